@@ -0,0 +1,17 @@
+package secretstorage
+
+func (ss *KeyringStorage[V]) withReadAliases(aliases func(key string) []string) {
+	ss.readAliases = aliases
+}
+
+// WithReadAliases makes Get fall back to the aliases of a key, such as its deprecated names, when the key is not found.
+// The aliases are tried in order, and the value of the first one that is found is returned. If none is found, the
+// error of the key is returned.
+//
+// The aliases are only read, Set and Delete operate on the key itself, so that the values move to the new names as
+// they are written.
+func WithReadAliases(aliases func(key string) []string) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withReadAliases(aliases)
+	})
+}
@@ -0,0 +1,70 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_ReadAliases(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	aliases := func(key string) []string {
+		if key == "token" {
+			return []string{"api-token", "legacy-token"}
+		}
+
+		return nil
+	}
+
+	old := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithReadAliases(aliases))
+
+	// The primary key is absent, the second alias resolves.
+	require.NoError(t, old.Set(t.Name(), "legacy-token", "legacy"))
+
+	actual, err := s.Get(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", actual)
+
+	// The first alias wins.
+	require.NoError(t, old.Set(t.Name(), "api-token", "api"))
+
+	actual, err = s.Get(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "api", actual)
+
+	// Set writes the canonical key only, and it takes precedence.
+	require.NoError(t, s.Set(t.Name(), "token", "new"))
+
+	actual, err = s.Get(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual)
+
+	assert.Len(t, k.entries(t.Name()), 3)
+
+	// Delete deletes the canonical key only.
+	require.NoError(t, s.Delete(t.Name(), "token"))
+
+	actual, err = s.Get(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "api", actual)
+}
+
+func TestKeyringStorage_ReadAliases_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithReadAliases(func(string) []string { return []string{"alias"} }),
+	)
+
+	_, err := s.Get(t.Name(), "key")
+
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	require.EqualError(t, err, "failed to read data from keyring: secret not found in keyring")
+}
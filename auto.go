@@ -0,0 +1,147 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrNoBackend indicates that none of the backends can be used in the current environment.
+var ErrNoBackend = errors.New("no backend available")
+
+// ciVariables are the environment variables that the CI services set.
+var ciVariables = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "CIRCLECI", "JENKINS_URL", "TF_BUILD"}
+
+// Probe detects whether a backend can be used in the current environment.
+type Probe struct {
+	// Name identifies the backend in the logs.
+	Name string
+	// Detect returns the keyring of the backend, or false if the backend cannot be used.
+	Detect func() (keyring.Keyring, bool)
+}
+
+// CIProbe selects an EnvKeyring with the given prefix when running in CI, which is detected with the environment
+// variables that the CI services set, such as CI or GITHUB_ACTIONS.
+func CIProbe(prefix string) Probe {
+	return Probe{
+		Name: "env",
+		Detect: func() (keyring.Keyring, bool) {
+			for _, v := range ciVariables {
+				if os.Getenv(v) != "" {
+					return NewEnvKeyring(prefix), true
+				}
+			}
+
+			return nil, false
+		},
+	}
+}
+
+// OSKeyringProbe selects the OS keyring when it responds.
+func OSKeyringProbe() Probe {
+	return Probe{
+		Name: "keyring",
+		Detect: func() (keyring.Keyring, bool) {
+			_, err := keyring.Get("go.nhat.io/secretstorage", "probe")
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, false
+			}
+
+			return defaultKeyring{}, true
+		},
+	}
+}
+
+// FileProbe selects a FileKeyring when the path is set and its directory exists, see NewFileKeyring.
+func FileProbe(path string, key []byte) Probe {
+	return Probe{
+		Name: "file",
+		Detect: func() (keyring.Keyring, bool) {
+			if path == "" {
+				return nil, false
+			}
+
+			if fi, err := os.Stat(filepath.Dir(path)); err != nil || !fi.IsDir() {
+				return nil, false
+			}
+
+			k, err := NewFileKeyring(path, key)
+			if err != nil {
+				return nil, false
+			}
+
+			return k, true
+		},
+	}
+}
+
+// AutoStorage creates a KeyringStorage with the first backend that can be used in the current environment. By
+// default, the environment variables are used in CI, see CIProbe, and the OS keyring otherwise. Use WithProbes to
+// change the backends and their order.
+func AutoStorage[V any](opts ...AutoStorageOption) (*KeyringStorage[V], error) {
+	cfg := autoStorageConfig{
+		probes: []Probe{CIProbe("SECRET_"), OSKeyringProbe()},
+		logger: slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt.applyAutoStorageOption(&cfg)
+	}
+
+	for _, p := range cfg.probes {
+		k, ok := p.Detect()
+		if !ok {
+			cfg.logger.Debug("secret storage backend is not available", "backend", p.Name)
+
+			continue
+		}
+
+		cfg.logger.Info("secret storage backend is selected", "backend", p.Name)
+
+		return NewKeyringStorage[V](append(cfg.storageOptions, WithKeyring(k))...), nil
+	}
+
+	return nil, fmt.Errorf("%w: tried %d backends", ErrNoBackend, len(cfg.probes))
+}
+
+type autoStorageConfig struct {
+	probes         []Probe
+	logger         *slog.Logger
+	storageOptions []KeyringStorageOption
+}
+
+// AutoStorageOption is an option to configure AutoStorage.
+type AutoStorageOption interface {
+	applyAutoStorageOption(cfg *autoStorageConfig)
+}
+
+type autoStorageOptionFunc func(cfg *autoStorageConfig)
+
+func (f autoStorageOptionFunc) applyAutoStorageOption(cfg *autoStorageConfig) {
+	f(cfg)
+}
+
+// WithProbes sets the backends to try, in order.
+func WithProbes(probes ...Probe) AutoStorageOption {
+	return autoStorageOptionFunc(func(cfg *autoStorageConfig) {
+		cfg.probes = probes
+	})
+}
+
+// WithAutoLogger sets the logger that logs the selected backend. The default is slog.Default().
+func WithAutoLogger(l *slog.Logger) AutoStorageOption {
+	return autoStorageOptionFunc(func(cfg *autoStorageConfig) {
+		cfg.logger = l
+	})
+}
+
+// WithStorageOptions sets the options of the created storage. The keyring is set to the one of the selected backend.
+func WithStorageOptions(opts ...KeyringStorageOption) AutoStorageOption {
+	return autoStorageOptionFunc(func(cfg *autoStorageConfig) {
+		cfg.storageOptions = append(cfg.storageOptions, opts...)
+	})
+}
@@ -0,0 +1,95 @@
+package secretstorage_test
+
+import (
+	"bytes"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestAutoStorage_ProbeOrder(t *testing.T) {
+	t.Parallel()
+
+	unavailable := secretstorage.Probe{
+		Name: "unavailable",
+		Detect: func() (keyring.Keyring, bool) {
+			return nil, false
+		},
+	}
+
+	k := newMemoryKeyring()
+
+	available := secretstorage.Probe{
+		Name: "memory",
+		Detect: func() (keyring.Keyring, bool) {
+			return k, true
+		},
+	}
+
+	var logs bytes.Buffer
+
+	s, err := secretstorage.AutoStorage[string](
+		secretstorage.WithProbes(unavailable, available),
+		secretstorage.WithAutoLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		secretstorage.WithStorageOptions(secretstorage.WithMaxLength(5)),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	assert.Len(t, k.entries(t.Name()), 4)
+	assert.Contains(t, logs.String(), `msg="secret storage backend is not available" backend=unavailable`)
+	assert.Contains(t, logs.String(), `msg="secret storage backend is selected" backend=memory`)
+}
+
+func TestAutoStorage_NoBackend(t *testing.T) {
+	t.Parallel()
+
+	s, err := secretstorage.AutoStorage[string](
+		secretstorage.WithProbes(secretstorage.FileProbe("", nil)),
+	)
+
+	require.EqualError(t, err, "no backend available: tried 1 backends")
+	require.ErrorIs(t, err, secretstorage.ErrNoBackend)
+	assert.Nil(t, s)
+}
+
+func TestAutoStorage_FileProbe(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{1}, 32)
+
+	s, err := secretstorage.AutoStorage[string](
+		secretstorage.WithProbes(
+			secretstorage.FileProbe(filepath.Join(dir, "missing", "secrets"), key),
+			secretstorage.FileProbe(filepath.Join(dir, "secrets"), key),
+		),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Set(t.Name(), "key", "value"))
+
+	assert.FileExists(t, filepath.Join(dir, "secrets"))
+}
+
+func TestAutoStorage_CIProbe(t *testing.T) { //nolint: paralleltest
+	t.Setenv("CI", "true")
+	t.Setenv("TEST_SERVICE_KEY", "value")
+
+	s, err := secretstorage.AutoStorage[string](
+		secretstorage.WithProbes(secretstorage.CIProbe("TEST_")),
+	)
+	require.NoError(t, err)
+
+	actual, err := s.Get("service", "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+}
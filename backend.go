@@ -0,0 +1,48 @@
+package secretstorage
+
+// BackendInfo describes the keyring that a KeyringStorage runs against, see KeyringStorage.Backend.
+type BackendInfo struct {
+	// Name is the name of the keyring, for example "macOS Keychain", or "unknown" for a custom keyring.
+	Name string
+	// MaxEntryLength is the known limit of the length of an entry, in bytes, 0 if there is no known limit. The limit may
+	// include the service and the key, the max length of the storage should be set lower, see WithMaxLength.
+	MaxEntryLength int
+	// Listing tells whether the keyring is able to enumerate the entries of a service, see Lister.
+	Listing bool
+	// Context tells whether the keyring supports the cancellation of its calls, see ContextKeyring.
+	Context bool
+	// Options tells whether the keyring accepts the options of its backend, see OptionsKeyring.
+	Options bool
+	// Watching tells whether the keyring is able to notify the changes of its entries. None of the keyrings supports
+	// watching yet, so it is always false.
+	Watching bool
+}
+
+const unknownBackend = "unknown"
+
+// Backend reports the keyring that the storage runs against, and its capabilities. The OS keyring is reported for the
+// platform the program runs on. The custom keyrings are reported as "unknown", with their capabilities.
+func (ss *KeyringStorage[V]) Backend() BackendInfo {
+	defer ss.rlockConfig()()
+
+	k := unwrapKeyring(ss.keyring)
+
+	info := BackendInfo{Name: unknownBackend}
+
+	switch k.(type) {
+	case defaultKeyring:
+		info = platformBackend()
+
+	case *FileKeyring:
+		info.Name = "file"
+
+	case *EnvKeyring:
+		info.Name = "environment"
+	}
+
+	_, info.Listing = k.(Lister)
+	_, info.Context = k.(ContextKeyring)
+	_, info.Options = k.(OptionsKeyring)
+
+	return info
+}
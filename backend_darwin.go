@@ -0,0 +1,9 @@
+//go:build darwin
+
+package secretstorage
+
+// platformBackend reports the macOS Keychain. The keyring passes the service, the key and the data to the security
+// command, which fails when they exceed about 3000 bytes together.
+func platformBackend() BackendInfo {
+	return BackendInfo{Name: "macOS Keychain", MaxEntryLength: 3000}
+}
@@ -0,0 +1,8 @@
+//go:build linux
+
+package secretstorage
+
+// platformBackend reports the Secret Service. There is no limit, but the performance suffers with the big entries.
+func platformBackend() BackendInfo {
+	return BackendInfo{Name: "Secret Service"}
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Backend_Platform(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string]()
+
+	expected := secretstorage.BackendInfo{Name: "Secret Service", Watching: false}
+
+	assert.Equal(t, expected, s.Backend())
+}
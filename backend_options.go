@@ -0,0 +1,36 @@
+package secretstorage
+
+import "context"
+
+// OptionsKeyring is an optional interface for keyrings that accept the options of their backend, that keyring.Keyring
+// is not able to express, such as the access control flags of a keychain item. When the keyring implements it, and the
+// context of a call carries backend options, see ContextWithBackendOptions, the calls of the storage to the keyring
+// go through the methods with options.
+type OptionsKeyring interface {
+	GetWithOptions(service, user string, opts any) (string, error)
+	SetWithOptions(service, user, password string, opts any) error
+	DeleteWithOptions(service, user string, opts any) error
+}
+
+type backendOptionsKey struct{}
+
+// ContextWithBackendOptions returns a copy of the context that carries the options of the backend. The options are
+// opaque to the storage, they are passed as is to the keyrings that implement OptionsKeyring, and are ignored by the
+// others. They apply to the calls of the context methods of the storage, such as SetContext, for example:
+//
+//	ctx = secretstorage.ContextWithBackendOptions(ctx, myKeychainOptions{RequireBiometry: true})
+//
+//	err := ss.SetContext(ctx, "service", "key", value)
+//
+// A keyring that implements both OptionsKeyring and ContextKeyring is called with the options, its calls are abandoned,
+// but not canceled, when the context is done.
+func ContextWithBackendOptions(ctx context.Context, opts any) context.Context {
+	return context.WithValue(ctx, backendOptionsKey{}, opts)
+}
+
+// backendOptions returns the options of the backend that the context carries, if any.
+func backendOptions(ctx context.Context) (any, bool) {
+	opts := ctx.Value(backendOptionsKey{})
+
+	return opts, opts != nil
+}
@@ -0,0 +1,160 @@
+package secretstorage_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+var _ secretstorage.OptionsKeyring = (*optionsKeyring)(nil)
+
+type keychainOptions struct {
+	RequireBiometry bool
+}
+
+type optionsCall struct {
+	op   string
+	user string
+	opts any
+}
+
+// optionsKeyring is an in-memory keyring that records the options of the backend that it receives.
+type optionsKeyring struct {
+	*memoryKeyring
+
+	mu    sync.Mutex
+	calls []optionsCall
+}
+
+func (k *optionsKeyring) record(op, user string, opts any) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.calls = append(k.calls, optionsCall{op: op, user: user, opts: opts})
+}
+
+func (k *optionsKeyring) recorded() []optionsCall {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return append([]optionsCall(nil), k.calls...)
+}
+
+func (k *optionsKeyring) GetWithOptions(service, user string, opts any) (string, error) {
+	k.record("get", user, opts)
+
+	return k.Get(service, user)
+}
+
+func (k *optionsKeyring) SetWithOptions(service, user, password string, opts any) error {
+	k.record("set", user, opts)
+
+	return k.Set(service, user, password)
+}
+
+func (k *optionsKeyring) DeleteWithOptions(service, user string, opts any) error {
+	k.record("delete", user, opts)
+
+	return k.Delete(service, user)
+}
+
+func newOptionsKeyring() *optionsKeyring {
+	return &optionsKeyring{memoryKeyring: newMemoryKeyring()}
+}
+
+func TestKeyringStorage_BackendOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+	}{
+		{
+			scenario: "keyring",
+		},
+		{
+			scenario: "guarded keyring",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCircuitBreaker(3, time.Second)},
+		},
+		{
+			scenario: "keyring with timeout",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithOperationTimeout(time.Second)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newOptionsKeyring()
+			s := secretstorage.NewKeyringStorage[string](append(tc.options, secretstorage.WithKeyring(k))...)
+
+			opts := keychainOptions{RequireBiometry: true}
+			ctx := secretstorage.ContextWithBackendOptions(context.Background(), opts)
+
+			require.NoError(t, s.SetContext(ctx, t.Name(), "key", "value"))
+
+			actual, err := s.GetContext(ctx, t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, "value", actual)
+
+			require.NoError(t, s.DeleteContext(ctx, t.Name(), "key"))
+
+			calls := k.recorded()
+			require.NotEmpty(t, calls)
+
+			ops := make(map[string]bool)
+
+			for _, c := range calls {
+				// The previous value of the key is deleted too, see Rotate.
+				assert.Contains(t, []string{"key", "key~previous"}, c.user)
+				assert.Equal(t, opts, c.opts)
+
+				ops[c.op] = true
+			}
+
+			assert.Equal(t, map[string]bool{"get": true, "set": true, "delete": true}, ops)
+		})
+	}
+}
+
+func TestKeyringStorage_BackendOptions_NoOptions(t *testing.T) {
+	t.Parallel()
+
+	k := newOptionsKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.SetContext(context.Background(), t.Name(), "key", "value"))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	assert.Empty(t, k.recorded())
+}
+
+func TestKeyringStorage_BackendOptions_Ignored(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	ctx := secretstorage.ContextWithBackendOptions(context.Background(), keychainOptions{RequireBiometry: true})
+
+	require.NoError(t, s.SetContext(ctx, t.Name(), "key", "value"))
+
+	actual, err := s.GetContext(ctx, t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	require.NoError(t, s.DeleteContext(ctx, t.Name(), "key"))
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !linux && !windows
+
+package secretstorage
+
+// platformBackend reports the Secret Service, that the keyring uses on the other unix systems.
+func platformBackend() BackendInfo {
+	return BackendInfo{Name: "Secret Service"}
+}
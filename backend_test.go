@@ -0,0 +1,61 @@
+package secretstorage_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_Backend(t *testing.T) {
+	t.Parallel()
+
+	fk, err := secretstorage.NewFileKeyring(filepath.Join(t.TempDir(), "secrets"), make([]byte, 32))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+		expected secretstorage.BackendInfo
+	}{
+		{
+			scenario: "custom keyring",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithKeyring(mock.NopKeyring(t))},
+			expected: secretstorage.BackendInfo{Name: "unknown", Watching: false},
+		},
+		{
+			scenario: "custom keyring with listing",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithKeyring(newMemoryKeyring())},
+			expected: secretstorage.BackendInfo{Name: "unknown", Listing: true},
+		},
+		{
+			scenario: "file keyring",
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithKeyring(fk),
+				secretstorage.WithMaxConcurrency(2),
+			},
+			expected: secretstorage.BackendInfo{Name: "file", Listing: true},
+		},
+		{
+			scenario: "environment keyring",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithKeyring(secretstorage.NewEnvKeyring("SECRET_"))},
+			expected: secretstorage.BackendInfo{Name: "environment"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewKeyringStorage[string](tc.options...)
+
+			assert.Equal(t, tc.expected, s.Backend())
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package secretstorage
+
+// platformBackend reports the Windows Credential Manager, which limits the credentials to 2560 bytes.
+func platformBackend() BackendInfo {
+	return BackendInfo{Name: "Windows Credential Manager", MaxEntryLength: 2560}
+}
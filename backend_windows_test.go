@@ -0,0 +1,21 @@
+//go:build windows
+
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Backend_Platform(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string]()
+
+	expected := secretstorage.BackendInfo{Name: "Windows Credential Manager", MaxEntryLength: 2560, Watching: false}
+
+	assert.Equal(t, expected, s.Backend())
+}
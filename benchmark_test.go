@@ -0,0 +1,152 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.nhat.io/secretstorage"
+)
+
+func benchmarkGet(b *testing.B, length int) {
+	b.Helper()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	value := randString(length)
+
+	if err := s.Set("service", "key", value); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Get("service", "key"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkSet(b *testing.B, length int) {
+	b.Helper()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	value := randString(length)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := s.Set("service", "key", value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGet_SinglePart(b *testing.B) {
+	benchmarkGet(b, 1000)
+}
+
+func BenchmarkGet_Multipart(b *testing.B) {
+	benchmarkGet(b, 10000)
+}
+
+func BenchmarkGet_Multipart_ManyPages(b *testing.B) {
+	benchmarkGet(b, 1000000)
+}
+
+func BenchmarkSet_SinglePart(b *testing.B) {
+	benchmarkSet(b, 1000)
+}
+
+func BenchmarkSet_Multipart(b *testing.B) {
+	benchmarkSet(b, 10000)
+}
+
+func BenchmarkGet_Multipart_PagesPerCall(b *testing.B) {
+	for _, n := range []int{1, 4, 16} {
+		n := n
+
+		b.Run(fmt.Sprintf("%d pages per call", n), func(b *testing.B) {
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(newConcurrencyKeyring(100*time.Microsecond)),
+				secretstorage.WithPagesPerCall(n),
+			)
+
+			if err := s.Set("service", "key", randString(30000)); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Get("service", "key"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGet_Multipart_ParallelReads(b *testing.B) {
+	for _, n := range []int{1, 8, 32} {
+		n := n
+
+		b.Run(fmt.Sprintf("%d parallel reads", n), func(b *testing.B) {
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(newConcurrencyKeyring(time.Millisecond)),
+				secretstorage.WithParallelReads(n),
+			)
+
+			// 64 pages.
+			if err := s.Set("service", "key", randString(64*2048)); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Get("service", "key"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDelete_SinglePart(b *testing.B) {
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newConcurrencyKeyring(100 * time.Microsecond)),
+	)
+
+	for _, tc := range []struct {
+		name   string
+		delete func(service, key string) error
+	}{
+		{name: "Delete", delete: s.Delete},
+		{name: "DeleteSingle", delete: s.DeleteSingle},
+	} {
+		tc := tc
+
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+
+				if err := s.Set("service", "key", "value"); err != nil {
+					b.Fatal(err)
+				}
+
+				b.StartTimer()
+
+				if err := tc.delete("service", "key"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
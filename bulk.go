@@ -0,0 +1,140 @@
+package secretstorage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// GetAll gets all the values of the service. The pages of the multipart values are reassembled and are not returned
+// as separate keys.
+//
+// By default, GetAll aborts at the first key that could not be read. With WithPartialBulkReads, the keys that could
+// not be read are skipped, and the partial result is returned together with the errors.
+//
+// The keyring must implement Lister, otherwise ErrListingNotSupported is returned.
+func (ss *KeyringStorage[V]) GetAll(service string) (map[string]V, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	keys, err := ss.listKeys(service)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]V, len(keys))
+
+	for _, key := range keys {
+		v, gErr := ss.getKey(service, key)
+		if gErr == nil {
+			result[key] = v
+
+			continue
+		}
+
+		gErr = fmt.Errorf("failed to get %q: %w", key, gErr)
+
+		if !ss.partialBulkReads {
+			return nil, gErr
+		}
+
+		err = multierr.Append(err, gErr)
+	}
+
+	return result, err
+}
+
+// VerifyAll reads and decodes every key of the service to detect the corrupt data, without returning the values. The
+// pages of the multipart values are checked for presence, and for the length when it is stored, see WithStoredLength.
+//
+// The result maps every key to the error of its verification, or to nil if the key is intact. The error is only
+// returned when the keys could not be listed. The keyring must implement Lister, otherwise ErrListingNotSupported is
+// returned.
+func (ss *KeyringStorage[V]) VerifyAll(service string) (map[string]error, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	keys, err := ss.listKeys(service)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]error, len(keys))
+
+	for _, key := range keys {
+		_, result[key] = ss.getKey(service, key)
+	}
+
+	return result, nil
+}
+
+// List lists the keys of the service, the pages of the multipart values are not listed. The keyring must implement
+// Lister, otherwise ErrListingNotSupported is returned.
+func (ss *KeyringStorage[V]) List(service string) ([]string, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	return ss.listKeys(service)
+}
+
+// listKeys lists the keys of the service, excluding the pages of the multipart values.
+func (ss *KeyringStorage[V]) listKeys(service string) ([]string, error) {
+	l, err := ss.lister()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := l.List(service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data in keyring: %w", err)
+	}
+
+	return logicalKeys(entries, ss.pageFormat), nil
+}
+
+// logicalKeys removes the pages and the previous slots from the entries. An entry is a page if it is formatted as a page
+// of another entry, the previous slots are reserved keys, see Rotate.
+func logicalKeys(entries []string, f pageFormat) []string {
+	exists := make(map[string]struct{}, len(entries))
+
+	for _, e := range entries {
+		exists[e] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(entries))
+
+	isPage := func(e string) bool {
+		key, _, ok := f.parse(e)
+		if !ok {
+			return false
+		}
+
+		if _, ok := exists[key]; ok {
+			return true
+		}
+
+		// The pages written with the atomic swap have a generation.
+		if i := strings.LastIndexByte(key, '~'); i >= 0 {
+			if _, err := strconv.Atoi(key[i+1:]); err == nil {
+				_, ok := exists[key[:i]]
+
+				return ok
+			}
+		}
+
+		return false
+	}
+
+	for _, e := range entries {
+		if !isPage(e) && !isPreviousKey(e) {
+			keys = append(keys, e)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,142 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_GetAll_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	actual, err := s.GetAll(t.Name())
+
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.Nil(t, actual)
+}
+
+func TestKeyringStorage_GetAll_Success(t *testing.T) {
+	t.Parallel()
+
+	single := randString(128)
+	multipart := randString(6139)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	require.NoError(t, s.Set(t.Name(), "single", single))
+	require.NoError(t, s.Set(t.Name(), "multipart", multipart))
+	require.NoError(t, s.Set(t.Name(), "not-a-page-0001", "value"))
+	require.NoError(t, s.Set("another service", "key", "value"))
+
+	actual, err := s.GetAll(t.Name())
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"single":          single,
+		"multipart":       multipart,
+		"not-a-page-0001": "value",
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestKeyringStorage_GetAll_Empty(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	actual, err := s.GetAll(t.Name())
+	require.NoError(t, err)
+
+	assert.Empty(t, actual)
+}
+
+func TestKeyringStorage_GetAll_Failure_Abort(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "a", "42"))
+	require.NoError(t, k.Set(t.Name(), "b", "value"))
+	require.NoError(t, k.Set(t.Name(), "c", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "d", "24"))
+
+	s := secretstorage.NewKeyringStorage[custom](secretstorage.WithKeyring(k))
+
+	actual, err := s.GetAll(t.Name())
+
+	require.EqualError(t, err, `failed to get "b": failed to unmarshal data read from keyring: strconv.Atoi: parsing "value": invalid syntax`)
+	assert.Nil(t, actual)
+}
+
+func TestKeyringStorage_GetAll_Failure_Partial(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "a", "42"))
+	require.NoError(t, k.Set(t.Name(), "b", "value"))
+	require.NoError(t, k.Set(t.Name(), "c", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "d", "24"))
+
+	s := secretstorage.NewKeyringStorage[custom](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithPartialBulkReads(),
+	)
+
+	actual, err := s.GetAll(t.Name())
+
+	expectedError := `failed to get "b": failed to unmarshal data read from keyring: strconv.Atoi: parsing "value": invalid syntax; ` +
+		`failed to get "c": failed to read multipart data #1 from keyring: secret not found in keyring`
+
+	require.EqualError(t, err, expectedError)
+
+	expected := map[string]custom{
+		"a": 42,
+		"d": 24,
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestKeyringStorage_VerifyAll_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	actual, err := s.VerifyAll(t.Name())
+
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.Nil(t, actual)
+}
+
+func TestKeyringStorage_VerifyAll(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[custom](secretstorage.WithKeyring(k), secretstorage.WithStoredLength())
+
+	require.NoError(t, s.Set(t.Name(), "single", 42))
+	require.NoError(t, k.Set(t.Name(), "invalid", "value"))
+	require.NoError(t, k.Set(t.Name(), "missing-page", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "missing-page-0001", "4"))
+	require.NoError(t, k.Set(t.Name(), "truncated", "application/multipart-secret; pages=2; length=4"))
+	require.NoError(t, k.Set(t.Name(), "truncated-0001", "12"))
+	require.NoError(t, k.Set(t.Name(), "truncated-0002", "3"))
+	require.NoError(t, k.Set("another service", "key", "value"))
+
+	actual, err := s.VerifyAll(t.Name())
+	require.NoError(t, err)
+
+	require.Len(t, actual, 4)
+	require.NoError(t, actual["single"])
+	require.EqualError(t, actual["invalid"], `failed to unmarshal data read from keyring: strconv.Atoi: parsing "value": invalid syntax`)
+	require.EqualError(t, actual["missing-page"], "failed to read multipart data #2 from keyring: secret not found in keyring")
+	require.ErrorIs(t, actual["truncated"], secretstorage.ErrCorruptSecret)
+}
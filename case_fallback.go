@@ -0,0 +1,30 @@
+package secretstorage
+
+import "strings"
+
+func (ss *KeyringStorage[V]) withCaseInsensitiveFallback() {
+	ss.caseFallback = true
+}
+
+// WithCaseInsensitiveFallback makes Get retry with a single variant of the key in another case when the key is not
+// found, for the keys that are written with another casing, such as by a legacy tool. The variant is the key in upper
+// case, or in lower case if the key is already in upper case. The variant that matches is logged at the debug level
+// with slog.
+//
+// The variant is tried before the aliases, see WithReadAliases. Set and Delete operate on the key itself.
+func WithCaseInsensitiveFallback() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withCaseInsensitiveFallback()
+	})
+}
+
+// caseVariant returns the key in upper case, or in lower case if it is already in upper case. The boolean is false if
+// the key has no case.
+func caseVariant(key string) (string, bool) {
+	variant := strings.ToUpper(key)
+	if variant == key {
+		variant = strings.ToLower(key)
+	}
+
+	return variant, variant != key
+}
@@ -0,0 +1,59 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_CaseInsensitiveFallback(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	legacy := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithCaseInsensitiveFallback())
+
+	require.NoError(t, legacy.Set(t.Name(), "API-TOKEN", "legacy"))
+	require.NoError(t, legacy.Set(t.Name(), "ApiKey", "mixed"))
+
+	// The upper case variant is found.
+	actual, err := s.Get(t.Name(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", actual)
+
+	// Only a single variant is tried.
+	_, err = s.Get(t.Name(), "apikey")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	_, err = s.Get(t.Name(), "Api-Token")
+	require.NoError(t, err)
+
+	// The exact key takes precedence.
+	require.NoError(t, s.Set(t.Name(), "api-token", "new"))
+
+	actual, err = s.Get(t.Name(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual)
+
+	// The lower case variant is tried for the keys in upper case.
+	require.NoError(t, legacy.Delete(t.Name(), "API-TOKEN"))
+
+	actual, err = s.Get(t.Name(), "API-TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual)
+}
+
+func TestKeyringStorage_CaseInsensitiveFallback_Disabled(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "API-TOKEN", "legacy"))
+
+	_, err := s.Get(t.Name(), "api-token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
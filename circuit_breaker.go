@@ -0,0 +1,80 @@
+package secretstorage
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen indicates that the circuit breaker is open and the keyring is not called.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker opens after a number of consecutive failures and rejects all the calls until the cooldown is over.
+// After that, it lets a single trial call through to decide whether it should close or stay open for another cooldown,
+// the other calls are rejected until the trial call is done.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     Clock
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if b.trial || b.clock.Now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+
+	b.trial = true
+
+	return nil
+}
+
+func (b *circuitBreaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if err == nil || errors.Is(err, ErrNotFound) {
+		b.failures = 0
+
+		return
+	}
+
+	b.failures++
+
+	if b.failures >= b.threshold {
+		b.openUntil = b.clock.Now().Add(b.cooldown)
+	}
+}
+
+// reset closes the breaker.
+func (b *circuitBreaker) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.trial = false
+}
+
+// WithCircuitBreaker stops calling the keyring for the cooldown duration after a number of consecutive failures, the
+// calls fail with ErrCircuitOpen instead. Once the cooldown is over, a single trial call closes the breaker if it
+// succeeds, or opens it again if it fails, the concurrent calls still fail with ErrCircuitOpen meanwhile. Not found
+// errors are not considered as failures.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withCircuitBreaker(threshold, cooldown)
+	})
+}
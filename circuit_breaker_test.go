@@ -0,0 +1,210 @@
+package secretstorage_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_CircuitBreaker_OpenAndClose(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").Twice().
+			Return("", assert.AnError)
+
+		k.On("Get", t.Name(), "key").Twice().
+			Return("value", nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithClock(clock),
+		secretstorage.WithCircuitBreaker(2, time.Minute),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := s.Get(t.Name(), "key")
+		require.ErrorIs(t, err, assert.AnError)
+	}
+
+	// The breaker is open, the keyring is not called.
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+
+	clock.Add(59 * time.Second)
+
+	_, err = s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+
+	// The cooldown is over, the trial call closes the breaker.
+	clock.Add(time.Second)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	actual, err = s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_CircuitBreaker_FailedTrialCall(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Delete", t.Name(), "key").Twice().
+			Return(assert.AnError)
+
+		k.On("Delete", t.Name(), "key").Once().
+			Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithClock(clock),
+		secretstorage.WithCircuitBreaker(1, time.Minute),
+	)
+
+	k.On("Get", t.Name(), "key").Return("value", nil)
+	k.On("Get", t.Name(), "key~previous").Return("", secretstorage.ErrNotFound)
+
+	err := s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, assert.AnError)
+
+	err = s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+
+	clock.Add(time.Minute)
+
+	// The trial call fails, the breaker opens again.
+	err = s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, assert.AnError)
+
+	err = s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+
+	clock.Add(time.Minute)
+
+	err = s.Delete(t.Name(), "key")
+	require.NoError(t, err)
+}
+
+func TestKeyringStorage_CircuitBreaker_NotFoundIsNotAFailure(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithClock(newFakeClock()),
+		secretstorage.WithCircuitBreaker(1, time.Minute),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Get(t.Name(), "key")
+		require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	}
+}
+
+func TestKeyringStorage_CircuitBreaker_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(mock.NopKeyring(t)),
+		secretstorage.WithCircuitBreaker(1, time.Minute),
+	)
+
+	_, err := s.GetAll(t.Name())
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func TestKeyringStorage_CircuitBreaker_SingleTrialCall(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").Once().
+			Return("", assert.AnError)
+
+		k.On("Get", t.Name(), "key").Once().
+			Run(func(mock.Arguments) {
+				close(started)
+				<-release
+			}).
+			Return("value", nil)
+
+		k.On("Get", t.Name(), "other").Once().
+			Return("other value", nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithClock(clock),
+		secretstorage.WithCircuitBreaker(1, time.Minute),
+	)
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, assert.AnError)
+
+	clock.Add(time.Minute)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		actual, err := s.Get(t.Name(), "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", actual)
+	}()
+
+	<-started
+
+	// The trial call is in progress, the other calls are rejected.
+	_, err = s.Get(t.Name(), "other")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+
+	close(release)
+	wg.Wait()
+
+	actual, err := s.Get(t.Name(), "other")
+	require.NoError(t, err)
+	assert.Equal(t, "other value", actual)
+}
@@ -0,0 +1,23 @@
+package secretstorage
+
+import "time"
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+var _ Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock sets the clock to use. It is useful for testing the time-based features.
+func WithClock(c Clock) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withClock(c)
+	})
+}
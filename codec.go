@@ -0,0 +1,359 @@
+package secretstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"reflect"
+	"strings"
+)
+
+// ErrCodecMismatch indicates that the data was encoded with another codec than the configured one, and that none of
+// the legacy codecs is able to decode it.
+var ErrCodecMismatch = errors.New("codec mismatch")
+
+const (
+	mimeEncodedSecret = "application/encoded-secret"
+	textCodecName     = "text"
+)
+
+// Codec encodes the values for storing them in the keyring, and decodes them back.
+type Codec interface {
+	// Name identifies the codec, it is recorded together with the data. The name should change when the encoding
+	// changes in an incompatible way, for example "json/v2".
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, dest any) error
+}
+
+var (
+	_ Codec = TextCodec{}
+	_ Codec = JSONCodec{}
+)
+
+// TextCodec is the default codec. It supports strings, byte slices, and the types defined on them such as the enums,
+// url.Values (form-encoded), encoding.TextMarshaler and encoding.TextUnmarshaler, driver.Valuer and sql.Scanner, and the
+// pointers to them. For compatibility, the codec is not recorded with the data that it encodes.
+type TextCodec struct{}
+
+// Name returns "text".
+func (TextCodec) Name() string {
+	return textCodecName
+}
+
+// Marshal marshals the value.
+func (TextCodec) Marshal(v any) ([]byte, error) {
+	d, err := marshalData(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(d), nil
+}
+
+// Unmarshal unmarshals the data into the destination.
+func (TextCodec) Unmarshal(data []byte, dest any) error {
+	return unmarshalData(string(data), dest)
+}
+
+// JSONCodec encodes the values with encoding/json.
+type JSONCodec struct{}
+
+// Name returns "json".
+func (JSONCodec) Name() string {
+	return "json"
+}
+
+// Marshal marshals the value.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v) //nolint: wrapcheck
+}
+
+// Unmarshal unmarshals the data into the destination.
+func (JSONCodec) Unmarshal(data []byte, dest any) error {
+	return json.Unmarshal(data, dest) //nolint: wrapcheck
+}
+
+func (ss *KeyringStorage[V]) withCodec(c Codec) {
+	ss.codec = c
+}
+
+func (ss *KeyringStorage[V]) withLegacyCodecs(codecs ...Codec) {
+	ss.legacyCodecs = append(ss.legacyCodecs, codecs...)
+}
+
+func (ss *KeyringStorage[V]) withForceCodec() {
+	ss.forceCodec = true
+}
+
+// WithCodec sets the codec that encodes the values. The name of the codec is recorded together with the data, so that
+// the data encoded with another codec is detected when it is read, see WithLegacyCodecs.
+func WithCodec(c Codec) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withCodec(c)
+	})
+}
+
+// WithLegacyCodecs registers the codecs that were used before the current one. When the data was encoded with one of
+// them, it is decoded with that codec instead of failing with ErrCodecMismatch. The data is encoded with the current
+// codec the next time it is written.
+func WithLegacyCodecs(codecs ...Codec) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withLegacyCodecs(codecs...)
+	})
+}
+
+// WithForceCodec keeps the codec even if it conflicts with the type of the values, for example JSONCodec on a
+// Storage[string], which stores the strings JSON-quoted instead of as is. Without it, the writes fail with
+// ErrInvalidOption, and so does Reconfigure.
+func WithForceCodec() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withForceCodec()
+	})
+}
+
+// checkCodec fails if the codec encodes the values differently from the text codec, while the text codec already
+// stores them as is, such as JSONCodec on the strings and the byte slices. This is unlikely what the user expects.
+func (ss *KeyringStorage[V]) checkCodec() error {
+	if ss.forceCodec {
+		return nil
+	}
+
+	switch ss.codec.(type) {
+	case JSONCodec, *JSONCodec:
+	default:
+		return nil
+	}
+
+	t := reflect.TypeOf((*V)(nil)).Elem()
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8) {
+		return fmt.Errorf("%w: codec %q quotes the %s values, use WithForceCodec to keep it",
+			ErrInvalidOption, ss.codec.Name(), t)
+	}
+
+	return nil
+}
+
+// encode marshals the value with the codec, compresses and encrypts it if configured, and records the codec and these
+// steps in front of the data unless the data is marshaled with the text codec only. It fails if the codec conflicts
+// with the type of the values, see WithForceCodec.
+func (ss *KeyringStorage[V]) encode(service string, key string, v V) (string, error) {
+	if err := ss.checkCodec(); err != nil {
+		return "", err
+	}
+
+	// The text codec marshals to a string, without the round trip through bytes.
+	if _, ok := ss.codec.(TextCodec); ok && !ss.sealed() {
+		if ss.isNilSlice(v) {
+			return mimeNilSecret, nil
+		}
+
+		d, err := marshalData(v)
+		if err != nil {
+			return "", err
+		}
+
+		if ss.rejectEmpty && (d == "" || d == mimeEmptySecret) {
+			return "", ErrEmptyMarshal
+		}
+
+		return escape(d), nil
+	}
+
+	b, err := ss.marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	// The text codec stores a sentinel in place of an empty TextMarshaler output.
+	if ss.rejectEmpty && (len(b) == 0 || string(b) == mimeEmptySecret) {
+		return "", ErrEmptyMarshal
+	}
+
+	return ss.encodeBytes(service, key, b, ss.codec.Name())
+}
+
+// encodeBytes compresses and encrypts the marshaled data if configured, and records the codec and these steps in front
+// of the data unless the data is marshaled with the text codec only.
+func (ss *KeyringStorage[V]) encodeBytes(service string, key string, b []byte, codec string) (string, error) {
+	params := map[string]string{"codec": codec}
+
+	if ss.sealed() {
+		var err error
+
+		if b, err = ss.seal(service, key, b, params); err != nil {
+			return "", err
+		}
+	}
+
+	// The text codec is not recorded when the data is stored as is.
+	if len(params) == 1 && codec == textCodecName {
+		return escape(string(b)), nil
+	}
+
+	return mime.FormatMediaType(mimeEncodedSecret, params) + "\n" + string(b), nil
+}
+
+// decode finds the codec that encoded the data and unmarshals the data with it.
+func (ss *KeyringStorage[V]) decode(service string, key string, d string, dest *V) error {
+	_, err := ss.decodeHeader(service, key, d, dest)
+
+	return err
+}
+
+// decodeHeader is like decode, and also returns the parameters of the header that records the codec, if any.
+func (ss *KeyringStorage[V]) decodeHeader(service string, key string, d string, dest *V) (map[string]string, error) {
+	params, d, err := parseEncodedData(d)
+	if err != nil {
+		return nil, err
+	}
+
+	name := textCodecName
+
+	if params != nil {
+		name = params["codec"]
+	}
+
+	c, err := ss.codecByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if d, err = ss.unseal(service, key, d, params); err != nil {
+		return nil, err
+	}
+
+	// The text codec unmarshals the string as is, without the round trip through bytes.
+	if _, ok := c.(TextCodec); ok {
+		if ss.nilSlices && d == mimeNilSecret && isByteSlice(reflect.ValueOf(dest).Elem()) {
+			var zero V
+
+			*dest = zero
+
+			return params, nil
+		}
+
+		return params, unmarshalData(d, dest)
+	}
+
+	if err := c.Unmarshal([]byte(d), dest); err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+
+	return params, nil
+}
+
+// decodeBytes reverses encodeBytes, and returns the marshaled data without unmarshaling it, whatever its codec.
+func (ss *KeyringStorage[V]) decodeBytes(service string, key string, d string) (string, error) {
+	params, d, err := parseEncodedData(d)
+	if err != nil {
+		return "", err
+	}
+
+	return ss.unseal(service, key, d, params)
+}
+
+func (ss *KeyringStorage[V]) codecByName(name string) (Codec, error) {
+	if ss.codec.Name() == name {
+		return ss.codec, nil
+	}
+
+	for _, c := range ss.legacyCodecs {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: data is encoded with %q, expected %q", ErrCodecMismatch, name, ss.codec.Name())
+}
+
+// escapedHeader records the text codec in front of the data that is escaped.
+var escapedHeader = mime.FormatMediaType(mimeEncodedSecret, map[string]string{"codec": textCodecName}) + "\n"
+
+// escape records the text codec in front of the data that is stored as is, if the data starts like one of the headers
+// of the storage, so that a value such as "application/secret-reference; ..." is not read as a reference to another
+// key. The data is decoded as is, see unescape.
+func escape(d string) string {
+	if !isReserved(d) {
+		return d
+	}
+
+	return escapedHeader + d
+}
+
+// unescape returns the data that is escaped as is, see escape.
+func unescape(d string) string {
+	if e, ok := strings.CutPrefix(d, escapedHeader); ok {
+		return e
+	}
+
+	return d
+}
+
+// isReserved tells whether the data starts like one of the headers of the storage.
+func isReserved(d string) bool {
+	if _, ok := storedFormats.lookup(d); ok {
+		return true
+	}
+
+	return strings.HasPrefix(d, mimeEncodedSecret) || strings.HasPrefix(d, mimePreviousSecret)
+}
+
+// escapeLiteral records the text codec in front of the marshaled data that reads like a sentinel, such as the string
+// "application/nil-secret", so that it is not read back as a nil or an empty value. The data that is already escaped
+// this way is escaped again. The data is read back as is, see unescapeLiteral.
+func escapeLiteral(d string) string {
+	if !isEscapedSentinel(d) {
+		return d
+	}
+
+	return escapedHeader + d
+}
+
+// unescapeLiteral returns the data that is escaped with escapeLiteral as is. The sentinels are returned unchanged.
+func unescapeLiteral(d string) string {
+	if !isEscapedSentinel(d) {
+		return d
+	}
+
+	return unescape(d)
+}
+
+// isEscapedSentinel tells whether the data is a sentinel, escaped any number of times.
+func isEscapedSentinel(d string) bool {
+	for {
+		e, ok := strings.CutPrefix(d, escapedHeader)
+		if !ok {
+			return d == mimeNilSecret || d == mimeEmptySecret
+		}
+
+		d = e
+	}
+}
+
+// parseEncodedData returns the parameters of the header that records the codec, and the encoded data. The parameters
+// are nil if the codec is not recorded.
+func parseEncodedData(d string) (map[string]string, string, error) {
+	if !strings.HasPrefix(d, mimeEncodedSecret) {
+		return nil, d, nil
+	}
+
+	header, data, _ := strings.Cut(d, "\n")
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, "", &headerError{field: "params", err: err}
+	}
+
+	if _, ok := params["codec"]; !ok {
+		return nil, "", &headerError{field: "codec", err: errors.New("missing parameter")} //nolint: goerr113
+	}
+
+	return params, data, nil
+}
@@ -0,0 +1,378 @@
+package secretstorage_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+type jsonSecret struct {
+	Token string `json:"token"`
+}
+
+func TestKeyringStorage_JSONCodec(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[jsonSecret](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+	)
+
+	err := s.Set(t.Name(), "key", jsonSecret{Token: "secret"})
+	require.NoError(t, err)
+
+	stored, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/encoded-secret; codec=json\n{\"token\":\"secret\"}", stored)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonSecret{Token: "secret"}, actual)
+}
+
+func TestKeyringStorage_JSONCodec_Multipart(t *testing.T) {
+	t.Parallel()
+
+	expected := jsonSecret{Token: randString(6139)}
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[jsonSecret](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+	)
+
+	err := s.Set(t.Name(), "key", expected)
+	require.NoError(t, err)
+
+	stored, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/multipart-secret; pages=4", stored)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestKeyringStorage_TextCodec_NotRecorded(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.TextCodec{}),
+	)
+
+	err := s.Set(t.Name(), "key", "secret")
+	require.NoError(t, err)
+
+	stored, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", stored)
+}
+
+func TestKeyringStorage_CodecMismatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		stored        string
+		codec         secretstorage.Codec
+		legacyCodecs  []secretstorage.Codec
+		expected      string
+		expectedError string
+	}{
+		{
+			scenario:      "text data read with json codec",
+			stored:        `{"token":"secret"}`,
+			codec:         secretstorage.JSONCodec{},
+			expectedError: `failed to unmarshal data read from keyring: codec mismatch: data is encoded with "text", expected "json"`,
+		},
+		{
+			scenario:      "json data read with text codec",
+			stored:        "application/encoded-secret; codec=json\n\"secret\"",
+			codec:         secretstorage.TextCodec{},
+			expectedError: `failed to unmarshal data read from keyring: codec mismatch: data is encoded with "json", expected "text"`,
+		},
+		{
+			scenario:     "text data read with legacy text codec",
+			stored:       "secret",
+			codec:        secretstorage.JSONCodec{},
+			legacyCodecs: []secretstorage.Codec{secretstorage.TextCodec{}},
+			expected:     "secret",
+		},
+		{
+			scenario:     "json data read with legacy json codec",
+			stored:       "application/encoded-secret; codec=json\n\"secret\"",
+			codec:        secretstorage.TextCodec{},
+			legacyCodecs: []secretstorage.Codec{secretstorage.JSONCodec{}},
+			expected:     "secret",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+
+			require.NoError(t, k.Set(t.Name(), "key", tc.stored))
+
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(k),
+				secretstorage.WithCodec(tc.codec),
+				secretstorage.WithLegacyCodecs(tc.legacyCodecs...),
+			)
+
+			actual, err := s.Get(t.Name(), "key")
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+				require.ErrorIs(t, err, secretstorage.ErrCodecMismatch)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_CodecMissing(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/encoded-secret; version=1\nsecret"))
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+	)
+
+	actual, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: failed to get codec from data: missing parameter")
+	assert.Empty(t, actual)
+}
+
+func TestKeyringStorage_LegacyCodec_RewrittenWithCurrentCodec(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "secret"))
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+		secretstorage.WithLegacyCodecs(secretstorage.TextCodec{}),
+		secretstorage.WithForceCodec(),
+	)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	require.NoError(t, s.Set(t.Name(), "key", actual))
+
+	stored, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/encoded-secret; codec=json\n\"secret\"", stored)
+}
+
+func TestKeyringStorage_CodecConflict(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+	)
+
+	err := s.Set(t.Name(), "key", "p4ssw0rd")
+
+	require.EqualError(t, err, `failed to marshal data for writing to keyring: invalid option: codec "json" quotes the string values, use WithForceCodec to keep it`)
+	require.ErrorIs(t, err, secretstorage.ErrInvalidOption)
+	assert.Empty(t, k.entries(t.Name()))
+
+	b := secretstorage.NewKeyringStorage[[]byte](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+	)
+
+	err = b.Set(t.Name(), "key", []byte("p4ssw0rd"))
+
+	require.ErrorIs(t, err, secretstorage.ErrInvalidOption)
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_ForceCodec(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+		secretstorage.WithForceCodec(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	stored, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/encoded-secret; codec=json\n\"p4ssw0rd\"", stored)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestKeyringStorage_ReservedValues(t *testing.T) {
+	t.Parallel()
+
+	values := []string{
+		"application/secret-reference; key=other; service=other",
+		"application/multipart-secret; pages=2",
+		"application/labeled-secret; label-env=prod\nvalue",
+		"application/deleted-secret; deleted=2024-01-01T00:00:00Z\nvalue",
+		"application/encoded-secret; codec=json\n\"value\"",
+		"application/previous-secret; expires=2024-01-01T00:00:00Z\nvalue",
+		"application/nil-secret",
+		"application/empty-secret",
+		"application/encoded-secret; codec=text\napplication/nil-secret",
+	}
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+	}{
+		{
+			scenario: "single entry",
+		},
+		{
+			scenario: "multipart",
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithMaxLength(10),
+				secretstorage.WithStoredLength(),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](append(tc.options, secretstorage.WithKeyring(k))...)
+
+			require.NoError(t, s.Set(t.Name(), "other", "the other secret"))
+
+			for _, v := range values {
+				require.NoError(t, s.Set(t.Name(), "key", v))
+
+				actual, err := s.Get(t.Name(), "key")
+				require.NoError(t, err, v)
+				assert.Equal(t, v, actual)
+
+				r, err := s.GetReader(t.Name(), "key")
+				require.NoError(t, err, v)
+
+				b, err := io.ReadAll(r)
+				require.NoError(t, err, v)
+				require.NoError(t, r.Close())
+				assert.Equal(t, v, string(b))
+
+				require.NoError(t, s.SetReader(t.Name(), "key", strings.NewReader(v)))
+
+				actual, err = s.Get(t.Name(), "key")
+				require.NoError(t, err, v)
+				assert.Equal(t, v, actual)
+			}
+		})
+	}
+}
+
+func TestKeyringStorage_SentinelValues(t *testing.T) {
+	t.Parallel()
+
+	values := []string{
+		"application/nil-secret",
+		"application/empty-secret",
+		"application/encoded-secret; codec=text\napplication/nil-secret",
+		"application/encoded-secret; codec=text\napplication/encoded-secret; codec=text\napplication/empty-secret",
+	}
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+	}{
+		{
+			scenario: "plain",
+		},
+		{
+			scenario: "encrypted",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithEncryption(encryptionKey)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ptrs := secretstorage.NewKeyringStorage[*string](append(tc.options, secretstorage.WithKeyring(newMemoryKeyring()))...)
+			slices := secretstorage.NewKeyringStorage[[]byte](append(tc.options,
+				secretstorage.WithKeyring(newMemoryKeyring()),
+				secretstorage.WithNilSlices(),
+			)...)
+
+			for _, v := range values {
+				v := v
+
+				require.NoError(t, ptrs.Set(t.Name(), "key", &v))
+
+				p, err := ptrs.Get(t.Name(), "key")
+				require.NoError(t, err, v)
+				require.NotNil(t, p, v)
+				assert.Equal(t, v, *p)
+
+				require.NoError(t, slices.Set(t.Name(), "key", []byte(v)))
+
+				b, err := slices.Get(t.Name(), "key")
+				require.NoError(t, err, v)
+				assert.Equal(t, []byte(v), b)
+			}
+
+			// The nil values are still stored as the sentinel.
+			require.NoError(t, ptrs.Set(t.Name(), "key", nil))
+
+			p, err := ptrs.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Nil(t, p)
+
+			require.NoError(t, slices.Set(t.Name(), "key", nil))
+
+			b, err := slices.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Nil(t, b)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	k := newConcurrencyKeyring(time.Millisecond)
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxConcurrency(3),
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, s.Set(t.Name(), fmt.Sprintf("key-%d", i), randString(5000)))
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, k.maxConcurrentCalls(), int64(3))
+
+	entries, err := k.List(t.Name())
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 30*4)
+}
+
+func TestKeyringStorage_ServiceLock(t *testing.T) {
+	t.Parallel()
+
+	k := newConcurrencyKeyring(100 * time.Microsecond)
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithServiceLock(),
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+
+			assert.NoError(t, s.Set(t.Name(), key, randString(5000)))
+
+			_, err := s.Get(t.Name(), key)
+			assert.NoError(t, err)
+
+			assert.NoError(t, s.Delete(t.Name(), key))
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int64(1), k.maxConcurrentCalls())
+}
+
+// concurrencyKeyring is a memoryKeyring that records the maximum number of concurrent calls.
+type concurrencyKeyring struct {
+	*memoryKeyring
+
+	latency time.Duration
+	current atomic.Int64
+	max     atomic.Int64
+}
+
+func (k *concurrencyKeyring) track() func() {
+	current := k.current.Add(1)
+
+	for {
+		m := k.max.Load()
+		if current <= m || k.max.CompareAndSwap(m, current) {
+			break
+		}
+	}
+
+	time.Sleep(k.latency)
+
+	return func() {
+		k.current.Add(-1)
+	}
+}
+
+func (k *concurrencyKeyring) Set(service, user, password string) error {
+	defer k.track()()
+
+	return k.memoryKeyring.Set(service, user, password)
+}
+
+func (k *concurrencyKeyring) Get(service, user string) (string, error) {
+	defer k.track()()
+
+	return k.memoryKeyring.Get(service, user)
+}
+
+func (k *concurrencyKeyring) Delete(service, user string) error {
+	defer k.track()()
+
+	return k.memoryKeyring.Delete(service, user)
+}
+
+func (k *concurrencyKeyring) maxConcurrentCalls() int64 {
+	return k.max.Load()
+}
+
+func newConcurrencyKeyring(latency time.Duration) *concurrencyKeyring {
+	return &concurrencyKeyring{
+		memoryKeyring: newMemoryKeyring(),
+		latency:       latency,
+	}
+}
+
+func TestKeyringStorage_Locks_NoCollision(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithMaxLength(5),
+	)
+
+	require.NoError(t, s.Set("a:b", "c", "hello world"))
+
+	// The reader of a multipart value holds the lock of its key until it is closed.
+	r, err := s.GetReader("a:b", "c")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		assert.NoError(t, s.Set("a", "b:c", "value"))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the key shares the lock of another key")
+	}
+
+	blocked := make(chan struct{})
+
+	go func() {
+		defer close(blocked)
+
+		assert.NoError(t, s.Set("a:b", "c", "value"))
+	}()
+
+	select {
+	case <-blocked:
+		t.Fatal("the key is not locked")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	require.NoError(t, r.Close())
+
+	<-blocked
+}
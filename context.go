@@ -0,0 +1,192 @@
+package secretstorage
+
+import (
+	"context"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ContextKeyring is an optional interface for keyrings that support context natively. When the keyring implements
+// it, the context methods of the storage, such as GetContext, call the context variants of the keyring.
+type ContextKeyring interface {
+	GetContext(ctx context.Context, service, user string) (string, error)
+	SetContext(ctx context.Context, service, user, password string) error
+	DeleteContext(ctx context.Context, service, user string) error
+}
+
+// GetContext is like Get, and stops waiting for the keyring when the context is done.
+func (ss *KeyringStorage[V]) GetContext(ctx context.Context, service string, key string) (V, error) {
+	return ss.withContext(ctx).Get(service, key)
+}
+
+// SetContext is like Set, and stops waiting for the keyring when the context is done.
+func (ss *KeyringStorage[V]) SetContext(ctx context.Context, service string, key string, value V) error {
+	return ss.withContext(ctx).Set(service, key, value)
+}
+
+// DeleteContext is like Delete, and stops waiting for the keyring when the context is done.
+func (ss *KeyringStorage[V]) DeleteContext(ctx context.Context, service string, key string) error {
+	return ss.withContext(ctx).Delete(service, key)
+}
+
+// withContext returns a copy of the storage that shares the configuration and the locks, and passes the context to
+// the keyring.
+func (ss *KeyringStorage[V]) withContext(ctx context.Context) *KeyringStorage[V] {
+	ss.configMu.RLock()
+	c := *ss
+	ss.configMu.RUnlock()
+
+	// The keyring is read from the copy, the one of the storage could be replaced by Reconfigure meanwhile.
+	if g, ok := c.keyring.(*guardedKeyring); ok {
+		guarded := *g
+		guarded.Keyring = contextKeyring{ctx: ctx, Keyring: g.Keyring}
+
+		c.keyring = &guarded
+	} else {
+		c.keyring = contextKeyring{ctx: ctx, Keyring: c.keyring}
+	}
+
+	return &c
+}
+
+var (
+	_ keyring.Keyring = (*contextKeyring)(nil)
+	_ Lister          = (*contextKeyring)(nil)
+	_ ServiceLister   = (*contextKeyring)(nil)
+)
+
+// contextKeyring passes the context to the keyring if it implements ContextKeyring, or the options of the backend that
+// the context carries if it implements OptionsKeyring. Otherwise, the calls run in a goroutine, and are abandoned, but
+// not canceled, when the context is done.
+type contextKeyring struct {
+	keyring.Keyring
+
+	ctx context.Context //nolint: containedctx
+}
+
+// optionsKeyring returns the keyring with the options of the backend, if the context carries them and the keyring
+// accepts them.
+func (k contextKeyring) optionsKeyring() (OptionsKeyring, any, bool) {
+	ok, isOptionsKeyring := k.Keyring.(OptionsKeyring)
+	if !isOptionsKeyring {
+		return nil, nil, false
+	}
+
+	opts, hasOptions := backendOptions(k.ctx)
+
+	return ok, opts, hasOptions
+}
+
+func (k contextKeyring) Get(service, user string) (string, error) {
+	if ok, opts, found := k.optionsKeyring(); found {
+		return runContext(k.ctx, func() (string, error) {
+			return ok.GetWithOptions(service, user, opts)
+		})
+	}
+
+	if ck, ok := k.Keyring.(ContextKeyring); ok {
+		return ck.GetContext(k.ctx, service, user) //nolint: wrapcheck
+	}
+
+	return runContext(k.ctx, func() (string, error) {
+		return k.Keyring.Get(service, user)
+	})
+}
+
+func (k contextKeyring) Set(service, user, password string) error {
+	if ok, opts, found := k.optionsKeyring(); found {
+		_, err := runContext(k.ctx, func() (struct{}, error) {
+			return struct{}{}, ok.SetWithOptions(service, user, password, opts)
+		})
+
+		return err
+	}
+
+	if ck, ok := k.Keyring.(ContextKeyring); ok {
+		return ck.SetContext(k.ctx, service, user, password) //nolint: wrapcheck
+	}
+
+	_, err := runContext(k.ctx, func() (struct{}, error) {
+		return struct{}{}, k.Keyring.Set(service, user, password)
+	})
+
+	return err
+}
+
+func (k contextKeyring) Delete(service, user string) error {
+	if ok, opts, found := k.optionsKeyring(); found {
+		_, err := runContext(k.ctx, func() (struct{}, error) {
+			return struct{}{}, ok.DeleteWithOptions(service, user, opts)
+		})
+
+		return err
+	}
+
+	if ck, ok := k.Keyring.(ContextKeyring); ok {
+		return ck.DeleteContext(k.ctx, service, user) //nolint: wrapcheck
+	}
+
+	_, err := runContext(k.ctx, func() (struct{}, error) {
+		return struct{}{}, k.Keyring.Delete(service, user)
+	})
+
+	return err
+}
+
+func (k contextKeyring) DeleteAll(service string) error {
+	_, err := runContext(k.ctx, func() (struct{}, error) {
+		return struct{}{}, k.Keyring.DeleteAll(service)
+	})
+
+	return err
+}
+
+func (k contextKeyring) List(service string) ([]string, error) {
+	l, ok := k.Keyring.(Lister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return runContext(k.ctx, func() ([]string, error) {
+		return l.List(service)
+	})
+}
+
+func (k contextKeyring) Services() ([]string, error) {
+	l, ok := k.Keyring.(ServiceLister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return runContext(k.ctx, l.Services)
+}
+
+// runContext runs the function in a goroutine, and returns the error of the context if it is done first.
+func runContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err //nolint: wrapcheck
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	ch := make(chan result, 1)
+
+	go func() {
+		v, err := fn()
+
+		ch <- result{value: v, err: err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.value, r.err
+
+	case <-ctx.Done():
+		return zero, ctx.Err() //nolint: wrapcheck
+	}
+}
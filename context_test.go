@@ -0,0 +1,177 @@
+package secretstorage_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+type ctxKey struct{}
+
+func TestKeyringStorage_Context_ContextKeyring(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+	}{
+		{
+			scenario: "keyring",
+		},
+		{
+			scenario: "guarded keyring",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCircuitBreaker(3, time.Second)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+			ck := mock.NewContextKeyring(t)
+
+			ck.On("GetContext", ctx, t.Name(), "key").
+				Return("value", nil).Once()
+
+			ck.On("GetContext", ctx, t.Name(), "key").
+				Return("", secretstorage.ErrNotFound).Once()
+
+			ck.On("SetContext", ctx, t.Name(), "key", "new value").
+				Return(nil).Once()
+
+			ck.On("GetContext", ctx, t.Name(), "key").
+				Return("new value", nil).Once()
+
+			ck.On("DeleteContext", ctx, t.Name(), "key").
+				Return(nil).Once()
+
+			// The previous value of the key is deleted too.
+			ck.On("GetContext", ctx, t.Name(), "key~previous").
+				Return("", secretstorage.ErrNotFound).Once()
+
+			// The mock fails the test if the methods without context are called.
+			k := &bothKeyring{Keyring: mock.NopKeyring(t), ContextKeyring: ck}
+
+			s := secretstorage.NewKeyringStorage[string](append(tc.options, secretstorage.WithKeyring(k))...)
+
+			actual, err := s.GetContext(ctx, t.Name(), "key")
+			require.NoError(t, err)
+
+			assert.Equal(t, "value", actual)
+
+			err = s.SetContext(ctx, t.Name(), "key", "new value")
+			require.NoError(t, err)
+
+			err = s.DeleteContext(ctx, t.Name(), "key")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestKeyringStorage_Context_Fallback(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	err := s.SetContext(context.Background(), t.Name(), "key", "value")
+	require.NoError(t, err)
+
+	actual, err := s.GetContext(context.Background(), t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+
+	err = s.DeleteContext(context.Background(), t.Name(), "key")
+	require.NoError(t, err)
+
+	_, err = s.GetContext(context.Background(), t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_Context_Fallback_Timeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Run(func(mock.Arguments) { <-release }).
+			Return("value", nil).
+			Maybe()
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	actual, err := s.GetContext(ctx, t.Name(), "key")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, actual)
+}
+
+func TestKeyringStorage_Context_Canceled(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := s.SetContext(ctx, t.Name(), "key", "value")
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// bothKeyring implements both keyring.Keyring and secretstorage.ContextKeyring.
+type bothKeyring struct {
+	*mock.Keyring
+	*mock.ContextKeyring
+}
+
+func TestKeyringStorage_Context_Reconfigure(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	require.NoError(t, s.Set(t.Name(), "key", "value"))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			actual, err := s.GetContext(context.Background(), t.Name(), "key")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "value", actual)
+		}()
+
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, s.Reconfigure(secretstorage.WithMaxLength(10+i)))
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,25 @@
+package secretstorage
+
+import "fmt"
+
+func (ss *KeyringStorage[V]) withContextualErrors() {
+	ss.contextualErrors = true
+}
+
+// WithContextualErrors appends the service and the key to the errors of Get, Set and Delete, such as
+// `failed to read data from keyring: secret not found in keyring (service="app" key="token")`, to tell which key
+// failed. The errors still match their causes with errors.Is and errors.As.
+func WithContextualErrors() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withContextualErrors()
+	})
+}
+
+// contextualError appends the service and the key to the error, if enabled.
+func (ss *KeyringStorage[V]) contextualError(err error, service string, key string) error {
+	if err == nil || !ss.contextualErrors {
+		return err
+	}
+
+	return fmt.Errorf("%w (service=%q key=%q)", err, service, key)
+}
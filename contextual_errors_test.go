@@ -0,0 +1,61 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_ContextualErrors(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithContextualErrors())
+
+	_, err := s.Get("app", "token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Contains(t, err.Error(), `(service="app" key="token")`)
+
+	err = s.Delete("app", "token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Contains(t, err.Error(), `(service="app" key="token")`)
+
+	// The successful calls have no error.
+	require.NoError(t, s.Set("app", "token", "value"))
+
+	actual, err := s.Get("app", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_ContextualErrors_Set(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", "app", "token").
+			Return("", secretstorage.ErrNotFound)
+
+		k.On("Set", "app", "token", "value").
+			Return(assert.AnError)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithContextualErrors())
+
+	err := s.Set("app", "token", "value")
+	require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, `failed to write data to keyring: `+assert.AnError.Error()+` (service="app" key="token")`, err.Error())
+}
+
+func TestKeyringStorage_ContextualErrors_Disabled(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	_, err := s.Get("app", "token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.NotContains(t, err.Error(), "service=")
+}
@@ -0,0 +1,106 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/multierr"
+)
+
+// CopyService copies all the values of the source service to the destination service. The multipart values are
+// reassembled and then split again when they are written, the pages are not copied as is. The encrypted values are
+// encrypted again for the destination, see WithEncryption.
+//
+// The keys are copied one by one, a failure does not stop the copy of the other keys and all the errors are returned
+// together.
+//
+// The keyring must implement Lister, otherwise ErrListingNotSupported is returned.
+func (ss *KeyringStorage[V]) CopyService(src, dst string, opts ...CopyServiceOption) error {
+	defer ss.rlockConfig()()
+
+	src = ss.serviceOrDefault(src)
+	dst = ss.serviceOrDefault(dst)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	cfg := copyServiceConfig{}
+
+	for _, opt := range opts {
+		opt.applyCopyServiceOption(&cfg)
+	}
+
+	keys, err := ss.listKeys(src)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if cErr := ss.copyKey(src, dst, key, cfg); cErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to copy %q: %w", key, cErr))
+		}
+	}
+
+	return err
+}
+
+func (ss *KeyringStorage[V]) copyKey(src, dst, key string, cfg copyServiceConfig) error {
+	mu := ss.mutex(src, key)
+
+	mu.RLock()
+	d, _, targetService, targetKey, err := ss.getRawTarget(src, key)
+	mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	// The encrypted data is bound to the key it is read from, it is encoded again for the destination, see
+	// WithEncryption.
+	if params, _, pErr := parseEncodedData(d); pErr == nil && params["encryption"] != "" {
+		if d, err = ss.rebind(targetService, targetKey, dst, key, d); err != nil {
+			return fmt.Errorf("failed to read data from keyring: %w", err)
+		}
+	}
+
+	mu = ss.mutex(dst, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if cfg.skipExisting {
+		_, err := ss.keyring.Get(dst, key)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("failed to read data from keyring: %w", err)
+		}
+	}
+
+	return ss.setRaw(dst, key, d, nil)
+}
+
+type copyServiceConfig struct {
+	skipExisting bool
+}
+
+// CopyServiceOption is an option to configure CopyService.
+type CopyServiceOption interface {
+	applyCopyServiceOption(cfg *copyServiceConfig)
+}
+
+type copyServiceOptionFunc func(cfg *copyServiceConfig)
+
+func (f copyServiceOptionFunc) applyCopyServiceOption(cfg *copyServiceConfig) {
+	f(cfg)
+}
+
+// WithSkipExisting keeps the values that already exist in the destination service instead of overwriting them.
+func WithSkipExisting() CopyServiceOption {
+	return copyServiceOptionFunc(func(cfg *copyServiceConfig) {
+		cfg.skipExisting = true
+	})
+}
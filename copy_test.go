@@ -0,0 +1,139 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_CopyService_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.CopyService(t.Name(), "dst")
+
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+}
+
+func TestKeyringStorage_CopyService_Success(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	single := randString(128)
+	multipart := randString(6139)
+
+	require.NoError(t, s.Set(t.Name(), "single", single))
+	require.NoError(t, s.Set(t.Name(), "multipart", multipart))
+	require.NoError(t, s.Set("dst", "existing", "value"))
+	require.NoError(t, s.Set("dst", "single", randString(5000)))
+
+	err := s.CopyService(t.Name(), "dst")
+	require.NoError(t, err)
+
+	actual, err := s.GetAll("dst")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"existing":  "value",
+		"single":    single,
+		"multipart": multipart,
+	}
+
+	assert.Equal(t, expected, actual)
+
+	expectedEntries := []string{
+		"existing",
+		"multipart",
+		"multipart-0001",
+		"multipart-0002",
+		"multipart-0003",
+		"single",
+	}
+
+	entries, err := k.List("dst")
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedEntries, entries)
+}
+
+func TestKeyringStorage_CopyService_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithEncryption(encryptionKey),
+	)
+
+	single := randString(128)
+	multipart := randString(6139)
+
+	require.NoError(t, s.Set(t.Name(), "single", single))
+	require.NoError(t, s.Set(t.Name(), "multipart", multipart))
+
+	err := s.CopyService(t.Name(), "dst")
+	require.NoError(t, err)
+
+	actual, err := s.GetAll("dst")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"single":    single,
+		"multipart": multipart,
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestKeyringStorage_CopyService_SkipExisting(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	require.NoError(t, s.Set(t.Name(), "new", "new value"))
+	require.NoError(t, s.Set(t.Name(), "existing", randString(6139)))
+	require.NoError(t, s.Set("dst", "existing", "old value"))
+
+	err := s.CopyService(t.Name(), "dst", secretstorage.WithSkipExisting())
+	require.NoError(t, err)
+
+	actual, err := s.GetAll("dst")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"new":      "new value",
+		"existing": "old value",
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestKeyringStorage_CopyService_Failure(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "a", "application/multipart-secret; pages=1"))
+	require.NoError(t, k.Set(t.Name(), "b", "value"))
+	require.NoError(t, k.Set(t.Name(), "c", "application/multipart-secret; pages="))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.CopyService(t.Name(), "dst")
+
+	expected := `failed to copy "a": invalid secret pages: 1; ` +
+		`failed to copy "c": failed to get params from data: mime: invalid media parameter`
+
+	require.EqualError(t, err, expected)
+
+	actual, err := s.GetAll("dst")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"b": "value"}, actual)
+}
@@ -0,0 +1,26 @@
+package secretstorage
+
+func (ss *KeyringStorage[V]) withDefaultService(service string) {
+	ss.defaultService = service
+}
+
+// WithDefaultService sets the service that is used when the service is empty, so that the call sites that share a
+// service may omit it, such as Get("", "key"). The other services are used as is, and the storage keeps its whole
+// interface.
+//
+// The service is substituted before anything else, including the locks, the checks, and the errors, so an empty service
+// is never seen past the public methods. Without a default service, the empty service is passed to the keyring as is.
+func WithDefaultService(service string) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withDefaultService(service)
+	})
+}
+
+// serviceOrDefault returns the default service if the service is empty, see WithDefaultService.
+func (ss *KeyringStorage[V]) serviceOrDefault(service string) string {
+	if service == "" {
+		return ss.defaultService
+	}
+
+	return service
+}
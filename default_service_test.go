@@ -0,0 +1,67 @@
+package secretstorage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_DefaultService(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithDefaultService(t.Name()),
+		secretstorage.WithContextualErrors(),
+	)
+
+	require.NoError(t, s.Set("", "key", "value"))
+	require.NoError(t, s.SetContext(context.Background(), "", "other", "other value"))
+
+	assert.Equal(t, map[string]string{"key": "value", "other": "other value"}, k.entries(t.Name()))
+	assert.Empty(t, k.entries(""))
+
+	// The service is filled in for the reads, and the other services are used as is.
+	actual, err := s.Get("", "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	actual, err = s.Get(t.Name(), "other")
+	require.NoError(t, err)
+	assert.Equal(t, "other value", actual)
+
+	keys, err := s.List("")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"key", "other"}, keys)
+
+	_, err = s.Get(t.Name()+"/other", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	// The errors report the service that is used.
+	require.NoError(t, s.Delete("", "key"))
+
+	err = s.Delete("", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Contains(t, err.Error(), `(service="`+t.Name()+`" key="key")`)
+
+	assert.Equal(t, map[string]string{"other": "other value"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_DefaultService_Reconfigure(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set("", "key", "empty service"))
+	require.NoError(t, s.Reconfigure(secretstorage.WithDefaultService(t.Name())))
+	require.NoError(t, s.Set("", "key", "default service"))
+
+	assert.Equal(t, map[string]string{"key": "empty service"}, k.entries(""))
+	assert.Equal(t, map[string]string{"key": "default service"}, k.entries(t.Name()))
+}
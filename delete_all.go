@@ -0,0 +1,142 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+// DeleteAll deletes all the values of the service, including the pages of the multipart values. The service is locked
+// for the duration of the deletion: the operations on its keys wait for DeleteAll to finish, and DeleteAll waits for
+// the ongoing ones, including the readers returned by GetReader that are not closed yet.
+//
+// If the keyring implements Lister, the entries that the keyring leaves behind, such as the pages on some platforms,
+// are deleted one by one. Otherwise, the values that the storage has written, and their previous values, see Rotate,
+// are deleted one by one with their pages before the keyring deletes the service.
+func (ss *KeyringStorage[V]) DeleteAll(service string) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	l := ss.serviceLocks(service)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := ss.deleteWritten(service); err != nil {
+		return err
+	}
+
+	if err := ss.keyring.DeleteAll(service); err != nil {
+		return ss.notFound(fmt.Errorf("failed to delete all data in keyring: %w", err))
+	}
+
+	ss.entries.releaseService(service)
+	ss.written.releaseService(service)
+
+	if err := ss.deleteLeftovers(service); err != nil {
+		return err
+	}
+
+	return ss.unindexService(service)
+}
+
+// deleteLeftovers deletes the entries of the service that are still listed by the keyring.
+func (ss *KeyringStorage[V]) deleteLeftovers(service string) error {
+	l, err := ss.lister()
+	if err != nil {
+		return nil //nolint: nilerr
+	}
+
+	entries, err := l.List(service)
+	if err != nil {
+		return fmt.Errorf("failed to list data in keyring: %w", err)
+	}
+
+	for _, e := range entries {
+		if dErr := ss.keyring.Delete(service, e); dErr != nil && !errors.Is(dErr, ErrNotFound) {
+			err = multierr.Append(err, fmt.Errorf("failed to delete %q in keyring: %w", e, dErr))
+		}
+	}
+
+	return err
+}
+
+// deleteWritten deletes the values of the service that the storage has written, and their previous values, with their
+// pages, if the keyring does not implement Lister. The pages are found from the headers, that DeleteAll deletes.
+func (ss *KeyringStorage[V]) deleteWritten(service string) error {
+	if _, err := ss.lister(); err == nil {
+		return nil
+	}
+
+	var err error
+
+	for _, key := range ss.written.list(service) {
+		if _, dErr := ss.deleteData(service, key, true); dErr != nil && !errors.Is(dErr, ErrNotFound) {
+			err = multierr.Append(err, fmt.Errorf("failed to delete %q in keyring: %w", key, dErr))
+		}
+	}
+
+	return err
+}
+
+// writtenKeys tracks the keys that the storage writes, per service, so that DeleteAll finds their pages when the
+// keyring does not list its entries.
+type writtenKeys struct {
+	mu   sync.Mutex
+	keys map[string]map[string]struct{}
+}
+
+// add records that the key is written.
+func (w *writtenKeys) add(service string, key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.keys[service] == nil {
+		w.keys[service] = make(map[string]struct{})
+	}
+
+	w.keys[service][key] = struct{}{}
+}
+
+// has tells whether the key is written.
+func (w *writtenKeys) has(service string, key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.keys[service][key]
+
+	return ok
+}
+
+// list returns the keys of the service that are written, and their previous slots, see Rotate.
+func (w *writtenKeys) list(service string) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make([]string, 0, 2*len(w.keys[service]))
+
+	for key := range w.keys[service] {
+		result = append(result, key)
+
+		if _, ok := w.keys[service][previousKey(key)]; !ok && !isPreviousKey(key) {
+			result = append(result, previousKey(key))
+		}
+	}
+
+	return result
+}
+
+// releaseService forgets the keys of the service.
+func (w *writtenKeys) releaseService(service string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.keys, service)
+}
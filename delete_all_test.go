@@ -0,0 +1,187 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_DeleteAll_Success(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "single", "value"))
+	require.NoError(t, s.Set(t.Name(), "multipart", randString(5000)))
+	require.NoError(t, s.Set("another service", "key", "value"))
+
+	err := s.DeleteAll(t.Name())
+	require.NoError(t, err)
+
+	assert.Empty(t, k.entries(t.Name()))
+	assert.Equal(t, map[string]string{"key": "value"}, k.entries("another service"))
+}
+
+func TestKeyringStorage_DeleteAll_Failure(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("DeleteAll", t.Name()).
+			Return(assert.AnError)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.DeleteAll(t.Name())
+
+	require.EqualError(t, err, "failed to delete all data in keyring: assert.AnError general error for testing")
+}
+
+func TestKeyringStorage_DeleteAll_ExcludesKeyOperations(t *testing.T) {
+	t.Parallel()
+
+	k := &deleteAllKeyring{concurrencyKeyring: newConcurrencyKeyring(100 * time.Microsecond)}
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, s.Set(t.Name(), key, randString(5000)))
+
+				// The value is either intact or deleted as a whole, never half deleted.
+				if _, err := s.Get(t.Name(), key); err != nil {
+					assert.ErrorIs(t, err, secretstorage.ErrNotFound)
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, s.DeleteAll(t.Name()))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Zero(t, k.overlaps.Load())
+}
+
+// deleteAllKeyring is a concurrencyKeyring that counts the calls that overlap with DeleteAll.
+type deleteAllKeyring struct {
+	*concurrencyKeyring
+
+	deleting atomic.Bool
+	overlaps atomic.Int64
+}
+
+func (k *deleteAllKeyring) check() {
+	if k.deleting.Load() {
+		k.overlaps.Add(1)
+	}
+}
+
+func (k *deleteAllKeyring) Set(service, user, password string) error {
+	k.check()
+
+	return k.concurrencyKeyring.Set(service, user, password)
+}
+
+func (k *deleteAllKeyring) Get(service, user string) (string, error) {
+	k.check()
+
+	return k.concurrencyKeyring.Get(service, user)
+}
+
+func (k *deleteAllKeyring) Delete(service, user string) error {
+	k.check()
+
+	return k.concurrencyKeyring.Delete(service, user)
+}
+
+func (k *deleteAllKeyring) DeleteAll(service string) error {
+	k.deleting.Store(true)
+	defer k.deleting.Store(false)
+
+	time.Sleep(time.Millisecond)
+
+	return k.concurrencyKeyring.DeleteAll(service)
+}
+
+func TestKeyringStorage_DeleteAll_Leftovers(t *testing.T) {
+	t.Parallel()
+
+	k := &leakyKeyring{memoryKeyring: newMemoryKeyring()}
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "key", randString(5000)))
+
+	err := s.DeleteAll(t.Name())
+	require.NoError(t, err)
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+// leakyKeyring is a memoryKeyring whose DeleteAll leaves the pages behind.
+type leakyKeyring struct {
+	*memoryKeyring
+}
+
+func (k *leakyKeyring) DeleteAll(service string) error {
+	for key := range k.entries(service) {
+		if strings.Contains(key, "-00") {
+			continue
+		}
+
+		if err := k.Delete(service, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestKeyringStorage_DeleteAll_Leftovers_NoLister(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+	// The keyring does not implement Lister.
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(struct{ keyring.Keyring }{&leakyKeyring{memoryKeyring: m}}),
+		secretstorage.WithMaxLength(100),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", randString(500)))
+	require.NoError(t, s.Rotate(t.Name(), "rotated", randString(500), 0))
+	require.NoError(t, s.Rotate(t.Name(), "rotated", randString(500), time.Hour))
+
+	assert.Contains(t, m.entries(t.Name()), "rotated~previous-0001")
+
+	err := s.DeleteAll(t.Name())
+	require.NoError(t, err)
+
+	assert.Empty(t, m.entries(t.Name()))
+}
@@ -0,0 +1,29 @@
+package secretstorage
+
+// MultipartDeleteError is returned when the deletion of a multipart value fails part way, it tells which entries are
+// left behind. The deletion stops at the first page that could not be deleted. The header is deleted if at least one
+// page is, so that the value is not read half deleted.
+//
+// The error matches the error of the keyring with errors.Is.
+type MultipartDeleteError struct {
+	Service string
+	Key     string
+	// Deleted are the pages that are deleted.
+	Deleted []int
+	// Failed are the pages that could not be deleted.
+	Failed []int
+	// Skipped are the pages that are not deleted because the deletion stopped before them.
+	Skipped []int
+	// HeaderDeleted tells whether the header, the main entry of the value, is deleted.
+	HeaderDeleted bool
+	// Err is the error of the deletion.
+	Err error
+}
+
+func (e *MultipartDeleteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MultipartDeleteError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,34 @@
+package secretstorage
+
+import "fmt"
+
+// DeletePage deletes the entry of a single page of the key, and nothing else. It returns ErrNotFound if the page does
+// not exist, and ErrInvalidPage if the page is less than 1.
+//
+// DeletePage is meant for recovery, for example to remove an orphaned page. Deleting a page of a live multipart value
+// corrupts the value. The pages written with WithAtomicSwap may have a generation in their keys, DeletePage does not
+// know about it.
+func (ss *KeyringStorage[V]) DeletePage(service string, key string, page int) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	if page < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidPage, page)
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := ss.keyring.Delete(service, ss.pageFormat.format(key, page)); err != nil {
+		return ss.notFound(fmt.Errorf("failed to delete multipart data #%d in keyring: %w", page, err))
+	}
+
+	return nil
+}
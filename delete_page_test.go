@@ -0,0 +1,51 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_DeletePage(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-0001", "hello "))
+	require.NoError(t, k.Set(t.Name(), "key-0002", "world"))
+	require.NoError(t, k.Set(t.Name(), "key-0003", "orphan"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.DeletePage(t.Name(), "key", 3)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"key":      "application/multipart-secret; pages=2",
+		"key-0001": "hello ",
+		"key-0002": "world",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	err = s.DeletePage(t.Name(), "key", 3)
+
+	require.EqualError(t, err, "failed to delete multipart data #3 in keyring: secret not found in keyring")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_DeletePage_InvalidPage(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.DeletePage(t.Name(), "key", 0)
+
+	require.ErrorIs(t, err, secretstorage.ErrInvalidPage)
+	require.EqualError(t, err, "invalid secret page: 0")
+}
@@ -0,0 +1,389 @@
+package secretstorage
+
+import (
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	mimeEncryptedSecret = "application/encrypted-secret"
+	saltSize            = 16
+	derivedKeySize      = 32
+)
+
+var (
+	_ Storage[any] = (*EncryptedStorage[any])(nil)
+	_ Flusher      = (*EncryptedStorage[any])(nil)
+	_ KDF          = ScryptKDF{}
+	_ KDF          = Argon2KDF{}
+)
+
+// KDF derives the encryption keys from a passphrase, see EncryptedStorage.
+type KDF interface {
+	// Name returns the name of the KDF, that is recorded in the header of the data. It must be stable.
+	Name() string
+	// DeriveKey derives a 32-byte key from the passphrase and the salt.
+	DeriveKey(passphrase []byte, salt []byte) ([]byte, error)
+}
+
+// ScryptKDF derives the keys with scrypt, with N=32768, r=8 and p=1.
+type ScryptKDF struct{}
+
+// Name returns "scrypt".
+func (ScryptKDF) Name() string {
+	return "scrypt"
+}
+
+// DeriveKey derives the key.
+func (ScryptKDF) DeriveKey(passphrase []byte, salt []byte) ([]byte, error) {
+	return scrypt.Key(passphrase, salt, 1<<15, 8, 1, derivedKeySize) //nolint: wrapcheck
+}
+
+// Argon2KDF derives the keys with Argon2id, with 1 pass over 64 MiB of memory and 4 threads.
+type Argon2KDF struct{}
+
+// Name returns "argon2id".
+func (Argon2KDF) Name() string {
+	return "argon2id"
+}
+
+// DeriveKey derives the key.
+func (Argon2KDF) DeriveKey(passphrase []byte, salt []byte) ([]byte, error) {
+	return argon2.IDKey(passphrase, salt, 1, 64*1024, 4, derivedKeySize), nil
+}
+
+// EncryptedStorage encrypts the values with AES-GCM before writing them to another storage. Every write derives a new
+// key from the passphrase and a random salt, that is stored in the header of the data, so that each secret is
+// encrypted with its own key: the derived key of one secret does not decrypt the others. The ciphertext is also bound
+// to its service and key, it can not be moved to another key.
+//
+// The values are marshaled with TextCodec. Every read and write derives a key, which is slow on purpose, see WithKDF.
+// The passphrases may be rotated, with their ids in the headers, see WithKeyResolver and Rotate.
+type EncryptedStorage[V any] struct {
+	storage     Storage[string]
+	passphrase  []byte
+	kdf         KDF
+	resolver    KeyResolver
+	activeKeyID string
+	rand        io.Reader
+
+	decryptionPassphrases [][]byte
+}
+
+// Get gets the data from the storage, and decrypts it.
+func (s *EncryptedStorage[V]) Get(service string, key string) (V, error) {
+	var result V
+
+	d, err := s.storage.Get(service, key)
+	if err != nil {
+		return result, err //nolint: wrapcheck
+	}
+
+	b, err := s.decrypt(service, key, d)
+	if err != nil {
+		return result, err
+	}
+
+	if err := unmarshalData(string(b), &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	return result, nil
+}
+
+// Set encrypts the value with a new derived key, and sets it in the storage.
+func (s *EncryptedStorage[V]) Set(service string, key string, value V) error {
+	d, err := marshalData(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", tagError(ErrMarshal, err))
+	}
+
+	e, err := s.encrypt(service, key, []byte(d))
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Set(service, key, e) //nolint: wrapcheck
+}
+
+// Delete deletes the data in the storage.
+func (s *EncryptedStorage[V]) Delete(service string, key string) error {
+	return s.storage.Delete(service, key) //nolint: wrapcheck
+}
+
+// Flush flushes the decorated storage.
+func (s *EncryptedStorage[V]) Flush() error {
+	return flush(s.storage)
+}
+
+func (s *EncryptedStorage[V]) encrypt(service string, key string, plaintext []byte) (string, error) {
+	passphrase, id := s.passphrase, ""
+
+	if s.resolver != nil {
+		var err error
+
+		if passphrase, err = s.resolveKey(s.activeKeyID); err != nil {
+			return "", err
+		}
+
+		id = s.activeKeyID
+	}
+
+	return s.encryptWith(service, key, plaintext, passphrase, id)
+}
+
+// encryptWith encrypts the data with a key that is derived from the passphrase, and records the id of the passphrase
+// in the header, if any.
+func (s *EncryptedStorage[V]) encryptWith(service string, key string, plaintext []byte, passphrase []byte, id string) (string, error) {
+	salt := make([]byte, saltSize)
+
+	if _, err := io.ReadFull(randReader(s.rand), salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	params := map[string]string{
+		"kdf":  s.kdf.Name(),
+		"salt": base64.StdEncoding.EncodeToString(salt),
+	}
+
+	if id != "" {
+		params["key-id"] = id
+	}
+
+	derived, err := s.kdf.DeriveKey(passphrase, salt)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	aead, err := newAEAD(derived)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := io.ReadFull(randReader(s.rand), nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := aead.Seal(nonce, nonce, plaintext, associatedData(service, key))
+	header := mime.FormatMediaType(mimeEncryptedSecret, params)
+
+	return header + "\n" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (s *EncryptedStorage[V]) decrypt(service string, key string, d string) ([]byte, error) {
+	e, err := s.parseEncryptedData(d)
+	if err != nil {
+		return nil, err
+	}
+
+	passphrases, err := s.passphrasesOf(e.id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The data is encrypted without an id, or before the ids are recorded, the passphrases are tried in turn.
+	for _, passphrase := range passphrases {
+		var b []byte
+
+		if b, err = s.decryptWith(service, key, e, passphrase); err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, err
+}
+
+// passphrasesOf returns the passphrase of the id, or the passphrase of NewEncryptedStorage followed by the older ones
+// if the data has no id, see WithDecryptionPassphrases.
+func (s *EncryptedStorage[V]) passphrasesOf(id string) ([][]byte, error) {
+	passphrases := append([][]byte{s.passphrase}, s.decryptionPassphrases...)
+
+	if id == "" {
+		return passphrases, nil
+	}
+
+	// The data that is rotated with Rotate records the fingerprint of its passphrase.
+	for _, passphrase := range passphrases {
+		if keyID(passphrase) == id {
+			return [][]byte{passphrase}, nil
+		}
+	}
+
+	passphrase, err := s.resolveKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]byte{passphrase}, nil
+}
+
+// decryptWith decrypts the data with a key that is derived from the passphrase.
+func (s *EncryptedStorage[V]) decryptWith(service string, key string, e encryptedData, passphrase []byte) ([]byte, error) {
+	derived, err := e.kdf.DeriveKey(passphrase, e.salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	aead, err := newAEAD(derived)
+	if err != nil {
+		return nil, err
+	}
+
+	return openSealed(aead, e.ciphertext, associatedData(service, key), legacyAssociatedData(service, key))
+}
+
+// encryptedData is the data that is written by EncryptedStorage.
+type encryptedData struct {
+	kdf        KDF
+	salt       []byte
+	id         string
+	ciphertext []byte
+}
+
+func (s *EncryptedStorage[V]) parseEncryptedData(d string) (encryptedData, error) {
+	if !strings.HasPrefix(d, mimeEncryptedSecret) {
+		return encryptedData{}, fmt.Errorf("%w: data is not encrypted", ErrCorruptSecret)
+	}
+
+	header, data, _ := strings.Cut(d, "\n")
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return encryptedData{}, &headerError{field: "params", err: err}
+	}
+
+	kdf, err := s.kdfByName(params["kdf"])
+	if err != nil {
+		return encryptedData{}, err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(params["salt"])
+	if err != nil {
+		return encryptedData{}, &headerError{field: "salt", err: err}
+	}
+
+	if len(salt) == 0 {
+		return encryptedData{}, &headerError{field: "salt", err: errors.New("missing parameter")} //nolint: goerr113
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return encryptedData{}, fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	return encryptedData{kdf: kdf, salt: salt, id: params["key-id"], ciphertext: ciphertext}, nil
+}
+
+// kdfByName returns the configured KDF, or the built-in one, that the data is encrypted with, so that the data that is
+// written before a change of KDF can still be read.
+func (s *EncryptedStorage[V]) kdfByName(name string) (KDF, error) {
+	for _, kdf := range []KDF{s.kdf, ScryptKDF{}, Argon2KDF{}} {
+		if kdf.Name() == name {
+			return kdf, nil
+		}
+	}
+
+	return nil, &headerError{field: "kdf", err: fmt.Errorf("unsupported kdf %q", name)} //nolint: goerr113
+}
+
+// associatedData binds the ciphertext to its service and key. Both are prefixed with their length, so that no two pairs
+// have the same associated data, such as "a\x00b", "c" and "a", "b\x00c".
+func associatedData(service string, key string) []byte {
+	b := make([]byte, 0, 16+len(service)+len(key))
+
+	b = binary.BigEndian.AppendUint64(b, uint64(len(service)))
+	b = append(b, service...)
+	b = binary.BigEndian.AppendUint64(b, uint64(len(key)))
+
+	return append(b, key...)
+}
+
+// legacyAssociatedData is the associated data before it is length-prefixed. The data is not encrypted with it anymore,
+// it is only decrypted.
+func legacyAssociatedData(service string, key string) []byte {
+	return []byte(service + "\x00" + key)
+}
+
+// openSealed decrypts the data, that is the nonce followed by the ciphertext, with the first associated data that
+// authenticates it.
+func openSealed(aead cipher.AEAD, b []byte, ads ...[]byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(b) < nonceSize {
+		return nil, fmt.Errorf("failed to decrypt data: %w", ErrCorruptSecret)
+	}
+
+	var err error
+
+	for _, ad := range ads {
+		var d []byte
+
+		if d, err = aead.Open(nil, b[:nonceSize], b[nonceSize:], ad); err == nil {
+			return d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to decrypt data: %w", err)
+}
+
+// NewEncryptedStorage creates a new EncryptedStorage that derives the keys from the passphrase. By default, the keys are
+// derived with ScryptKDF.
+func NewEncryptedStorage[V any](storage Storage[string], passphrase []byte, opts ...EncryptedStorageOption[V]) *EncryptedStorage[V] {
+	s := &EncryptedStorage[V]{
+		storage:    storage,
+		passphrase: passphrase,
+		kdf:        ScryptKDF{},
+	}
+
+	for _, opt := range opts {
+		opt.applyEncryptedStorageOption(s)
+	}
+
+	return s
+}
+
+// EncryptedStorageOption is an option to configure EncryptedStorage.
+type EncryptedStorageOption[V any] interface {
+	applyEncryptedStorageOption(s *EncryptedStorage[V])
+}
+
+type encryptedStorageOptionFunc[V any] func(s *EncryptedStorage[V])
+
+func (f encryptedStorageOptionFunc[V]) applyEncryptedStorageOption(s *EncryptedStorage[V]) {
+	f(s)
+}
+
+// WithKDF sets the KDF that derives the keys, such as ScryptKDF or Argon2KDF. The data is decrypted with the KDF that is
+// recorded in its header, so the data written with another KDF is still read.
+func WithKDF[V any](kdf KDF) EncryptedStorageOption[V] {
+	return encryptedStorageOptionFunc[V](func(s *EncryptedStorage[V]) {
+		s.kdf = kdf
+	})
+}
+
+// WithDecryptionPassphrases registers the passphrases that were used before the one of NewEncryptedStorage. The data
+// encrypted with one of them is still decrypted, the data is encrypted with the current passphrase the next time it is
+// written, or when it is rotated with Rotate.
+func WithDecryptionPassphrases[V any](passphrases ...[]byte) EncryptedStorageOption[V] {
+	return encryptedStorageOptionFunc[V](func(s *EncryptedStorage[V]) {
+		s.decryptionPassphrases = append(s.decryptionPassphrases, passphrases...)
+	})
+}
+
+// WithEncryptedRandReader sets the source of the salts and the nonces, like WithRandReader for KeyringStorage. It is
+// meant for the tests only, crypto/rand.Reader is used by default.
+func WithEncryptedRandReader[V any](r io.Reader) EncryptedStorageOption[V] {
+	return encryptedStorageOptionFunc[V](func(s *EncryptedStorage[V]) {
+		s.rand = r
+	})
+}
@@ -0,0 +1,134 @@
+package secretstorage
+
+import (
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const mimeEncryptedEntry = "application/encrypted-entry; encryption=" + encryptionAESGCM
+
+var (
+	_ keyring.Keyring = (*EncryptedKeyring)(nil)
+	_ Lister          = (*EncryptedKeyring)(nil)
+	_ ServiceLister   = (*EncryptedKeyring)(nil)
+	_ Flusher         = (*EncryptedKeyring)(nil)
+)
+
+// EncryptedKeyring encrypts every entry with AES-GCM before writing it to another keyring, and decrypts it when it is
+// read. It is used with WithKeyring. Unlike EncryptedStorage and WithEncryption, which encrypt the values, it encrypts
+// the entries as they are stored, so the headers, the labels and the pages of the multipart values are all encrypted.
+// The ciphertext is bound to its service and user, it can not be moved to another entry.
+//
+// The services and the users are not encrypted. The encrypted entries are about a third longer than the plain ones,
+// the max length of the storage should account for it if the keyring limits the size of the entries, see
+// WithMaxLength. The entries that are not encrypted fail to read with ErrCorruptSecret.
+type EncryptedKeyring struct {
+	keyring.Keyring
+
+	aead cipher.AEAD
+	rand io.Reader
+}
+
+// Get gets the entry, and decrypts it.
+func (k *EncryptedKeyring) Get(service, user string) (string, error) {
+	d, err := k.Keyring.Get(service, user)
+	if err != nil {
+		return "", err //nolint: wrapcheck
+	}
+
+	data, ok := strings.CutPrefix(d, mimeEncryptedEntry+"\n")
+	if !ok {
+		return "", fmt.Errorf("%w: entry is not encrypted", ErrCorruptSecret)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	b, err = openSealed(k.aead, b, associatedData(service, user), legacyAssociatedData(service, user))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// Set encrypts the entry, and sets it.
+func (k *EncryptedKeyring) Set(service, user, password string) error {
+	nonce := make([]byte, k.aead.NonceSize())
+
+	if _, err := io.ReadFull(randReader(k.rand), nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	b := k.aead.Seal(nonce, nonce, []byte(password), associatedData(service, user))
+
+	return k.Keyring.Set(service, user, mimeEncryptedEntry+"\n"+base64.StdEncoding.EncodeToString(b)) //nolint: wrapcheck
+}
+
+// List lists the users of the service, if the keyring implements Lister.
+func (k *EncryptedKeyring) List(service string) ([]string, error) {
+	l, ok := k.Keyring.(Lister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return l.List(service) //nolint: wrapcheck
+}
+
+// Services lists the services, if the keyring implements ServiceLister.
+func (k *EncryptedKeyring) Services() ([]string, error) {
+	l, ok := k.Keyring.(ServiceLister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return l.Services() //nolint: wrapcheck
+}
+
+// Flush flushes the decorated keyring, if it implements Flusher.
+func (k *EncryptedKeyring) Flush() error {
+	return flush(k.Keyring)
+}
+
+// NewEncryptedKeyring creates a new EncryptedKeyring that encrypts the entries of the keyring with the key. The key must
+// be 16, 24 or 32 bytes long, to select AES-128, AES-192 or AES-256.
+func NewEncryptedKeyring(k keyring.Keyring, key []byte, opts ...EncryptedKeyringOption) (*EncryptedKeyring, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ek := &EncryptedKeyring{Keyring: k, aead: aead}
+
+	for _, opt := range opts {
+		opt.applyEncryptedKeyringOption(ek)
+	}
+
+	return ek, nil
+}
+
+// EncryptedKeyringOption is an option to configure EncryptedKeyring.
+type EncryptedKeyringOption interface {
+	applyEncryptedKeyringOption(k *EncryptedKeyring)
+}
+
+type encryptedKeyringOptionFunc func(k *EncryptedKeyring)
+
+func (f encryptedKeyringOptionFunc) applyEncryptedKeyringOption(k *EncryptedKeyring) {
+	f(k)
+}
+
+// WithEncryptedKeyringRandReader sets the source of the nonces, like WithRandReader for KeyringStorage. It is meant for
+// the tests only, crypto/rand.Reader is used by default.
+func WithEncryptedKeyringRandReader(r io.Reader) EncryptedKeyringOption {
+	return encryptedKeyringOptionFunc(func(k *EncryptedKeyring) {
+		k.rand = r
+	})
+}
@@ -0,0 +1,163 @@
+package secretstorage_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestEncryptedKeyring(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+
+	k, err := secretstorage.NewEncryptedKeyring(m, encryptionKey)
+	require.NoError(t, err)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(64))
+
+	value := strings.Repeat("the quick brown fox ", 10)
+
+	require.NoError(t, s.SetWith(t.Name(), "key", value, secretstorage.WithLabels(map[string]string{"owner": "alice"})))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, value, actual)
+
+	labels, err := s.Labels(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "alice"}, labels)
+
+	// The header and all the pages are encrypted at rest.
+	entries := m.entries(t.Name())
+
+	assert.Len(t, entries, 5)
+
+	for key, d := range entries {
+		assert.True(t, strings.HasPrefix(d, "application/encrypted-entry; encryption=aes-gcm\n"), key)
+		assert.NotContains(t, d, "multipart")
+		assert.NotContains(t, d, "alice")
+		assert.NotContains(t, d, "quick")
+	}
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+	assert.Empty(t, m.entries(t.Name()))
+
+	_, err = s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestEncryptedKeyring_NotEncrypted(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+
+	k, err := secretstorage.NewEncryptedKeyring(m, encryptionKey)
+	require.NoError(t, err)
+
+	require.NoError(t, m.Set(t.Name(), "key", "secret"))
+
+	actual, err := k.Get(t.Name(), "key")
+
+	require.EqualError(t, err, "corrupt secret: entry is not encrypted")
+	require.ErrorIs(t, err, secretstorage.ErrCorruptSecret)
+	assert.Empty(t, actual)
+}
+
+func TestEncryptedKeyring_MovedEntry(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+
+	k, err := secretstorage.NewEncryptedKeyring(m, encryptionKey)
+	require.NoError(t, err)
+
+	require.NoError(t, k.Set(t.Name(), "key", "secret"))
+
+	d, err := m.Get(t.Name(), "key")
+	require.NoError(t, err)
+	require.NoError(t, m.Set(t.Name(), "other", d))
+
+	actual, err := k.Get(t.Name(), "other")
+
+	require.EqualError(t, err, "failed to decrypt data: cipher: message authentication failed")
+	assert.Empty(t, actual)
+}
+
+func TestEncryptedKeyring_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+
+	k, err := secretstorage.NewEncryptedKeyring(m, encryptionKey)
+	require.NoError(t, err)
+
+	require.NoError(t, k.Set(t.Name(), "key", "secret"))
+
+	other, err := secretstorage.NewEncryptedKeyring(m, []byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+
+	_, err = other.Get(t.Name(), "key")
+	require.EqualError(t, err, "failed to decrypt data: cipher: message authentication failed")
+}
+
+func TestNewEncryptedKeyring_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	k, err := secretstorage.NewEncryptedKeyring(newMemoryKeyring(), []byte("key"))
+
+	require.EqualError(t, err, "failed to create cipher: crypto/aes: invalid key size 3")
+	assert.Nil(t, k)
+}
+
+func TestEncryptedKeyring_AmbiguousEntry(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+
+	k, err := secretstorage.NewEncryptedKeyring(m, encryptionKey)
+	require.NoError(t, err)
+
+	require.NoError(t, k.Set("a\x00b", "c", "secret"))
+
+	d, err := m.Get("a\x00b", "c")
+	require.NoError(t, err)
+	require.NoError(t, m.Set("a", "b\x00c", d))
+
+	actual, err := k.Get("a", "b\x00c")
+
+	require.EqualError(t, err, "failed to decrypt data: cipher: message authentication failed")
+	assert.Empty(t, actual)
+}
+
+func TestEncryptedKeyring_LegacyEntry(t *testing.T) {
+	t.Parallel()
+
+	block, err := aes.NewCipher(encryptionKey)
+	require.NoError(t, err)
+
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	// The entry is bound to its service and key before they are length-prefixed.
+	nonce := make([]byte, aead.NonceSize())
+	b := aead.Seal(nonce, nonce, []byte("secret"), []byte(t.Name()+"\x00key"))
+
+	m := newMemoryKeyring()
+
+	require.NoError(t, m.Set(t.Name(), "key", "application/encrypted-entry; encryption=aes-gcm\n"+base64.StdEncoding.EncodeToString(b)))
+
+	k, err := secretstorage.NewEncryptedKeyring(m, encryptionKey)
+	require.NoError(t, err)
+
+	actual, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+}
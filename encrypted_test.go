@@ -0,0 +1,200 @@
+package secretstorage_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestEncryptedStorage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		kdf      secretstorage.KDF
+		expected string
+	}{
+		{
+			scenario: "scrypt",
+			kdf:      secretstorage.ScryptKDF{},
+			expected: "application/encrypted-secret; kdf=scrypt; salt=",
+		},
+		{
+			scenario: "argon2",
+			kdf:      secretstorage.Argon2KDF{},
+			expected: "application/encrypted-secret; kdf=argon2id; salt=",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewEncryptedStorage[string](
+				secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k)),
+				[]byte("passphrase"),
+				secretstorage.WithKDF[string](tc.kdf),
+			)
+
+			require.NoError(t, s.Set(t.Name(), "a", "p4ssw0rd"))
+			require.NoError(t, s.Set(t.Name(), "b", "p4ssw0rd"))
+
+			entries := k.entries(t.Name())
+
+			assert.Contains(t, entries["a"], tc.expected)
+			assert.NotContains(t, entries["a"], "p4ssw0rd")
+
+			// Each secret has its own salt, so its own key.
+			assert.NotEqual(t, entries["a"], entries["b"])
+
+			actual, err := s.Get(t.Name(), "a")
+			require.NoError(t, err)
+			assert.Equal(t, "p4ssw0rd", actual)
+
+			require.NoError(t, s.Delete(t.Name(), "a"))
+
+			_, err = s.Get(t.Name(), "a")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+		})
+	}
+}
+
+func TestEncryptedStorage_Failures(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+	s := secretstorage.NewEncryptedStorage[string](inner, []byte("passphrase"))
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	// The data of another KDF is still read.
+	argon2 := secretstorage.NewEncryptedStorage[string](inner, []byte("passphrase"), secretstorage.WithKDF[string](secretstorage.Argon2KDF{}))
+
+	actual, err := argon2.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+
+	// Wrong passphrase.
+	other := secretstorage.NewEncryptedStorage[string](inner, []byte("another passphrase"))
+
+	_, err = other.Get(t.Name(), "key")
+	require.EqualError(t, err, "failed to decrypt data: cipher: message authentication failed")
+
+	// The ciphertext is bound to its key.
+	require.NoError(t, inner.Set(t.Name(), "moved", k.entries(t.Name())["key"]))
+
+	_, err = s.Get(t.Name(), "moved")
+	require.EqualError(t, err, "failed to decrypt data: cipher: message authentication failed")
+
+	// Not encrypted.
+	require.NoError(t, inner.Set(t.Name(), "plain", "p4ssw0rd"))
+
+	_, err = s.Get(t.Name(), "plain")
+	require.ErrorIs(t, err, secretstorage.ErrCorruptSecret)
+	require.EqualError(t, err, "corrupt secret: data is not encrypted")
+
+	// Unknown KDF.
+	require.NoError(t, inner.Set(t.Name(), "unknown", "application/encrypted-secret; kdf=md5; salt=\"c2FsdA==\"\ndata"))
+
+	_, err = s.Get(t.Name(), "unknown")
+	require.EqualError(t, err, `failed to get kdf from data: unsupported kdf "md5"`)
+}
+
+// fixedKDF derives the same key from every passphrase and salt.
+type fixedKDF struct{}
+
+func (fixedKDF) Name() string {
+	return "fixed"
+}
+
+func (fixedKDF) DeriveKey([]byte, []byte) ([]byte, error) {
+	return encryptionKey, nil
+}
+
+func TestEncryptedStorage_LegacyData(t *testing.T) {
+	t.Parallel()
+
+	block, err := aes.NewCipher(encryptionKey)
+	require.NoError(t, err)
+
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	// The data is bound to its service and key before they are length-prefixed.
+	nonce := make([]byte, aead.NonceSize())
+	b := aead.Seal(nonce, nonce, []byte("p4ssw0rd"), []byte(t.Name()+"\x00key"))
+
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	s := secretstorage.NewEncryptedStorage[string](inner, []byte("passphrase"), secretstorage.WithKDF[string](fixedKDF{}))
+
+	require.NoError(t, inner.Set(t.Name(), "key", "application/encrypted-secret; kdf=fixed; salt=\"c2FsdA==\"\n"+base64.StdEncoding.EncodeToString(b)))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestEncryptedStorage_Rotate(t *testing.T) {
+	t.Parallel()
+
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	old := secretstorage.NewEncryptedStorage[string](inner, []byte("old passphrase"))
+	s := secretstorage.NewEncryptedStorage[string](inner, []byte("new passphrase"),
+		secretstorage.WithDecryptionPassphrases[string]([]byte("old passphrase")),
+	)
+
+	require.NoError(t, old.Set(t.Name(), "key1", "value1"))
+	require.NoError(t, old.Set(t.Name(), "key2", "value2"))
+	require.NoError(t, s.Set(t.Name(), "key3", "value3"))
+
+	// The values are read before the rotation.
+	actual, err := s.Get(t.Name(), "key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", actual)
+
+	rotated, err := s.Rotate(t.Name(), []byte("old passphrase"), []byte("new passphrase"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, rotated)
+
+	d, err := inner.Get(t.Name(), "key1")
+	require.NoError(t, err)
+	assert.Contains(t, d, "key-id=")
+
+	// The rotated values are read with the new passphrase only.
+	fresh := secretstorage.NewEncryptedStorage[string](inner, []byte("new passphrase"))
+
+	for key, expected := range map[string]string{"key1": "value1", "key2": "value2", "key3": "value3"} {
+		actual, err := fresh.Get(t.Name(), key)
+		require.NoError(t, err, key)
+		assert.Equal(t, expected, actual, key)
+	}
+
+	_, err = old.Get(t.Name(), "key1")
+	require.Error(t, err)
+
+	rotated, err = s.Rotate(t.Name(), []byte("old passphrase"), []byte("new passphrase"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, rotated)
+}
+
+func TestEncryptedStorage_Rotate_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	s := secretstorage.NewEncryptedStorage[string](struct{ secretstorage.Storage[string] }{inner}, []byte("passphrase"))
+
+	rotated, err := s.Rotate(t.Name(), []byte("old passphrase"), []byte("passphrase"))
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.Equal(t, 0, rotated)
+}
@@ -0,0 +1,262 @@
+package secretstorage
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// RotateEncryptionKey encrypts again with the new key the values of the service that are encrypted with the old key,
+// see WithEncryption, and returns the number of values that are rotated. The keys of the service are found with List,
+// the keyring must implement Lister.
+//
+// The values are decrypted and encrypted again as they are, they are not decoded. The values that are not encrypted, or
+// already encrypted with the new key, are left unchanged. The labels and the expiry of the values are kept, and so are
+// the previous values, see Rotate.
+//
+// The storage should be configured with the new key, and the old one in WithDecryptionKeys, so that the values are
+// read whether they are rotated or not during the rotation. The rotation goes on when a value fails, the errors are
+// returned together.
+func (ss *KeyringStorage[V]) RotateEncryptionKey(service string, oldKey []byte, newKey []byte) (int, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	for _, key := range [][]byte{oldKey, newKey} {
+		if _, err := newAEAD(key); err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrInvalidOption, err)
+		}
+	}
+
+	keys, err := ss.listKeys(service)
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+
+	for _, key := range keys {
+		ok, rErr := ss.rotateEncryptionKey(ss.rand(), service, key, oldKey, newKey)
+		if rErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to rotate the encryption key of %q: %w", key, rErr))
+
+			continue
+		}
+
+		if ok {
+			rotated++
+		}
+	}
+
+	return rotated, err
+}
+
+// rotateEncryptionKey locks the key and encrypts its value, and its previous value, again with the new key. The boolean
+// is false if both are left unchanged.
+func (ss *KeyringStorage[V]) rotateEncryptionKey(r io.Reader, service string, key string, oldKey []byte, newKey []byte) (bool, error) {
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The previous value is bound to the key, like the value, see Rotate.
+	ad := associatedData(service, key)
+
+	ok, err := ss.reencryptEntry(r, service, key, ad, oldKey, newKey)
+	if err != nil {
+		return false, err
+	}
+
+	// The lock of the key also guards its previous slot, see Rotate.
+	prevOK, err := ss.reencryptEntry(r, service, previousKey(key), ad, oldKey, newKey)
+	if err != nil {
+		return ok, fmt.Errorf("failed to rotate the previous value: %w", err)
+	}
+
+	return ok || prevOK, nil
+}
+
+// reencryptEntry encrypts the value of the entry again with the new key, bound to the associated data. The boolean is
+// false if the value is left unchanged.
+func (ss *KeyringStorage[V]) reencryptEntry(
+	r io.Reader,
+	service string,
+	key string,
+	ad []byte,
+	oldKey []byte,
+	newKey []byte,
+) (bool, error) {
+	d, params, targetService, targetKey, err := ss.getRawTarget(service, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// The references are left unchanged, their targets are rotated on their own.
+	if targetService != service || targetKey != key {
+		return false, nil
+	}
+
+	var (
+		rotated string
+		ok      bool
+	)
+
+	if strings.HasPrefix(d, mimePreviousSecret) {
+		prev, expires, err := parsePreviousEntry(d)
+		if err != nil {
+			return false, err
+		}
+
+		if rotated, ok, err = reencrypt(r, prev, ad, oldKey, newKey); err != nil || !ok {
+			return false, err
+		}
+
+		rotated = previousEntry(rotated, expires)
+	} else if rotated, ok, err = reencrypt(r, d, ad, oldKey, newKey); err != nil || !ok {
+		return false, err
+	}
+
+	expires, _, err := expiryFromParams(params)
+	if err != nil {
+		return false, err
+	}
+
+	labels := withExpiryLabel(labelsFromParams(params), expires)
+
+	switch {
+	case isPreviousKey(key):
+		err = ss.setEntry(service, key, rotated, labels)
+
+	case ss.verifyWrite:
+		err = ss.setRawVerified(service, key, rotated, labels)
+
+	default:
+		err = ss.setRaw(service, key, rotated, labels)
+	}
+
+	return err == nil, err
+}
+
+// reencrypt decrypts the encoded data with the old key, and encrypts it with the new key, bound to the associated data.
+// The boolean is false if the data is not encrypted, or is already encrypted with the new key.
+func reencrypt(r io.Reader, d string, ad []byte, oldKey []byte, newKey []byte) (string, bool, error) {
+	params, data, err := parseEncodedData(d)
+	if err != nil {
+		return "", false, err
+	}
+
+	encryption, encrypted := params["encryption"]
+	if !encrypted || params["key-id"] == keyID(newKey) {
+		return d, false, nil
+	}
+
+	if encryption != encryptionAESGCM {
+		return "", false, &headerError{field: "encryption", err: fmt.Errorf("unsupported encryption %q", encryption)} //nolint: goerr113
+	}
+
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	if b, err = decrypt(oldKey, b, ad); err != nil {
+		return "", false, err
+	}
+
+	if b, err = encrypt(r, newKey, b, ad); err != nil {
+		return "", false, err
+	}
+
+	params["key-id"] = keyID(newKey)
+
+	return mime.FormatMediaType(mimeEncodedSecret, params) + "\n" + base64.StdEncoding.EncodeToString(b), true, nil
+}
+
+// Rotate encrypts again with the new passphrase the values of the service that are encrypted with the old passphrase,
+// and returns the number of values that are rotated. The keys of the service are found with List, the decorated
+// storage must implement Lister.
+//
+// The header of the rotated values records the id of the new passphrase, a fingerprint derived from it, so that the
+// values are read whether they are rotated or not during the rotation. The values that are already encrypted with the
+// new passphrase are left unchanged. The storage should be configured with the new passphrase, and the old one in
+// WithDecryptionPassphrases. The rotation goes on when a value fails, the errors are returned together.
+func (s *EncryptedStorage[V]) Rotate(service string, oldKey []byte, newKey []byte) (int, error) {
+	l, ok := s.storage.(Lister)
+	if !ok {
+		return 0, ErrListingNotSupported
+	}
+
+	keys, err := l.List(service)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list data in storage: %w", err)
+	}
+
+	rotated := 0
+
+	for _, key := range keys {
+		ok, rErr := s.rotate(service, key, oldKey, newKey)
+		if rErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to rotate the encryption key of %q: %w", key, rErr))
+
+			continue
+		}
+
+		if ok {
+			rotated++
+		}
+	}
+
+	return rotated, err
+}
+
+// rotate encrypts the value of the key again with the new passphrase. The boolean is false if the value is left
+// unchanged.
+func (s *EncryptedStorage[V]) rotate(service string, key string, oldKey []byte, newKey []byte) (bool, error) {
+	d, err := s.storage.Get(service, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read data from storage: %w", err)
+	}
+
+	e, err := s.parseEncryptedData(d)
+	if err != nil {
+		return false, err
+	}
+
+	id := keyID(newKey)
+	if e.id == id {
+		return false, nil
+	}
+
+	b, err := s.decryptWith(service, key, e, oldKey)
+	if err != nil {
+		// The value that is written with the new passphrase, before the rotation, has no id.
+		if _, nErr := s.decryptWith(service, key, e, newKey); nErr == nil {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if d, err = s.encryptWith(service, key, b, newKey, id); err != nil {
+		return false, err
+	}
+
+	if err := s.storage.Set(service, key, d); err != nil {
+		return false, fmt.Errorf("failed to write data to storage: %w", err)
+	}
+
+	return true, nil
+}
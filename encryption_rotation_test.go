@@ -0,0 +1,166 @@
+package secretstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+var newEncryptionKey = []byte("fedcba9876543210fedcba9876543210")
+
+func TestKeyringStorage_RotateEncryptionKey(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	old := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(64),
+		secretstorage.WithEncryption(encryptionKey),
+	)
+
+	plain := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, old.Set(t.Name(), "single", "p4ssw0rd"))
+	require.NoError(t, old.Set(t.Name(), "multipart", randString(200)))
+	require.NoError(t, plain.Set(t.Name(), "plain", "not encrypted"))
+
+	expected := map[string]string{
+		"single": "p4ssw0rd",
+		"plain":  "not encrypted",
+	}
+
+	expected["multipart"], _ = old.Get(t.Name(), "multipart") //nolint: errcheck
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(64),
+		secretstorage.WithEncryption(newEncryptionKey),
+		secretstorage.WithDecryptionKeys(encryptionKey),
+		secretstorage.WithPlaintextFallback(),
+	)
+
+	// The values encrypted with the old key are read during the rotation.
+	for key, value := range expected {
+		actual, err := s.Get(t.Name(), key)
+		require.NoError(t, err)
+		assert.Equal(t, value, actual)
+	}
+
+	// The new values are encrypted with the new key.
+	require.NoError(t, s.Set(t.Name(), "new", "n3w"))
+
+	expected["new"] = "n3w"
+
+	rotated, err := s.RotateEncryptionKey(t.Name(), encryptionKey, newEncryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rotated)
+
+	// The rotated values are not rotated again.
+	rotated, err = s.RotateEncryptionKey(t.Name(), encryptionKey, newEncryptionKey)
+	require.NoError(t, err)
+	assert.Zero(t, rotated)
+
+	// The old key is not needed anymore.
+	current := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(64),
+		secretstorage.WithEncryption(newEncryptionKey),
+		secretstorage.WithPlaintextFallback(),
+	)
+
+	for key, value := range expected {
+		actual, err := current.Get(t.Name(), key)
+		require.NoError(t, err)
+		assert.Equal(t, value, actual)
+	}
+
+	_, err = old.Get(t.Name(), "single")
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: failed to decrypt data: cipher: message authentication failed")
+}
+
+func TestKeyringStorage_RotateEncryptionKey_KeepsLabelsAndPrevious(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithClock(clock),
+		secretstorage.WithEncryption(encryptionKey),
+	)
+
+	expires := clock.Now().Add(time.Hour)
+
+	require.NoError(t, s.SetWith(t.Name(), "key", "v1",
+		secretstorage.WithLabels(map[string]string{"env": "prod"}),
+		secretstorage.WithExpiry(expires),
+	))
+	require.NoError(t, s.Rotate(t.Name(), "rotated", "v1", time.Hour))
+	require.NoError(t, s.Rotate(t.Name(), "rotated", "v2", time.Hour))
+
+	rotated, err := s.RotateEncryptionKey(t.Name(), encryptionKey, newEncryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rotated)
+
+	require.NoError(t, s.Reconfigure(secretstorage.WithEncryption(newEncryptionKey)))
+
+	labels, err := s.Labels(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod"}, labels)
+
+	actualExpiry, err := s.ExpiresAt(t.Name(), "key")
+	require.NoError(t, err)
+	assert.True(t, expires.Equal(actualExpiry))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", actual)
+
+	previous, ok, err := s.GetPrevious(t.Name(), "rotated")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", previous)
+}
+
+func TestKeyringStorage_RotateEncryptionKey_WrongOldKey(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithEncryption(encryptionKey),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	rotated, err := s.RotateEncryptionKey(t.Name(), []byte("0000000000000000"), newEncryptionKey)
+	require.EqualError(t, err, `failed to rotate the encryption key of "key": failed to decrypt data: cipher: message authentication failed`)
+	assert.Zero(t, rotated)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestKeyringStorage_RotateEncryptionKey_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	_, err := s.RotateEncryptionKey(t.Name(), encryptionKey, []byte("key"))
+	require.ErrorIs(t, err, secretstorage.ErrInvalidOption)
+}
+
+func TestKeyringStorage_RotateEncryptionKey_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)), secretstorage.WithReadOnly())
+
+	_, err := s.RotateEncryptionKey(t.Name(), encryptionKey, newEncryptionKey)
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
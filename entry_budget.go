@@ -0,0 +1,120 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrEntryBudgetExceeded indicates that a write is refused because the storage would use more keyring entries than its
+// budget, see WithEntryBudget.
+var ErrEntryBudgetExceeded = errors.New("entry budget exceeded")
+
+func (ss *KeyringStorage[V]) withEntryBudget(n int) {
+	ss.entryBudget = n
+}
+
+// WithEntryBudget caps the number of keyring entries that the storage uses, the main entries and the pages, across all
+// its keys. A write that would exceed the budget is refused with ErrEntryBudgetExceeded, before anything is written.
+// The entries are freed by the deletes, and by the overwrites with shorter values.
+//
+// Only the entries that the storage writes are counted, from the time the budget is set: the entries that exist
+// before, or that another storage or process writes, are not. A failed write keeps the entries of the old value counted, as
+// they may still exist.
+func WithEntryBudget(n int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withEntryBudget(n)
+	})
+}
+
+// entryUsage tracks the number of keyring entries that the storage uses, per key.
+type entryUsage struct {
+	mu    sync.Mutex
+	total int
+	keys  map[string]map[string]int
+}
+
+// reserve records that the key uses n entries, instead of its current ones, or returns ErrEntryBudgetExceeded if the
+// total would exceed the budget. The returned function reverts the reservation.
+func (u *entryUsage) reserve(budget int, service string, key string, n int) (func(), error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	old := u.keys[service][key]
+
+	if total := u.total - old + n; total > budget {
+		return nil, fmt.Errorf("%w: the data needs %d entries, %d of %d are used", ErrEntryBudgetExceeded, n, u.total-old, budget)
+	}
+
+	u.set(service, key, n)
+
+	return func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+
+		// The old entries may still exist, the new ones that could have been written are counted too.
+		if old > n {
+			u.set(service, key, old)
+		}
+	}, nil
+}
+
+// release records that the key does not use any entry.
+func (u *entryUsage) release(service string, key string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.set(service, key, 0)
+}
+
+// releaseService records that the keys of the service do not use any entry.
+func (u *entryUsage) releaseService(service string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, n := range u.keys[service] {
+		u.total -= n
+	}
+
+	delete(u.keys, service)
+}
+
+func (u *entryUsage) set(service string, key string, n int) {
+	u.total += n - u.keys[service][key]
+
+	if n == 0 {
+		delete(u.keys[service], key)
+
+		return
+	}
+
+	if u.keys[service] == nil {
+		u.keys[service] = make(map[string]int)
+	}
+
+	u.keys[service][key] = n
+}
+
+// countEntries returns the number of keyring entries that the data uses once written with the labels.
+func (ss *KeyringStorage[V]) countEntries(d string, labels map[string]string) int {
+	if len(labeledEntry(labels, d)) <= ss.maxLength {
+		return 1
+	}
+
+	// The data that fits in a single entry, but not with its labels, is split in 2 pages.
+	return max(ss.countPages(len(d)), minPages) + 1
+}
+
+// reserveEntries reserves the entries of the data in the budget, if any. The returned function reverts the reservation.
+func (ss *KeyringStorage[V]) reserveEntries(service string, key string, d string, labels map[string]string) (func(), error) {
+	if ss.entryBudget <= 0 {
+		return func() {}, nil
+	}
+
+	return ss.entries.reserve(ss.entryBudget, service, key, ss.countEntries(d, labels))
+}
+
+// releaseEntries frees the entries of the key in the budget.
+func (ss *KeyringStorage[V]) releaseEntries(service string, key string) {
+	ss.entries.release(service, key)
+}
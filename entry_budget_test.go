@@ -0,0 +1,66 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_EntryBudget(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithEntryBudget(5),
+	)
+
+	// 1 entry.
+	require.NoError(t, s.Set(t.Name(), "a", "short"))
+
+	// 3 entries, 4 in total.
+	require.NoError(t, s.Set(t.Name(), "b", randString(20)))
+
+	// The large multipart write is rejected, nothing is written.
+	err := s.Set(t.Name(), "c", randString(30))
+	require.ErrorIs(t, err, secretstorage.ErrEntryBudgetExceeded)
+	require.EqualError(t, err, "entry budget exceeded: the data needs 4 entries, 4 of 5 are used")
+	assert.Len(t, k.entries(t.Name()), 4)
+
+	// The overwrite of a multipart value with a short one frees its pages, 2 in total.
+	require.NoError(t, s.Set(t.Name(), "b", "short"))
+	require.NoError(t, s.Set(t.Name(), "c", randString(20)))
+
+	err = s.Set(t.Name(), "d", "short")
+	require.ErrorIs(t, err, secretstorage.ErrEntryBudgetExceeded)
+
+	// Deleting frees the entries.
+	require.NoError(t, s.Delete(t.Name(), "c"))
+	require.NoError(t, s.Set(t.Name(), "d", randString(20)))
+
+	// Deleting all the service frees all its entries.
+	require.NoError(t, s.DeleteAll(t.Name()))
+	require.NoError(t, s.Set(t.Name(), "e", randString(40)))
+}
+
+func TestKeyringStorage_EntryBudget_Overwrite(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithEntryBudget(3),
+	)
+
+	// The entries of the old value are reused by the new one.
+	require.NoError(t, s.Set(t.Name(), "key", randString(20)))
+	require.NoError(t, s.Set(t.Name(), "key", randString(20)))
+
+	err := s.Set(t.Name(), "key", randString(30))
+	require.ErrorIs(t, err, secretstorage.ErrEntryBudgetExceeded)
+	require.EqualError(t, err, "entry budget exceeded: the data needs 4 entries, 0 of 3 are used")
+}
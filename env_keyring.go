@@ -0,0 +1,64 @@
+package secretstorage
+
+import (
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+var _ keyring.Keyring = (*EnvKeyring)(nil)
+
+// EnvKeyring is a read-only keyring that reads the secrets from the environment variables, for example in CI. The
+// variable of a secret is named after the prefix, the service and the key, in upper case, and with the characters
+// other than letters and digits replaced by underscores: the key "token" of the service "my-app" with the prefix
+// "SECRET_" is read from SECRET_MY_APP_TOKEN.
+type EnvKeyring struct {
+	prefix string
+}
+
+// NewEnvKeyring creates a new EnvKeyring that reads the environment variables with the given prefix.
+func NewEnvKeyring(prefix string) *EnvKeyring {
+	return &EnvKeyring{prefix: prefix}
+}
+
+// Get gets the secret from the environment variable.
+func (k *EnvKeyring) Get(service, user string) (string, error) {
+	v, ok := os.LookupEnv(k.VariableName(service, user))
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return v, nil
+}
+
+// Set returns ErrReadOnly.
+func (k *EnvKeyring) Set(string, string, string) error {
+	return ErrReadOnly
+}
+
+// Delete returns ErrReadOnly.
+func (k *EnvKeyring) Delete(string, string) error {
+	return ErrReadOnly
+}
+
+// DeleteAll returns ErrReadOnly.
+func (k *EnvKeyring) DeleteAll(string) error {
+	return ErrReadOnly
+}
+
+// VariableName returns the name of the environment variable of the secret.
+func (k *EnvKeyring) VariableName(service, user string) string {
+	return k.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+
+		default:
+			return '_'
+		}
+	}, service+"_"+user)
+}
@@ -0,0 +1,37 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestEnvKeyring_VariableName(t *testing.T) {
+	t.Parallel()
+
+	k := secretstorage.NewEnvKeyring("SECRET_")
+
+	assert.Equal(t, "SECRET_MY_APP_TOKEN", k.VariableName("my-app", "token"))
+	assert.Equal(t, "SECRET_GO_NHAT_IO_API_KEY_0001", k.VariableName("go.nhat.io", "api key-0001"))
+}
+
+func TestEnvKeyring(t *testing.T) { //nolint: paralleltest
+	t.Setenv("SECRET_MY_APP_TOKEN", "value")
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(secretstorage.NewEnvKeyring("SECRET_")))
+
+	actual, err := s.Get("my-app", "token")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+
+	_, err = s.Get("my-app", "unknown")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.ErrorIs(t, s.Set("my-app", "token", "value"), secretstorage.ErrReadOnly)
+	require.ErrorIs(t, s.Delete("my-app", "token"), secretstorage.ErrReadOnly)
+	require.ErrorIs(t, s.DeleteAll("my-app"), secretstorage.ErrReadOnly)
+}
@@ -0,0 +1,106 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/multierr"
+)
+
+// Equal compares the values of every key of the service in the two storages, for example to validate a migration
+// between two backends. It returns whether the storages hold the same data, and the sorted keys that differ: the keys
+// whose values are not equal, and the keys that are missing on one side. The values are compared with reflect.DeepEqual.
+//
+// The keys are listed on the storages that implement Lister, at least one of them must, otherwise
+// ErrListingNotSupported is returned. When only one side is listed, the keys that only exist on the other side are not
+// detected.
+//
+// A key that could not be read on either side is neither equal nor different, the comparison goes on and all the
+// errors are returned together.
+func Equal[V any](service string, a, b Storage[V]) (bool, []string, error) {
+	keys, err := unionKeys(service, a, b)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var diff []string
+
+	for _, key := range keys {
+		same, cErr := equalKey(service, key, a, b)
+		if cErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to compare %q: %w", key, cErr))
+
+			continue
+		}
+
+		if !same {
+			diff = append(diff, key)
+		}
+	}
+
+	return err == nil && len(diff) == 0, diff, err
+}
+
+// unionKeys lists the keys of the service on both storages, if they implement Lister.
+func unionKeys[V any](service string, storages ...Storage[V]) ([]string, error) {
+	listed := false
+	seen := make(map[string]struct{})
+
+	for _, s := range storages {
+		l, ok := s.(Lister)
+		if !ok {
+			continue
+		}
+
+		// A KeyringStorage is not able to list the keys when its keyring is not.
+		keys, err := l.List(service)
+		if errors.Is(err, ErrListingNotSupported) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err //nolint: wrapcheck
+		}
+
+		listed = true
+
+		for _, key := range keys {
+			seen[key] = struct{}{}
+		}
+	}
+
+	if !listed {
+		return nil, ErrListingNotSupported
+	}
+
+	keys := make([]string, 0, len(seen))
+
+	for key := range seen {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// equalKey tells whether the key has the same value in both storages, or is missing in both.
+func equalKey[V any](service string, key string, a, b Storage[V]) (bool, error) {
+	va, errA := a.Get(service, key)
+	if errA != nil && !errors.Is(errA, ErrNotFound) {
+		return false, errA //nolint: wrapcheck
+	}
+
+	vb, errB := b.Get(service, key)
+	if errB != nil && !errors.Is(errB, ErrNotFound) {
+		return false, errB //nolint: wrapcheck
+	}
+
+	if errA != nil || errB != nil {
+		return errA != nil && errB != nil, nil
+	}
+
+	return reflect.DeepEqual(va, vb), nil
+}
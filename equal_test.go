@@ -0,0 +1,96 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+// unlistedStorage hides the List method of the storage.
+type unlistedStorage[V any] struct {
+	secretstorage.Storage[V]
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	a := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithMaxLength(10))
+	// The long value is split into pages in a, and stored in a single entry in b.
+	b := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	long := randString(50)
+
+	for _, s := range []secretstorage.Storage[string]{a, b} {
+		require.NoError(t, s.Set(t.Name(), "a", "value"))
+		require.NoError(t, s.Set(t.Name(), "long", long))
+	}
+
+	equal, diff, err := secretstorage.Equal[string](t.Name(), a, b)
+	require.NoError(t, err)
+	assert.True(t, equal)
+	assert.Empty(t, diff)
+
+	// The different values, and the keys that are missing on either side, are reported.
+	require.NoError(t, b.Set(t.Name(), "a", "another value"))
+	require.NoError(t, a.Set(t.Name(), "only-a", "value"))
+	require.NoError(t, b.Set(t.Name(), "only-b", "value"))
+
+	equal, diff, err = secretstorage.Equal[string](t.Name(), a, b)
+	require.NoError(t, err)
+	assert.False(t, equal)
+	assert.Equal(t, []string{"a", "only-a", "only-b"}, diff)
+}
+
+func TestEqual_OneLister(t *testing.T) {
+	t.Parallel()
+
+	a := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	b := unlistedStorage[string]{secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))}
+
+	require.NoError(t, a.Set(t.Name(), "a", "value"))
+	require.NoError(t, b.Set(t.Name(), "a", "value"))
+	require.NoError(t, b.Set(t.Name(), "only-b", "value"))
+
+	// The keys that only exist on the side that is not listed are not detected.
+	equal, diff, err := secretstorage.Equal[string](t.Name(), a, b)
+	require.NoError(t, err)
+	assert.True(t, equal)
+	assert.Empty(t, diff)
+}
+
+func TestEqual_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	a := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	equal, diff, err := secretstorage.Equal[string](t.Name(), a, a)
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.False(t, equal)
+	assert.Empty(t, diff)
+}
+
+func TestEqual_ReadError(t *testing.T) {
+	t.Parallel()
+
+	a := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	require.NoError(t, a.Set(t.Name(), "a", "value"))
+	require.NoError(t, a.Set(t.Name(), "b", "value"))
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "a").
+			Return("", assert.AnError)
+
+		k.On("Get", t.Name(), "b").
+			Return("another value", nil)
+	})(t)
+
+	equal, diff, err := secretstorage.Equal[string](t.Name(), a, secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k)))
+	require.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, `failed to compare "a"`)
+	assert.False(t, equal)
+	assert.Equal(t, []string{"b"}, diff)
+}
@@ -0,0 +1,19 @@
+package secretstorage
+
+// taggedError makes an error match a sentinel error with errors.Is, without changing its message.
+type taggedError struct {
+	tag error
+	err error
+}
+
+func (e *taggedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *taggedError) Unwrap() []error {
+	return []error{e.tag, e.err}
+}
+
+func tagError(tag, err error) error {
+	return &taggedError{tag: tag, err: err}
+}
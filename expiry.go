@@ -0,0 +1,153 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"strings"
+	"time"
+)
+
+const (
+	expiresParam = "expires"
+	// expiresLabel carries the expiry with the labels down to the header. It is not a valid label name, so it does not
+	// conflict with the labels, see sanitizeLabels.
+	expiresLabel = ":" + expiresParam
+)
+
+// errExpired indicates that the expiry of the value has elapsed, the value is not found.
+var errExpired = fmt.Errorf("%w: the secret has expired", ErrNotFound)
+
+// WithExpiry sets the absolute time at which the value expires, such as the expiry of an OAuth token. It is stored in
+// the header of the value, in RFC 3339 format. Once it elapses, according to the clock, see WithClock, the value is not
+// found, and is deleted the next time it is read with Get. The values without expiry never expire.
+func WithExpiry(t time.Time) SetOption {
+	return setOptionFunc(func(c *setConfig) {
+		c.expires = t
+	})
+}
+
+// ExpiresAt returns the expiry of the value, see WithExpiry, without reading or decoding the value. The time is zero if
+// the value never expires. ErrNotFound is returned if the value does not exist or has expired.
+func (ss *KeyringStorage[V]) ExpiresAt(service string, key string) (time.Time, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		return time.Time{}, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	if isDeleted(d) {
+		return time.Time{}, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", ErrNotFound))
+	}
+
+	if !isMultipart(d) && !isLabeled(d) {
+		return time.Time{}, nil
+	}
+
+	header, _, _ := strings.Cut(d, "\n")
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return time.Time{}, &headerError{field: expiresParam, err: err}
+	}
+
+	if err := ss.checkExpiry(params); err != nil {
+		return time.Time{}, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	expires, _, err := expiryFromParams(params)
+
+	return expires, err
+}
+
+// withExpiryLabel returns the labels with the expiry, if any, so that it is written in the header with them.
+func withExpiryLabel(labels map[string]string, expires time.Time) map[string]string {
+	if expires.IsZero() {
+		return labels
+	}
+
+	result := make(map[string]string, len(labels)+1)
+
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	result[expiresLabel] = expires.UTC().Format(time.RFC3339Nano)
+
+	return result
+}
+
+// expiryFromParams returns the expiry in the parameters of a header. The boolean is false if there is no expiry.
+func expiryFromParams(params map[string]string) (time.Time, bool, error) {
+	v, ok := params[expiresParam]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	expires, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, false, &headerError{field: expiresParam, err: err}
+	}
+
+	return expires, true, nil
+}
+
+// checkExpiry returns errExpired if the expiry in the parameters of a header has elapsed.
+func (ss *KeyringStorage[V]) checkExpiry(params map[string]string) error {
+	expires, ok, err := expiryFromParams(params)
+	if err != nil {
+		return err
+	}
+
+	if ok && !ss.clock.Now().Before(expires) {
+		return errExpired
+	}
+
+	return nil
+}
+
+// checkDataExpiry returns errExpired if the data is in a format with a header, and its expiry has elapsed.
+func (ss *KeyringStorage[V]) checkDataExpiry(d string) error {
+	if !isMultipart(d) && !isLabeled(d) {
+		return nil
+	}
+
+	header, _, _ := strings.Cut(d, "\n")
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return &headerError{field: expiresParam, err: err}
+	}
+
+	return ss.checkExpiry(params)
+}
+
+// deleteExpired locks the key and deletes its value if it has expired. The value is read again under the lock, in case
+// it has been replaced in the meantime.
+func (ss *KeyringStorage[V]) deleteExpired(service string, key string) {
+	if ss.readOnly {
+		return
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, _, err := ss.getRawHeader(service, key); !errors.Is(err, errExpired) {
+		return
+	}
+
+	if _, err := ss.deleteValue(service, key); err != nil && !errors.Is(err, ErrNotFound) {
+		slog.Debug("failed to delete expired secret", "service", service, "key", key, "error", err)
+	}
+}
@@ -0,0 +1,195 @@
+package secretstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Expiry(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	expires := clock.Now().Add(time.Hour)
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithClock(clock))
+
+	require.NoError(t, s.SetWith(t.Name(), "token", "secret", secretstorage.WithExpiry(expires)))
+
+	expected := map[string]string{"token": "application/labeled-secret; expires=\"2020-01-02T04:04:05Z\"\nsecret"}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+
+	expiresAt, err := s.ExpiresAt(t.Name(), "token")
+	require.NoError(t, err)
+	assert.True(t, expires.Equal(expiresAt))
+
+	// The expiry is not a label.
+	labels, err := s.Labels(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	// The value is not found once the expiry elapses, and is deleted when it is read.
+	clock.Add(time.Hour)
+
+	_, err = s.ExpiresAt(t.Name(), "token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err = s.Get(t.Name(), "token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Empty(t, actual)
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Expiry_Multipart(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithClock(clock),
+		secretstorage.WithMaxLength(100),
+	)
+
+	err := s.SetWith(t.Name(), "token", randString(150),
+		secretstorage.WithExpiry(clock.Now().Add(time.Minute)),
+		secretstorage.WithLabels(map[string]string{"owner": "alice"}),
+	)
+	require.NoError(t, err)
+
+	entries := k.entries(t.Name())
+
+	assert.Len(t, entries, 3)
+	assert.Equal(t, `application/multipart-secret; expires="2020-01-02T03:05:05Z"; label-owner=alice; pages=2`, entries["token"])
+
+	labels, err := s.Labels(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "alice"}, labels)
+
+	clock.Add(time.Minute)
+
+	_, err = s.Get(t.Name(), "token")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Expiry_Replaced(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithClock(clock))
+
+	require.NoError(t, s.SetWith(t.Name(), "token", "old", secretstorage.WithExpiry(clock.Now().Add(time.Minute))))
+	require.NoError(t, s.Set(t.Name(), "token", "new"))
+
+	// The values without expiry never expire.
+	clock.Add(time.Hour)
+
+	actual, err := s.Get(t.Name(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual)
+
+	expiresAt, err := s.ExpiresAt(t.Name(), "token")
+	require.NoError(t, err)
+	assert.True(t, expiresAt.IsZero())
+
+	_, err = s.ExpiresAt(t.Name(), "missing")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_Expiry_Reference(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithClock(clock))
+
+	require.NoError(t, s.SetWith("shared", t.Name(), "secret", secretstorage.WithExpiry(clock.Now().Add(time.Minute))))
+	require.NoError(t, s.SetRef(t.Name(), "alias", "shared", t.Name()))
+
+	actual, err := s.Get(t.Name(), "alias")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+
+	// The reference to the expired value is not found, and is kept.
+	clock.Add(time.Minute)
+
+	actual, err = s.Get(t.Name(), "alias")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Empty(t, actual)
+
+	assert.Len(t, k.entries(t.Name()), 1)
+}
+
+func TestKeyringStorage_Expiry_GetReaderAndGetOrReconstruct(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		value    string
+	}{
+		{
+			scenario: "labeled",
+			value:    randString(50),
+		},
+		{
+			scenario: "multipart",
+			value:    randString(150),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newFakeClock()
+
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(newMemoryKeyring()),
+				secretstorage.WithClock(clock),
+				secretstorage.WithMaxLength(100),
+			)
+
+			require.NoError(t, s.SetWith(t.Name(), "token", tc.value, secretstorage.WithExpiry(clock.Now().Add(time.Minute))))
+
+			reconstruct := func(map[int]string) (string, error) {
+				return "", assert.AnError
+			}
+
+			r, err := s.GetReader(t.Name(), "token")
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+
+			actual, err := s.GetOrReconstruct(t.Name(), "token", reconstruct)
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, actual)
+
+			clock.Add(time.Minute)
+
+			_, err = s.GetReader(t.Name(), "token")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			_, err = s.GetOrReconstruct(t.Name(), "token", reconstruct)
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+		})
+	}
+}
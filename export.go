@@ -0,0 +1,139 @@
+package secretstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidExport indicates that a blob could not be imported because it is not an export of a key, or is of an
+// unsupported version.
+var ErrInvalidExport = errors.New("invalid export")
+
+const (
+	exportFormat  = "go.nhat.io/secretstorage/key"
+	exportVersion = 1
+)
+
+// exportedKey is the blob of ExportKey.
+type exportedKey struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// Encrypted tells whether the data is encrypted, see WithEncryption.
+	Encrypted bool `json:"encrypted"`
+	// Service and Key are the ones that the encrypted data is bound to.
+	Service string            `json:"service,omitempty"`
+	Key     string            `json:"key,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	// Data is the reassembled data, as it is encoded by the codec.
+	Data []byte `json:"data"`
+}
+
+// ExportKey exports the value of the key, reassembled, with its labels, as a self-describing and versioned blob that
+// ImportKey ingests, for example on another machine. The value is exported as it is encoded by the codec, and is
+// encrypted if the storage is configured with WithEncryption, the importing storage then needs the same key. Otherwise,
+// the blob holds the secret in clear and must be protected accordingly.
+//
+// The references are followed, the value of their target is exported.
+func (ss *KeyringStorage[V]) ExportKey(service string, key string) ([]byte, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	d, params, targetService, targetKey, err := ss.getRawTarget(service, key)
+	if err != nil {
+		return nil, ss.notFound(err)
+	}
+
+	encoded, _, err := parseEncodedData(d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export data: %w", err)
+	}
+
+	e := exportedKey{
+		Format:    exportFormat,
+		Version:   exportVersion,
+		Encrypted: encoded["encryption"] != "",
+		Data:      []byte(d),
+	}
+
+	if e.Encrypted {
+		e.Service, e.Key = targetService, targetKey
+	}
+
+	if labels := labelsFromParams(params); len(labels) > 0 {
+		e.Labels = labels
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export data: %w", err)
+	}
+
+	return b, nil
+}
+
+// ImportKey imports the blob of ExportKey into the key, with its labels. The value is split into pages according to the
+// configuration of the storage. The blob is rejected if the storage is not able to decode its value, for example
+// because it is encoded with another codec, or encrypted with another key. The encrypted value is encrypted again if it
+// is imported into another key, see WithEncryption.
+func (ss *KeyringStorage[V]) ImportKey(service string, key string, blob []byte) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	var e exportedKey
+
+	if err := json.Unmarshal(blob, &e); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidExport, err)
+	}
+
+	switch {
+	case e.Format != exportFormat:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidExport, e.Format)
+
+	case e.Version != exportVersion:
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidExport, e.Version)
+	}
+
+	labels, err := sanitizeLabels(e.Labels)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidExport, err)
+	}
+
+	boundService, boundKey := service, key
+	if e.Service != "" || e.Key != "" {
+		boundService, boundKey = e.Service, e.Key
+	}
+
+	var v V
+
+	if err := ss.decode(boundService, boundKey, string(e.Data), &v); err != nil {
+		return fmt.Errorf("failed to import data: %w", err)
+	}
+
+	d := string(e.Data)
+
+	// The encrypted data is bound to the key it is exported from, it is encrypted again for the new key.
+	if boundService != service || boundKey != key {
+		if d, err = ss.encode(service, key, v); err != nil {
+			return fmt.Errorf("failed to import data: %w", tagError(ErrMarshal, err))
+		}
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return ss.setRaw(service, key, d, labels)
+}
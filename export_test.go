@@ -0,0 +1,133 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_ExportKey_ImportKey(t *testing.T) {
+	t.Parallel()
+
+	src := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithMaxLength(100))
+
+	value := randString(300)
+	labels := map[string]string{"env": "prod"}
+
+	require.NoError(t, src.SetWith(t.Name(), "key", value, secretstorage.WithLabels(labels)))
+
+	blob, err := src.ExportKey(t.Name(), "key")
+	require.NoError(t, err)
+
+	// The fresh storage splits the value according to its own configuration.
+	k := newMemoryKeyring()
+	dst := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(200))
+
+	require.NoError(t, dst.ImportKey(t.Name(), "imported", blob))
+
+	assert.Len(t, k.entries(t.Name()), 3)
+
+	actual, err := dst.Get(t.Name(), "imported")
+	require.NoError(t, err)
+	assert.Equal(t, value, actual)
+
+	actualLabels, err := dst.Labels(t.Name(), "imported")
+	require.NoError(t, err)
+	assert.Equal(t, labels, actualLabels)
+}
+
+func TestKeyringStorage_ExportKey_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	src := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, src.Set(t.Name(), "key", "p4ssw0rd"))
+
+	blob, err := src.ExportKey(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Contains(t, string(blob), `"encrypted":true`)
+	assert.NotContains(t, string(blob), "p4ssw0rd")
+
+	// The importing storage needs the same key.
+	plain := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	err = plain.ImportKey(t.Name(), "key", blob)
+	require.EqualError(t, err, "failed to import data: failed to decrypt data: no encryption key")
+
+	dst := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, dst.ImportKey(t.Name(), "key", blob))
+
+	actual, err := dst.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+
+	// The value is encrypted again when it is imported into another key.
+	require.NoError(t, dst.ImportKey(t.Name(), "other", blob))
+
+	actual, err = dst.Get(t.Name(), "other")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestKeyringStorage_ExportKey_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	_, err := s.ExportKey(t.Name(), "key")
+
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_ImportKey_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		blob          string
+		expectedError string
+	}{
+		{
+			scenario:      "not json",
+			blob:          "secret",
+			expectedError: "invalid export: invalid character 's' looking for beginning of value",
+		},
+		{
+			scenario:      "unsupported format",
+			blob:          `{"format":"other","version":1}`,
+			expectedError: `invalid export: unsupported format "other"`,
+		},
+		{
+			scenario:      "unsupported version",
+			blob:          `{"format":"go.nhat.io/secretstorage/key","version":2}`,
+			expectedError: "invalid export: unsupported version 2",
+		},
+		{
+			scenario:      "invalid label",
+			blob:          `{"format":"go.nhat.io/secretstorage/key","version":1,"labels":{"not valid":"x"}}`,
+			expectedError: `invalid export: invalid label: "not valid"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			err := s.ImportKey(t.Name(), "key", []byte(tc.blob))
+
+			require.ErrorIs(t, err, secretstorage.ErrInvalidExport)
+			require.EqualError(t, err, tc.expectedError)
+			assert.Empty(t, k.entries(t.Name()))
+		})
+	}
+}
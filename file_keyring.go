@@ -0,0 +1,328 @@
+package secretstorage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+var (
+	_ keyring.Keyring = (*FileKeyring)(nil)
+	_ Lister          = (*FileKeyring)(nil)
+	_ ServiceLister   = (*FileKeyring)(nil)
+	_ Flusher         = (*FileKeyring)(nil)
+)
+
+// FileKeyring is a keyring that stores the secrets in a file, encrypted with AES-GCM. It is meant for the environments
+// without an OS keyring, such as containers. The whole file is read and written on every call.
+type FileKeyring struct {
+	path string
+	aead cipher.AEAD
+	rand io.Reader
+	mu   sync.Mutex
+}
+
+// NewFileKeyring creates a new FileKeyring that stores the secrets in the given file. The key must be 16, 24 or 32
+// bytes long, to select AES-128, AES-192 or AES-256. The file is created when the first secret is written.
+func NewFileKeyring(path string, key []byte, opts ...FileKeyringOption) (*FileKeyring, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	k := &FileKeyring{path: path, aead: aead}
+
+	for _, opt := range opts {
+		opt.applyFileKeyringOption(k)
+	}
+
+	return k, nil
+}
+
+// FileKeyringOption is an option to configure FileKeyring.
+type FileKeyringOption interface {
+	applyFileKeyringOption(k *FileKeyring)
+}
+
+type fileKeyringOptionFunc func(k *FileKeyring)
+
+func (f fileKeyringOptionFunc) applyFileKeyringOption(k *FileKeyring) {
+	f(k)
+}
+
+// WithFileKeyringRandReader sets the source of the nonces, like WithRandReader for KeyringStorage. It is meant for the
+// tests only, crypto/rand.Reader is used by default.
+func WithFileKeyringRandReader(r io.Reader) FileKeyringOption {
+	return fileKeyringOptionFunc(func(k *FileKeyring) {
+		k.rand = r
+	})
+}
+
+// Get gets the secret.
+func (k *FileKeyring) Get(service, user string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := k.load()
+	if err != nil {
+		return "", err
+	}
+
+	v, ok := data[service][user]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return v, nil
+}
+
+// Set sets the secret.
+func (k *FileKeyring) Set(service, user, password string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	if data[service] == nil {
+		data[service] = make(map[string]string)
+	}
+
+	data[service][user] = password
+
+	return k.save(data)
+}
+
+// Delete deletes the secret.
+func (k *FileKeyring) Delete(service, user string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := data[service][user]; !ok {
+		return ErrNotFound
+	}
+
+	delete(data[service], user)
+
+	if len(data[service]) == 0 {
+		delete(data, service)
+	}
+
+	return k.save(data)
+}
+
+// DeleteAll deletes all the secrets of the service.
+func (k *FileKeyring) DeleteAll(service string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := data[service]; !ok {
+		return nil
+	}
+
+	delete(data, service)
+
+	return k.save(data)
+}
+
+// List lists the keys of the service.
+func (k *FileKeyring) List(service string) ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(data[service]))
+
+	for key := range data[service] {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Services lists the services that have at least one key.
+func (k *FileKeyring) Services() ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]string, 0, len(data))
+
+	for service, keys := range data {
+		if len(keys) > 0 {
+			services = append(services, service)
+		}
+	}
+
+	sort.Strings(services)
+
+	return services, nil
+}
+
+// Flush syncs the file, and its directory, to the disk, so that the secrets survive a crash of the system. The writes
+// sync the new file before it replaces the file, so that a crash never leaves it empty, but do not sync the directory
+// on their own, the last writes may be lost.
+func (k *FileKeyring) Flush() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := syncFile(k.path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	// The directory is synced for the rename to be durable, which is not supported on every platform.
+	_ = syncDir(filepath.Dir(k.path)) //nolint: errcheck
+
+	return nil
+}
+
+func (k *FileKeyring) load() (map[string]map[string]string, error) {
+	b, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]map[string]string), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	nonceSize := k.aead.NonceSize()
+	if len(b) < nonceSize {
+		return nil, fmt.Errorf("failed to decrypt file: %w", ErrCorruptSecret)
+	}
+
+	b, err = k.aead.Open(nil, b[:nonceSize], b[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	var data map[string]map[string]string
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+
+	if data == nil {
+		data = make(map[string]map[string]string)
+	}
+
+	return data, nil
+}
+
+// save writes the data to a temporary file that is synced and replaces the file, so that the file is never partially
+// written.
+func (k *FileKeyring) save(data map[string]map[string]string) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode file: %w", err)
+	}
+
+	nonce := make([]byte, k.aead.NonceSize())
+
+	if _, err := io.ReadFull(randReader(k.rand), nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	b = k.aead.Seal(nonce, nonce, b, nil)
+
+	f, err := os.CreateTemp(filepath.Dir(k.path), filepath.Base(k.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	defer os.Remove(f.Name()) //nolint: errcheck
+
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close() //nolint: errcheck
+
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	// The data is on the disk before the rename, otherwise a crash may leave an empty file behind.
+	if err := f.Sync(); err != nil {
+		_ = f.Close() //nolint: errcheck
+
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), k.path); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// syncFile syncs the file, which is opened for writing, because some platforms, such as Windows, do not flush a file
+// that is opened for reading.
+func syncFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0) //nolint: gosec
+	if err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	return syncClose(f)
+}
+
+// syncDir syncs the directory, which can only be opened for reading.
+func syncDir(path string) error {
+	f, err := os.Open(path) //nolint: gosec
+	if err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	return syncClose(f)
+}
+
+func syncClose(f *os.File) error {
+	if err := f.Sync(); err != nil {
+		_ = f.Close() //nolint: errcheck
+
+		return err //nolint: wrapcheck
+	}
+
+	return f.Close() //nolint: wrapcheck
+}
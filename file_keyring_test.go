@@ -0,0 +1,103 @@
+package secretstorage_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestFileKeyring(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secrets")
+	key := bytes.Repeat([]byte{1}, 32)
+
+	k, err := secretstorage.NewFileKeyring(path, key)
+	require.NoError(t, err)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	value := randString(5000)
+
+	require.NoError(t, s.Set(t.Name(), "key", value))
+	require.NoError(t, s.Set(t.Name(), "another key", "value"))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(b), "value")
+
+	// A new keyring reads the same file.
+	k, err = secretstorage.NewFileKeyring(path, key)
+	require.NoError(t, err)
+
+	s = secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, value, actual)
+
+	keys, err := k.List(t.Name())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"another key", "key", "key-0001", "key-0002", "key-0003"}, keys)
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	_, err = s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.ErrorIs(t, s.Delete(t.Name(), "key"), secretstorage.ErrNotFound)
+
+	require.NoError(t, s.DeleteAll(t.Name()))
+
+	keys, err = k.List(t.Name())
+	require.NoError(t, err)
+
+	assert.Empty(t, keys)
+}
+
+func TestFileKeyring_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	k, err := secretstorage.NewFileKeyring(filepath.Join(t.TempDir(), "secrets"), bytes.Repeat([]byte{1}, 16))
+	require.NoError(t, err)
+
+	_, err = k.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, k.DeleteAll(t.Name()))
+}
+
+func TestFileKeyring_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secrets")
+
+	k, err := secretstorage.NewFileKeyring(path, bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	require.NoError(t, k.Set(t.Name(), "key", "value"))
+
+	k, err = secretstorage.NewFileKeyring(path, bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+
+	_, err = k.Get(t.Name(), "key")
+	require.EqualError(t, err, "failed to decrypt file: cipher: message authentication failed")
+}
+
+func TestFileKeyring_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	k, err := secretstorage.NewFileKeyring("secrets", []byte("key"))
+
+	require.EqualError(t, err, "failed to create cipher: crypto/aes: invalid key size 3")
+	assert.Nil(t, k)
+}
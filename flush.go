@@ -0,0 +1,42 @@
+package secretstorage
+
+import "fmt"
+
+// Flusher is an optional interface for storages and keyrings that buffer the writes, or do not make them durable right
+// away, such as FileKeyring. Flush makes the writes that are done so far durable.
+type Flusher interface {
+	Flush() error
+}
+
+// FlushStorage flushes the storage if it implements Flusher, and is a no-op otherwise. It should be called before the
+// process exits, so that the buffered writes are not lost.
+func FlushStorage[V any](s Storage[V]) error {
+	return flush(s)
+}
+
+// Flush flushes the keyring if it implements Flusher, like FileKeyring. It is a no-op otherwise, the writes to the OS
+// keyring are synchronous.
+func (ss *KeyringStorage[V]) Flush() error {
+	defer ss.rlockConfig()()
+
+	f, ok := unwrapKeyring(ss.keyring).(Flusher)
+	if !ok {
+		return nil
+	}
+
+	if err := f.Flush(); err != nil {
+		return fmt.Errorf("failed to flush keyring: %w", err)
+	}
+
+	return nil
+}
+
+// flush flushes the storage or the keyring if it implements Flusher.
+func flush(v any) error {
+	f, ok := v.(Flusher)
+	if !ok {
+		return nil
+	}
+
+	return f.Flush() //nolint: wrapcheck
+}
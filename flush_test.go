@@ -0,0 +1,92 @@
+package secretstorage_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+// flushKeyring implements both keyring.Keyring and secretstorage.Flusher.
+type flushKeyring struct {
+	*mock.Keyring
+	*mock.Flusher
+}
+
+func TestKeyringStorage_Flush_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	require.NoError(t, s.Flush())
+	require.NoError(t, secretstorage.FlushStorage[string](s))
+}
+
+func TestKeyringStorage_Flush_Failure(t *testing.T) {
+	t.Parallel()
+
+	f := mock.NewFlusher(t)
+
+	f.On("Flush").Return(assert.AnError).Once()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(&flushKeyring{Keyring: mock.NopKeyring(t), Flusher: f}),
+	)
+
+	err := s.Flush()
+
+	require.EqualError(t, err, "failed to flush keyring: assert.AnError general error for testing")
+}
+
+func TestFlushStorage_Decorators(t *testing.T) {
+	t.Parallel()
+
+	f := mock.NewFlusher(t)
+
+	f.On("Flush").Return(nil).Times(6)
+
+	newStorage := func() *secretstorage.KeyringStorage[string] {
+		return secretstorage.NewKeyringStorage[string](
+			secretstorage.WithKeyring(&flushKeyring{Keyring: mock.NopKeyring(t), Flusher: f}),
+		)
+	}
+
+	serialized := secretstorage.NewSerializedStorage[string](newStorage())
+
+	t.Cleanup(func() {
+		_ = serialized.Close() //nolint: errcheck
+	})
+
+	require.NoError(t, secretstorage.FlushStorage[string](secretstorage.NewEncryptedStorage[string](newStorage(), []byte("passphrase"))))
+	require.NoError(t, secretstorage.FlushStorage[string](serialized))
+	require.NoError(t, secretstorage.FlushStorage[string](secretstorage.NewTeeStorage[string](newStorage(), newStorage())))
+	require.NoError(t, secretstorage.FlushStorage[string](secretstorage.NewHashedStorage(newStorage())))
+	require.NoError(t, secretstorage.FlushStorage[string](secretstorage.NewValidatingStorage[string](newStorage(), func(string) error { return nil })))
+}
+
+func TestFileKeyring_Flush(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secrets")
+
+	k, err := secretstorage.NewFileKeyring(path, bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	// The file is not written yet.
+	require.NoError(t, k.Flush())
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "key", "value"))
+	require.NoError(t, secretstorage.FlushStorage[string](s))
+
+	actual, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+}
@@ -0,0 +1,118 @@
+package secretstorage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// formatDecoder returns the content of a key, from the data stored in its main entry. The pages are read up to reads
+// at once, see WithPagesPerCall.
+type formatDecoder func(k keyring.Keyring, f pageFormat, reads int, service string, key string, d string) (string, error)
+
+type registeredFormat struct {
+	mediaType string
+	decode    formatDecoder
+}
+
+// formatRegistry maps the media types of the stored formats to their decoders.
+type formatRegistry struct {
+	formats []registeredFormat
+}
+
+func (r *formatRegistry) register(mediaType string, decode formatDecoder) {
+	r.formats = append(r.formats, registeredFormat{mediaType: mediaType, decode: decode})
+}
+
+// lookup returns the decoder of the format of the data, or false if the data is not in any of the registered formats.
+func (r *formatRegistry) lookup(d string) (formatDecoder, bool) {
+	// All the formats are media types of the application type, most of the data is not in any of them.
+	if !strings.HasPrefix(d, mimeApplicationPrefix) {
+		return nil, false
+	}
+
+	for _, f := range r.formats {
+		if strings.HasPrefix(d, f.mediaType) {
+			return f.decode, true
+		}
+	}
+
+	return nil, false
+}
+
+const mimeApplicationPrefix = "application/"
+
+var (
+	// storedFormats are the formats that the storage recognizes when reading a key.
+	storedFormats = newStoredFormats()
+	// targetFormats are the stored formats that the target of a reference is decoded with, see SetRef.
+	targetFormats = newTargetFormats()
+)
+
+func newStoredFormats() *formatRegistry {
+	r := newTargetFormats()
+
+	r.register(mimeSecretReference, decodeReference)
+
+	return r
+}
+
+func newTargetFormats() *formatRegistry {
+	r := &formatRegistry{}
+
+	r.register(mimeMultipartSecret, decodeMultipart)
+	r.register(mimeLabeledSecret, decodeLabeled)
+	r.register(mimeDeletedSecret, decodeDeleted)
+
+	return r
+}
+
+// decodeMultipart reassembles the pages of a multipart data.
+func decodeMultipart(k keyring.Keyring, f pageFormat, reads int, service string, key string, d string) (string, error) {
+	h, err := parseMultipartHeader(d)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.checkPages(f); err != nil {
+		return "", err
+	}
+
+	pages := make([]string, h.pages+1)
+
+	err = forEachPage(h.pages, reads, func(i int) error {
+		p, err := h.readPage(k, f, service, key, i)
+		if err != nil {
+			return fmt.Errorf("failed to read multipart data #%d from keyring: %w", i, err)
+		}
+
+		pages[i] = p
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The pages are all read, the builder is sized to their exact total, including the last page that may be short.
+	length := 0
+
+	for _, p := range pages[1:] {
+		length += len(p)
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(length)
+
+	for _, p := range pages[1:] {
+		sb.WriteString(p)
+	}
+
+	if err := h.checkLength(sb.Len()); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
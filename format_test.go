@@ -0,0 +1,99 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Get_StoredFormats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		entries       map[string]string
+		expected      string
+		expectedError string
+	}{
+		{
+			scenario: "plain data",
+			entries:  map[string]string{"key": "secret"},
+			expected: "secret",
+		},
+		{
+			scenario: "unregistered media type",
+			entries:  map[string]string{"key": "text/plain; charset=utf-8"},
+			expected: "text/plain; charset=utf-8",
+		},
+		{
+			scenario: "multipart",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=2",
+				"key-0001": "hello ",
+				"key-0002": "world",
+			},
+			expected: "hello world",
+		},
+		{
+			scenario: "multipart with generation",
+			entries: map[string]string{
+				"key":        "application/multipart-secret; pages=2; generation=1",
+				"key~1-0001": "hello ",
+				"key~1-0002": "world",
+			},
+			expected: "hello world",
+		},
+		{
+			scenario:      "multipart with invalid params",
+			entries:       map[string]string{"key": "application/multipart-secret; pages="},
+			expectedError: "failed to get params from data: mime: invalid media parameter",
+		},
+		{
+			scenario:      "multipart with invalid pages",
+			entries:       map[string]string{"key": "application/multipart-secret; pages=hello"},
+			expectedError: `failed to get pages from data: strconv.Atoi: parsing "hello": invalid syntax`,
+		},
+		{
+			scenario:      "multipart with too few pages",
+			entries:       map[string]string{"key": "application/multipart-secret; pages=1"},
+			expectedError: "invalid secret pages: 1",
+		},
+		{
+			scenario: "multipart with missing page",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=3",
+				"key-0001": "hello ",
+			},
+			expectedError: "failed to read multipart data #2 from keyring: secret not found in keyring",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+
+			for key, value := range tc.entries {
+				require.NoError(t, k.Set(t.Name(), key, value))
+			}
+
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			actual, err := s.Get(t.Name(), "key")
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
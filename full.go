@@ -0,0 +1,41 @@
+package secretstorage
+
+import "fmt"
+
+// GetFull gets the value for the given key, together with the parameters of the headers that the value is stored
+// with, such as the number of pages of a multipart value or the codec. The value and the parameters are read at once,
+// under the same lock, so they are consistent. The parameters are empty if the value is stored without a header.
+func (ss *KeyringStorage[V]) GetFull(service string, key string) (V, map[string]string, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var result V
+
+	d, params, targetService, targetKey, err := ss.getRawTarget(service, key)
+	if err != nil {
+		return result, nil, ss.notFound(err)
+	}
+
+	codecParams, err := ss.decodeHeader(targetService, targetKey, d, &result)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to unmarshal data read from keyring: %w", err)
+	}
+
+	header := make(map[string]string, len(params)+len(codecParams))
+
+	for k, v := range params {
+		header[k] = v
+	}
+
+	for k, v := range codecParams {
+		header[k] = v
+	}
+
+	return result, header, nil
+}
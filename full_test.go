@@ -0,0 +1,103 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_GetFull(t *testing.T) {
+	t.Parallel()
+
+	multipart := randString(5000)
+
+	testCases := []struct {
+		scenario       string
+		options        []secretstorage.KeyringStorageOption
+		value          string
+		writes         int
+		expectedHeader map[string]string
+	}{
+		{
+			scenario:       "single",
+			value:          "value",
+			expectedHeader: map[string]string{},
+		},
+		{
+			scenario:       "multipart",
+			value:          multipart,
+			expectedHeader: map[string]string{"pages": "3"},
+		},
+		{
+			scenario:       "multipart with atomic swap",
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithAtomicSwap()},
+			value:          multipart,
+			writes:         2,
+			expectedHeader: map[string]string{"pages": "3", "generation": "1"},
+		},
+		{
+			scenario:       "single with codec",
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithCodec(secretstorage.JSONCodec{}), secretstorage.WithForceCodec()},
+			value:          "value",
+			expectedHeader: map[string]string{"codec": "json"},
+		},
+		{
+			scenario:       "multipart with codec",
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithCodec(secretstorage.JSONCodec{}), secretstorage.WithForceCodec()},
+			value:          multipart,
+			expectedHeader: map[string]string{"pages": "3", "codec": "json"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewKeyringStorage[string](
+				append(tc.options, secretstorage.WithKeyring(newMemoryKeyring()))...,
+			)
+
+			for i := 0; i < tc.writes || i == 0; i++ {
+				require.NoError(t, s.Set(t.Name(), "key", tc.value))
+			}
+
+			actual, header, err := s.GetFull(t.Name(), "key")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.value, actual)
+			assert.Equal(t, tc.expectedHeader, header)
+		})
+	}
+}
+
+func TestKeyringStorage_GetFull_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	actual, header, err := s.GetFull(t.Name(), "key")
+
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Empty(t, actual)
+	assert.Nil(t, header)
+}
+
+func TestKeyringStorage_GetFull_UnmarshalError(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "value"))
+
+	s := secretstorage.NewKeyringStorage[custom](secretstorage.WithKeyring(k))
+
+	_, header, err := s.GetFull(t.Name(), "key")
+
+	require.EqualError(t, err, `failed to unmarshal data read from keyring: strconv.Atoi: parsing "value": invalid syntax`)
+	assert.Nil(t, header)
+}
@@ -8,6 +8,7 @@ require (
 	github.com/stretchr/testify v1.10.0
 	github.com/zalando/go-keyring v0.2.6
 	go.uber.org/multierr v1.11.0
+	golang.org/x/crypto v0.28.0
 )
 
 require (
@@ -0,0 +1,183 @@
+package secretstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Group stores a small set of related values, the fields, in a single entry of a KeyringStorage, as a JSON object. The
+// keyring has no transactions across the entries, but a group is read and written as a whole, under the lock of its
+// entry, so the fields of a group change together, see Update.
+//
+// Every write reads, decodes and rewrites the whole entry, the cost grows with the number and the size of the fields,
+// and the entry becomes multipart once it exceeds the max length of the storage, see WithMaxLength. The groups are
+// meant for a handful of small values, such as the settings of an account, not for collections.
+//
+// The entry is encoded like a string value of the storage, so it is encrypted with WithEncryption, for example. The
+// fields must be marshalable to JSON. The labels of the entry are not kept when it is rewritten.
+type Group[V any] struct {
+	storage *KeyringStorage[string]
+	service string
+	key     string
+}
+
+// GetField gets the value of the field. ErrNotFound is returned if the group or the field does not exist.
+func (g *Group[V]) GetField(field string) (V, error) {
+	var zero V
+
+	fields, err := g.Fields()
+	if err != nil {
+		return zero, err
+	}
+
+	v, ok := fields[field]
+	if !ok {
+		return zero, g.storage.notFound(fmt.Errorf("%w: field %q", ErrNotFound, field))
+	}
+
+	return v, nil
+}
+
+// Fields gets the values of all the fields. ErrNotFound is returned if the group does not exist.
+func (g *Group[V]) Fields() (map[string]V, error) {
+	ss := g.storage
+
+	defer ss.rlockConfig()()
+
+	service := ss.serviceOrDefault(g.service)
+
+	mu := ss.mutex(service, g.key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	fields, err := g.read(service)
+	if err != nil {
+		return nil, ss.contextualError(ss.notFound(err), service, g.key)
+	}
+
+	return fields, nil
+}
+
+// SetField sets the value of the field, the other fields are kept. The group is created if it does not exist.
+func (g *Group[V]) SetField(field string, value V) error {
+	return g.Update(func(fields map[string]V) error {
+		fields[field] = value
+
+		return nil
+	})
+}
+
+// DeleteField deletes the field, the other fields are kept. The group is deleted with its last field. ErrNotFound is
+// returned if the group or the field does not exist.
+func (g *Group[V]) DeleteField(field string) error {
+	return g.Update(func(fields map[string]V) error {
+		if _, ok := fields[field]; !ok {
+			return fmt.Errorf("%w: field %q", ErrNotFound, field)
+		}
+
+		delete(fields, field)
+
+		return nil
+	})
+}
+
+// Update reads the fields, calls the function to change them, and writes them back, under the lock of the entry, so
+// that no other call of the storage reads or writes the group in the meantime. The fields are empty if the group does
+// not exist. Nothing is written if the function fails. The group is deleted if no field is left.
+func (g *Group[V]) Update(fn func(fields map[string]V) error) error {
+	ss := g.storage
+
+	defer ss.rlockConfig()()
+
+	service := ss.serviceOrDefault(g.service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	mu := ss.mutex(service, g.key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := g.update(service, fn)
+
+	return ss.contextualError(ss.notFound(err), service, g.key)
+}
+
+func (g *Group[V]) update(service string, fn func(fields map[string]V) error) error {
+	ss := g.storage
+
+	fields, err := g.read(service)
+
+	exists := err == nil
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		fields = make(map[string]V)
+
+	case err != nil:
+		return err
+	}
+
+	if err := fn(fields); err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		if !exists {
+			return nil
+		}
+
+		_, err := ss.deleteValue(service, g.key)
+
+		return err
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal group for writing to keyring: %w", tagError(ErrMarshal, err))
+	}
+
+	d, err := ss.encode(service, g.key, string(b))
+	if err != nil {
+		return fmt.Errorf("failed to marshal group for writing to keyring: %w", tagError(ErrMarshal, err))
+	}
+
+	if ss.verifyWrite {
+		return ss.setRawVerified(service, g.key, d, nil)
+	}
+
+	return ss.setRaw(service, g.key, d, nil)
+}
+
+// read reads and decodes the fields. The caller must lock the entry.
+func (g *Group[V]) read(service string) (map[string]V, error) {
+	d, _, err := g.storage.get(service, g.key)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]V
+
+	if err := json.Unmarshal([]byte(d), &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal group read from keyring: %w", err)
+	}
+
+	if fields == nil {
+		fields = make(map[string]V)
+	}
+
+	return fields, nil
+}
+
+// NewGroup creates a new Group of fields that is stored in the entry of the key in the storage.
+func NewGroup[V any](storage *KeyringStorage[string], service string, key string) *Group[V] {
+	return &Group[V]{
+		storage: storage,
+		service: service,
+		key:     key,
+	}
+}
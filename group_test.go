@@ -0,0 +1,169 @@
+package secretstorage_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestGroup_SetField(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+	g := secretstorage.NewGroup[string](s, t.Name(), "account")
+
+	require.NoError(t, g.SetField("user", "john"))
+	require.NoError(t, g.SetField("password", "secret"))
+
+	actual, err := g.GetField("user")
+	require.NoError(t, err)
+	assert.Equal(t, "john", actual)
+
+	fields, err := g.Fields()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "john", "password": "secret"}, fields)
+
+	// The fields are stored in a single entry.
+	assert.Equal(t, map[string]string{"account": `{"password":"secret","user":"john"}`}, k.entries(t.Name()))
+}
+
+func TestGroup_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	g := secretstorage.NewGroup[int](s, t.Name(), "account")
+
+	_, err := g.Fields()
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	_, err = g.GetField("port")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, g.SetField("port", 8080))
+
+	_, err = g.GetField("timeout")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.ErrorContains(t, err, `field "timeout"`)
+
+	err = g.DeleteField("timeout")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestGroup_DeleteField(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+	g := secretstorage.NewGroup[string](s, t.Name(), "account")
+
+	require.NoError(t, g.SetField("user", "john"))
+	require.NoError(t, g.SetField("password", "secret"))
+
+	require.NoError(t, g.DeleteField("password"))
+
+	fields, err := g.Fields()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "john"}, fields)
+
+	// The group is deleted with its last field.
+	require.NoError(t, g.DeleteField("user"))
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestGroup_Update(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	g := secretstorage.NewGroup[string](s, t.Name(), "account")
+
+	require.NoError(t, g.SetField("user", "john"))
+
+	err := g.Update(func(fields map[string]string) error {
+		fields["user"] = "jane"
+		fields["password"] = "secret"
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	fields, err := g.Fields()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "jane", "password": "secret"}, fields)
+
+	// Nothing is written if the function fails.
+	errUpdate := errors.New("update error")
+
+	err = g.Update(func(fields map[string]string) error {
+		fields["user"] = "jack"
+
+		return errUpdate
+	})
+	require.ErrorIs(t, err, errUpdate)
+
+	fields, err = g.Fields()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "jane", "password": "secret"}, fields)
+}
+
+func TestGroup_Update_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	g := secretstorage.NewGroup[int](s, t.Name(), "counters")
+
+	const n = 20
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, g.SetField(fmt.Sprintf("field-%d", i), i))
+		}(i)
+	}
+
+	wg.Wait()
+
+	// No write is lost, every update reads the fields written by the previous one.
+	fields, err := g.Fields()
+	require.NoError(t, err)
+	assert.Len(t, fields, n)
+}
+
+func TestGroup_Multipart(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(16))
+	g := secretstorage.NewGroup[string](s, t.Name(), "account")
+
+	require.NoError(t, g.SetField("user", "john"))
+	require.NoError(t, g.SetField("password", "a very long secret"))
+
+	assert.Greater(t, len(k.entries(t.Name())), 1)
+
+	actual, err := g.GetField("password")
+	require.NoError(t, err)
+	assert.Equal(t, "a very long secret", actual)
+}
+
+func TestGroup_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithReadOnly())
+	g := secretstorage.NewGroup[string](s, t.Name(), "account")
+
+	err := g.SetField("user", "john")
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
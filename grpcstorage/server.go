@@ -0,0 +1,66 @@
+package grpcstorage
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.nhat.io/secretstorage"
+)
+
+var _ SecretStorageServer = (*server)(nil)
+
+// server serves the secret service with a storage.
+type server struct {
+	UnimplementedSecretStorageServer
+
+	storage secretstorage.Storage[[]byte]
+}
+
+// RegisterStorageServer registers the secret service on the gRPC server, it stores the values in the inner storage, as
+// they are encoded by the clients, for example a secretstorage.KeyringStorage[[]byte].
+func RegisterStorageServer(srv grpc.ServiceRegistrar, inner secretstorage.Storage[[]byte]) {
+	RegisterSecretStorageServer(srv, &server{storage: inner})
+}
+
+func (s *server) Get(_ context.Context, req *GetRequest) (*GetResponse, error) {
+	v, err := s.storage.Get(req.GetService(), req.GetKey())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &GetResponse{Value: v}, nil
+}
+
+func (s *server) Set(_ context.Context, req *SetRequest) (*SetResponse, error) {
+	if err := s.storage.Set(req.GetService(), req.GetKey(), req.GetValue()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &SetResponse{}, nil
+}
+
+func (s *server) Delete(_ context.Context, req *DeleteRequest) (*DeleteResponse, error) {
+	if err := s.storage.Delete(req.GetService(), req.GetKey()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &DeleteResponse{}, nil
+}
+
+// toStatus converts the errors of the storage to the status codes that the clients recognize, see fromStatus.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, secretstorage.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, secretstorage.ErrReadOnly):
+		return status.Error(codes.PermissionDenied, err.Error())
+
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,126 @@
+// Package grpcstorage provides a storage that proxies to a remote secret service over gRPC, and the server of that
+// service, so that the secrets can be shared by multiple processes through a daemon.
+package grpcstorage
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative secretstorage.proto
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.nhat.io/secretstorage"
+)
+
+var _ secretstorage.Storage[any] = (*Storage[any])(nil)
+
+// Storage is a storage that proxies to a remote secret service, see RegisterStorageServer. The values are encoded by the
+// client with the codec, and travel as bytes, so the server does not need to know their types.
+type Storage[V any] struct {
+	client SecretStorageClient
+	codec  secretstorage.Codec
+}
+
+// NewGRPCStorage creates a new Storage that calls the secret service over the connection. By default, the values are
+// encoded with secretstorage.TextCodec.
+func NewGRPCStorage[V any](conn grpc.ClientConnInterface, opts ...Option[V]) *Storage[V] {
+	s := &Storage[V]{
+		client: NewSecretStorageClient(conn),
+		codec:  secretstorage.TextCodec{},
+	}
+
+	for _, opt := range opts {
+		opt.applyOption(s)
+	}
+
+	return s
+}
+
+// Get gets the value for the given key.
+func (s *Storage[V]) Get(service string, key string) (V, error) {
+	return s.GetContext(context.Background(), service, key)
+}
+
+// GetContext is like Get, with the context of the call.
+func (s *Storage[V]) GetContext(ctx context.Context, service string, key string) (V, error) {
+	var result V
+
+	resp, err := s.client.Get(ctx, &GetRequest{Service: service, Key: key})
+	if err != nil {
+		return result, fromStatus(err)
+	}
+
+	if err := s.codec.Unmarshal(resp.GetValue(), &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	return result, nil
+}
+
+// Set sets the value for the given key.
+func (s *Storage[V]) Set(service string, key string, value V) error {
+	return s.SetContext(context.Background(), service, key, value)
+}
+
+// SetContext is like Set, with the context of the call.
+func (s *Storage[V]) SetContext(ctx context.Context, service string, key string, value V) error {
+	b, err := s.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	if _, err := s.client.Set(ctx, &SetRequest{Service: service, Key: key, Value: b}); err != nil {
+		return fromStatus(err)
+	}
+
+	return nil
+}
+
+// Delete deletes the value for the given key.
+func (s *Storage[V]) Delete(service string, key string) error {
+	return s.DeleteContext(context.Background(), service, key)
+}
+
+// DeleteContext is like Delete, with the context of the call.
+func (s *Storage[V]) DeleteContext(ctx context.Context, service string, key string) error {
+	if _, err := s.client.Delete(ctx, &DeleteRequest{Service: service, Key: key}); err != nil {
+		return fromStatus(err)
+	}
+
+	return nil
+}
+
+// fromStatus makes the errors of the server match the errors of secretstorage, see toStatus.
+func fromStatus(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", secretstorage.ErrNotFound, status.Convert(err).Message())
+
+	case codes.PermissionDenied:
+		return fmt.Errorf("%w: %s", secretstorage.ErrReadOnly, status.Convert(err).Message())
+
+	default:
+		return err //nolint: wrapcheck
+	}
+}
+
+// Option is an option to configure Storage.
+type Option[V any] interface {
+	applyOption(s *Storage[V])
+}
+
+type optionFunc[V any] func(s *Storage[V])
+
+func (f optionFunc[V]) applyOption(s *Storage[V]) {
+	f(s)
+}
+
+// WithCodec sets the codec that encodes the values on the client.
+func WithCodec[V any](c secretstorage.Codec) Option[V] {
+	return optionFunc[V](func(s *Storage[V]) {
+		s.codec = c
+	})
+}
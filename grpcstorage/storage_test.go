@@ -0,0 +1,127 @@
+package grpcstorage_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/grpcstorage"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	conn := newConn(t, newInnerStorage(t))
+	s := grpcstorage.NewGRPCStorage[string](conn)
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	_, err = s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	err = s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestStorage_Codec(t *testing.T) {
+	t.Parallel()
+
+	inner := newInnerStorage(t)
+	s := grpcstorage.NewGRPCStorage[credentials](newConn(t, inner), grpcstorage.WithCodec[credentials](secretstorage.JSONCodec{}))
+
+	expected := credentials{Username: "john", Password: "p4ssw0rd"}
+
+	require.NoError(t, s.Set(t.Name(), "key", expected))
+
+	// The value travels as it is encoded by the client.
+	raw, err := inner.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"username":"john","password":"p4ssw0rd"}`, string(raw))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestStorage_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	k, err := secretstorage.NewFileKeyring(filepath.Join(t.TempDir(), "secrets"), make([]byte, 32))
+	require.NoError(t, err)
+
+	inner := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(k), secretstorage.WithReadOnly())
+	s := grpcstorage.NewGRPCStorage[string](newConn(t, inner))
+
+	err = s.Set(t.Name(), "key", "p4ssw0rd")
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
+
+func TestStorage_Context(t *testing.T) {
+	t.Parallel()
+
+	s := grpcstorage.NewGRPCStorage[string](newConn(t, newInnerStorage(t)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.GetContext(ctx, t.Name(), "key")
+	require.ErrorContains(t, err, "context canceled")
+}
+
+func newInnerStorage(t *testing.T) *secretstorage.KeyringStorage[[]byte] {
+	t.Helper()
+
+	k, err := secretstorage.NewFileKeyring(filepath.Join(t.TempDir(), "secrets"), make([]byte, 32))
+	require.NoError(t, err)
+
+	return secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(k))
+}
+
+func newConn(t *testing.T, inner secretstorage.Storage[[]byte]) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+
+	grpcstorage.RegisterStorageServer(srv, inner)
+
+	go srv.Serve(lis) //nolint: errcheck
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close() //nolint: errcheck
+
+		srv.Stop()
+	})
+
+	return conn
+}
@@ -0,0 +1,158 @@
+package secretstorage
+
+import "github.com/zalando/go-keyring"
+
+var (
+	_ keyring.Keyring = (*guardedKeyring)(nil)
+	_ Lister          = (*guardedKeyring)(nil)
+	_ ServiceLister   = (*guardedKeyring)(nil)
+)
+
+// guardedKeyring decorates the keyring configured by the user with the protections configured for the storage. It also
+// normalizes the results of the keyring: the value is discarded when an error is returned.
+type guardedKeyring struct {
+	keyring.Keyring
+
+	breaker       *circuitBreaker
+	semaphore     chan struct{}
+	recoverPanics bool
+}
+
+func (k *guardedKeyring) do(fn func() error) error {
+	var (
+		err      error
+		returned bool
+	)
+
+	if k.breaker != nil {
+		if err := k.breaker.allow(); err != nil {
+			return err
+		}
+
+		defer func() {
+			// The call that panics is a failure, so that the trial call, if it is one, is not left in progress.
+			if !returned {
+				err = ErrKeyringPanic
+			}
+
+			k.breaker.done(err)
+		}()
+	}
+
+	if k.semaphore != nil {
+		k.semaphore <- struct{}{}
+
+		defer func() { <-k.semaphore }()
+	}
+
+	if k.recoverPanics {
+		err = recoverPanic(fn)
+	} else {
+		err = fn()
+	}
+
+	returned = true
+
+	return err
+}
+
+func (k *guardedKeyring) Set(service, user, password string) error {
+	return k.do(func() error {
+		return k.Keyring.Set(service, user, password)
+	})
+}
+
+func (k *guardedKeyring) Get(service, user string) (string, error) {
+	var password string
+
+	err := k.do(func() error {
+		var err error
+
+		password, err = k.Keyring.Get(service, user)
+
+		return err //nolint: wrapcheck
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+func (k *guardedKeyring) Delete(service, user string) error {
+	return k.do(func() error {
+		return k.Keyring.Delete(service, user)
+	})
+}
+
+func (k *guardedKeyring) DeleteAll(service string) error {
+	return k.do(func() error {
+		return k.Keyring.DeleteAll(service)
+	})
+}
+
+func (k *guardedKeyring) List(service string) ([]string, error) {
+	l, ok := k.Keyring.(Lister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	var keys []string
+
+	err := k.do(func() error {
+		var err error
+
+		keys, err = l.List(service)
+
+		return err //nolint: wrapcheck
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (k *guardedKeyring) Services() ([]string, error) {
+	l, ok := k.Keyring.(ServiceLister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	var services []string
+
+	err := k.do(func() error {
+		var err error
+
+		services, err = l.Services()
+
+		return err //nolint: wrapcheck
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// unwrapKeyring returns the keyring configured by the user, without the decorations of the storage.
+func unwrapKeyring(k keyring.Keyring) keyring.Keyring {
+	for {
+		switch d := k.(type) {
+		case *guardedKeyring:
+			k = d.Keyring
+
+		case contextKeyring:
+			k = d.Keyring
+
+		case *hashedKeyring:
+			k = d.Keyring
+
+		case *timeoutKeyring:
+			k = d.Keyring
+
+		default:
+			return k
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package secretstorage
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrHashOnly indicates that the value can not be read because only its hash is stored, see HashedStorage.
+var ErrHashOnly = errors.New("only the hash of the value is stored")
+
+var (
+	_ Storage[string] = (*HashedStorage)(nil)
+	_ Flusher         = (*HashedStorage)(nil)
+	_ PasswordHasher  = BcryptHasher{}
+	_ PasswordHasher  = Argon2Hasher{}
+)
+
+// PasswordHasher hashes the passwords, and verifies the candidates against the hashes, see HashedStorage.
+type PasswordHasher interface {
+	// Hash hashes the password. The hash must record the algorithm and its parameters.
+	Hash(password string) (string, error)
+	// Verify tells whether the candidate matches the hash.
+	Verify(hash string, candidate string) (bool, error)
+	// Recognizes tells whether the hash is produced by the hasher.
+	Recognizes(hash string) bool
+}
+
+// BcryptHasher hashes the passwords with bcrypt, at the given cost, or at bcrypt.DefaultCost if it is 0. Bcrypt only
+// hashes the first 72 bytes of the passwords, the longer passwords are rejected.
+type BcryptHasher struct {
+	Cost int
+}
+
+// Hash hashes the password.
+func (h BcryptHasher) Hash(password string) (string, error) {
+	cost := h.Cost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+
+	b, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err //nolint: wrapcheck
+	}
+
+	return string(b), nil
+}
+
+// Verify tells whether the candidate matches the hash.
+func (BcryptHasher) Verify(hash string, candidate string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(candidate))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err //nolint: wrapcheck
+}
+
+// Recognizes tells whether the hash is a bcrypt hash.
+func (BcryptHasher) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, "$2")
+}
+
+const (
+	argon2HashPrefix = "$argon2id$"
+	// argon2MaxMemory is the highest memory, in KiB, that the hashes are verified with, 1 GiB.
+	argon2MaxMemory = 1024 * 1024
+)
+
+// Argon2Hasher hashes the passwords with Argon2id, with 1 pass over 64 MiB of memory and 4 threads. The hashes are in
+// the PHC string format, such as "$argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>".
+type Argon2Hasher struct {
+	// Rand is the source of the salts, crypto/rand.Reader if it is nil. It is meant for the tests only, like
+	// WithRandReader for KeyringStorage.
+	Rand io.Reader
+}
+
+// Hash hashes the password.
+func (h Argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltSize)
+
+	if _, err := io.ReadFull(randReader(h.Rand), salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	var (
+		memory  uint32 = 64 * 1024
+		time    uint32 = 1
+		threads uint8  = 4
+	)
+
+	key := argon2.IDKey([]byte(password), salt, time, memory, threads, derivedKeySize)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2HashPrefix, argon2.Version, memory, time, threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify tells whether the candidate matches the hash, with the parameters recorded in the hash.
+func (Argon2Hasher) Verify(hash string, candidate string) (bool, error) {
+	parts := strings.Split(strings.TrimPrefix(hash, argon2HashPrefix), "$")
+	if !strings.HasPrefix(hash, argon2HashPrefix) || len(parts) != 4 {
+		return false, fmt.Errorf("%w: malformed argon2id hash", ErrCorruptSecret)
+	}
+
+	var (
+		version        int
+		memory, time   uint32
+		threads        uint8
+		salt, expected []byte
+		err            error
+	)
+
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, fmt.Errorf("%w: unsupported argon2id version %q", ErrCorruptSecret, parts[0])
+	}
+
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, fmt.Errorf("%w: malformed argon2id parameters: %w", ErrCorruptSecret, err)
+	}
+
+	// The parameters come from the stored hash, argon2 panics if they are out of range.
+	if time < 1 || threads < 1 || memory > argon2MaxMemory {
+		return false, fmt.Errorf("%w: invalid argon2id parameters %q", ErrCorruptSecret, parts[1])
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return false, fmt.Errorf("%w: malformed argon2id salt: %w", ErrCorruptSecret, err)
+	}
+
+	if expected, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil || len(expected) == 0 {
+		return false, fmt.Errorf("%w: malformed argon2id hash", ErrCorruptSecret)
+	}
+
+	actual := argon2.IDKey([]byte(candidate), salt, time, memory, threads, uint32(len(expected))) //nolint: gosec
+
+	return subtle.ConstantTimeCompare(actual, expected) == 1, nil
+}
+
+// Recognizes tells whether the hash is an Argon2id hash.
+func (Argon2Hasher) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, argon2HashPrefix)
+}
+
+// previousGetter gets the value of a key before its rotation, see KeyringStorage.Rotate.
+type previousGetter interface {
+	GetPrevious(service string, key string) (string, bool, error)
+}
+
+// HashedStorage stores the hashes of the values, such as the passwords, instead of the values themselves. The values
+// can not be read back, Get fails with ErrHashOnly, they are compared with Verify instead. The hashes are stored as
+// normal secrets in the other storage.
+//
+// By default, the values are hashed with BcryptHasher, see WithPasswordHasher.
+type HashedStorage struct {
+	storage Storage[string]
+	hasher  PasswordHasher
+}
+
+// Get always fails with ErrHashOnly, because the value is not recoverable from its hash.
+func (s *HashedStorage) Get(string, string) (string, error) {
+	return "", ErrHashOnly
+}
+
+// Set hashes the value, and sets the hash in the storage.
+func (s *HashedStorage) Set(service string, key string, value string) error {
+	hash, err := s.hasher.Hash(value)
+	if err != nil {
+		return fmt.Errorf("failed to hash data: %w", err)
+	}
+
+	return s.storage.Set(service, key, hash) //nolint: wrapcheck
+}
+
+// Delete deletes the hash in the storage.
+func (s *HashedStorage) Delete(service string, key string) error {
+	return s.storage.Delete(service, key) //nolint: wrapcheck
+}
+
+// Flush flushes the decorated storage.
+func (s *HashedStorage) Flush() error {
+	return flush(s.storage)
+}
+
+// Verify tells whether the candidate matches the hash of the value. The hash is verified with the hasher that produced
+// it, so the hashes written before a change of hasher are still verified.
+//
+// If the storage keeps the previous values of the rotated keys, such as KeyringStorage, the candidate also matches the
+// previous hash during the grace period of the rotation, see KeyringStorage.Rotate.
+func (s *HashedStorage) Verify(service string, key string, candidate string) (bool, error) {
+	hash, err := s.storage.Get(service, key)
+	if err != nil {
+		return false, err //nolint: wrapcheck
+	}
+
+	if ok, err := s.verify(hash, candidate); ok || err != nil {
+		return ok, err
+	}
+
+	p, ok := s.storage.(previousGetter)
+	if !ok {
+		return false, nil
+	}
+
+	hash, ok, err = p.GetPrevious(service, key)
+	if !ok || err != nil {
+		return false, err //nolint: wrapcheck
+	}
+
+	return s.verify(hash, candidate)
+}
+
+func (s *HashedStorage) verify(hash string, candidate string) (bool, error) {
+	for _, h := range []PasswordHasher{s.hasher, BcryptHasher{}, Argon2Hasher{}} {
+		if h.Recognizes(hash) {
+			return h.Verify(hash, candidate) //nolint: wrapcheck
+		}
+	}
+
+	return false, fmt.Errorf("%w: unsupported hash", ErrCorruptSecret)
+}
+
+// NewHashedStorage creates a new HashedStorage that stores the hashes in the storage.
+func NewHashedStorage(storage Storage[string], opts ...HashedStorageOption) *HashedStorage {
+	s := &HashedStorage{
+		storage: storage,
+		hasher:  BcryptHasher{},
+	}
+
+	for _, opt := range opts {
+		opt.applyHashedStorageOption(s)
+	}
+
+	return s
+}
+
+// HashedStorageOption is an option to configure HashedStorage.
+type HashedStorageOption interface {
+	applyHashedStorageOption(s *HashedStorage)
+}
+
+type hashedStorageOptionFunc func(s *HashedStorage)
+
+func (f hashedStorageOptionFunc) applyHashedStorageOption(s *HashedStorage) {
+	f(s)
+}
+
+// WithPasswordHasher sets the hasher of the values, such as BcryptHasher or Argon2Hasher.
+func WithPasswordHasher(h PasswordHasher) HashedStorageOption {
+	return hashedStorageOptionFunc(func(s *HashedStorage) {
+		s.hasher = h
+	})
+}
@@ -0,0 +1,144 @@
+package secretstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestHashedStorage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		hasher   secretstorage.PasswordHasher
+		expected string
+	}{
+		{
+			scenario: "bcrypt",
+			hasher:   secretstorage.BcryptHasher{Cost: 4},
+			expected: "$2a$04$",
+		},
+		{
+			scenario: "argon2",
+			hasher:   secretstorage.Argon2Hasher{},
+			expected: "$argon2id$v=19$m=65536,t=1,p=4$",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+			s := secretstorage.NewHashedStorage(inner, secretstorage.WithPasswordHasher(tc.hasher))
+
+			require.NoError(t, s.Set(t.Name(), "password", "p4ssw0rd"))
+
+			// The hash is a normal secret.
+			hash, err := inner.Get(t.Name(), "password")
+			require.NoError(t, err)
+			assert.Contains(t, hash, tc.expected)
+			assert.NotContains(t, hash, "p4ssw0rd")
+
+			ok, err := s.Verify(t.Name(), "password", "p4ssw0rd")
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = s.Verify(t.Name(), "password", "p4ssw0rD")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			ok, err = s.Verify(t.Name(), "password", "")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			actual, err := s.Get(t.Name(), "password")
+			require.ErrorIs(t, err, secretstorage.ErrHashOnly)
+			assert.Empty(t, actual)
+
+			require.NoError(t, s.Delete(t.Name(), "password"))
+
+			_, err = s.Verify(t.Name(), "password", "p4ssw0rd")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+		})
+	}
+}
+
+func TestHashedStorage_Hashers(t *testing.T) {
+	t.Parallel()
+
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+	bcrypt := secretstorage.NewHashedStorage(inner, secretstorage.WithPasswordHasher(secretstorage.BcryptHasher{Cost: 4}))
+	argon2 := secretstorage.NewHashedStorage(inner, secretstorage.WithPasswordHasher(secretstorage.Argon2Hasher{}))
+
+	require.NoError(t, bcrypt.Set(t.Name(), "password", "p4ssw0rd"))
+
+	// The hash of another hasher is still verified.
+	ok, err := argon2.Verify(t.Name(), "password", "p4ssw0rd")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// The hashes that are not recognized are rejected.
+	require.NoError(t, inner.Set(t.Name(), "password", "p4ssw0rd"))
+
+	ok, err = argon2.Verify(t.Name(), "password", "p4ssw0rd")
+	require.ErrorIs(t, err, secretstorage.ErrCorruptSecret)
+	assert.False(t, ok)
+
+	for _, hash := range []string{
+		"$argon2id$v=19$m=65536,t=1,p=4$c2FsdA",
+		"$argon2id$v=19$m=65536,t=0,p=4$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=1,p=0$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=4294967295,t=1,p=4$c2FsdA$aGFzaA",
+	} {
+		require.NoError(t, inner.Set(t.Name(), "password", hash))
+
+		ok, err = argon2.Verify(t.Name(), "password", "p4ssw0rd")
+		require.ErrorIs(t, err, secretstorage.ErrCorruptSecret, hash)
+		assert.False(t, ok, hash)
+	}
+}
+
+func TestHashedStorage_Rotate(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithClock(clock))
+	hasher := secretstorage.BcryptHasher{Cost: 4}
+	s := secretstorage.NewHashedStorage(inner, secretstorage.WithPasswordHasher(hasher))
+
+	require.NoError(t, s.Set(t.Name(), "api-key", "old"))
+
+	hash, err := hasher.Hash("new")
+	require.NoError(t, err)
+	require.NoError(t, inner.Rotate(t.Name(), "api-key", hash, time.Hour))
+
+	// Both values are accepted during the grace period.
+	for _, candidate := range []string{"old", "new"} {
+		ok, err := s.Verify(t.Name(), "api-key", candidate)
+		require.NoError(t, err)
+		assert.True(t, ok, candidate)
+	}
+
+	ok, err := s.Verify(t.Name(), "api-key", "other")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Only the new value is accepted after it.
+	clock.Add(time.Hour)
+
+	ok, err = s.Verify(t.Name(), "api-key", "old")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = s.Verify(t.Name(), "api-key", "new")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
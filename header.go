@@ -0,0 +1,114 @@
+package secretstorage
+
+import (
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// multipartHeader is stored in place of a data that is too long, it tells how to find the pages of the data.
+type multipartHeader struct {
+	pages      int
+	generation int
+	// length is the length of the data, 0 if it is not stored.
+	length int
+	labels map[string]string
+}
+
+// pageKey returns the key of a page of the data.
+func (h multipartHeader) pageKey(f pageFormat, key string, page int) string {
+	if h.generation > 0 {
+		key = fmt.Sprintf("%s~%d", key, h.generation)
+	}
+
+	return f.format(key, page)
+}
+
+// checkPages returns ErrInvalidPageCount if the data has less than 2 pages, or more pages than the page format
+// accommodates. The header of a tiny data with labels has less pages, or none if the data is empty.
+func (h multipartHeader) checkPages(f pageFormat) error {
+	minPages := minPages
+	if len(h.labels) > 0 {
+		minPages = 0
+	}
+
+	if h.pages < minPages || h.pages > f.maxPages() {
+		return fmt.Errorf("%w: %d", ErrInvalidPageCount, h.pages)
+	}
+
+	return nil
+}
+
+// checkLength returns ErrCorruptSecret if the length of the reassembled data is not the stored one.
+func (h multipartHeader) checkLength(length int) error {
+	if h.length > 0 && length != h.length {
+		return fmt.Errorf("%w: the length is %d, expected %d", ErrCorruptSecret, length, h.length)
+	}
+
+	return nil
+}
+
+func (h multipartHeader) String() string {
+	params := map[string]string{"pages": strconv.Itoa(h.pages)}
+
+	if h.generation > 0 {
+		params["generation"] = strconv.Itoa(h.generation)
+	}
+
+	if h.length > 0 {
+		params["length"] = strconv.Itoa(h.length)
+	}
+
+	return mime.FormatMediaType(mimeMultipartSecret, labelParams(params, h.labels))
+}
+
+func isMultipart(d string) bool {
+	return strings.HasPrefix(d, mimeMultipartSecret)
+}
+
+func parseMultipartHeader(d string) (multipartHeader, error) {
+	_, params, err := mime.ParseMediaType(d)
+	if err != nil {
+		return multipartHeader{}, &headerError{field: "params", err: err}
+	}
+
+	pages, err := strconv.Atoi(params["pages"])
+	if err != nil {
+		return multipartHeader{}, &headerError{field: "pages", err: err}
+	}
+
+	h := multipartHeader{pages: pages}
+
+	if g, ok := params["generation"]; ok {
+		if h.generation, err = strconv.Atoi(g); err != nil {
+			return multipartHeader{}, &headerError{field: "generation", err: err}
+		}
+	}
+
+	if l, ok := params["length"]; ok {
+		if h.length, err = strconv.Atoi(l); err != nil {
+			return multipartHeader{}, &headerError{field: "length", err: err}
+		}
+	}
+
+	if labels := labelsFromParams(params); len(labels) > 0 {
+		h.labels = labels
+	}
+
+	return h, nil
+}
+
+// headerError is returned when a field of the header could not be parsed.
+type headerError struct {
+	field string
+	err   error
+}
+
+func (e *headerError) Error() string {
+	return fmt.Sprintf("failed to get %s from data: %s", e.field, e.err)
+}
+
+func (e *headerError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,194 @@
+package secretstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const keyNameSuffix = ".key"
+
+func (ss *KeyringStorage[V]) withKeyHasher(hash func(string) string) {
+	if hash == nil {
+		hash = sha256Key
+	}
+
+	ss.keyHasher = hash
+}
+
+// WithKeyHasher hashes the names of the keyring entries, including the pages, before every call to the keyring, for
+// the keyrings that limit the length of the names. If the hash function is nil, the names are hashed with SHA-256 and
+// encoded in hex.
+//
+// If the keyring implements Lister, the original name of every entry is stored in an extra entry, so that the entries
+// can still be listed.
+func WithKeyHasher(hash func(string) string) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withKeyHasher(hash)
+	})
+}
+
+func sha256Key(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	_ keyring.Keyring = (*hashedKeyring)(nil)
+	_ Lister          = (*hashedKeyring)(nil)
+	_ ServiceLister   = (*hashedKeyring)(nil)
+	_ ContextKeyring  = (*hashedKeyring)(nil)
+	_ OptionsKeyring  = (*hashedKeyring)(nil)
+)
+
+// hashedKeyring hashes the names of the entries. When the keyring is able to list its entries, the original name of
+// an entry is stored in the entry of the hash with the ".key" suffix.
+//
+// The context and the options of the backend are passed to the keyring, see ContextKeyring and OptionsKeyring. The
+// options are ignored if the keyring does not accept them.
+type hashedKeyring struct {
+	keyring.Keyring
+
+	hash func(string) string
+}
+
+func (k *hashedKeyring) indexed() bool {
+	_, ok := k.Keyring.(Lister)
+
+	return ok
+}
+
+func (k *hashedKeyring) Get(service, user string) (string, error) {
+	return k.Keyring.Get(service, k.hash(user)) //nolint: wrapcheck
+}
+
+func (k *hashedKeyring) GetContext(ctx context.Context, service, user string) (string, error) {
+	return contextKeyring{ctx: ctx, Keyring: k.Keyring}.Get(service, k.hash(user))
+}
+
+func (k *hashedKeyring) GetWithOptions(service, user string, opts any) (string, error) {
+	if ok, isOptionsKeyring := k.Keyring.(OptionsKeyring); isOptionsKeyring {
+		return ok.GetWithOptions(service, k.hash(user), opts) //nolint: wrapcheck
+	}
+
+	return k.Get(service, user)
+}
+
+func (k *hashedKeyring) Set(service, user, password string) error {
+	return k.set(service, user, password, k.Keyring.Set)
+}
+
+func (k *hashedKeyring) SetContext(ctx context.Context, service, user, password string) error {
+	return k.set(service, user, password, contextKeyring{ctx: ctx, Keyring: k.Keyring}.Set)
+}
+
+func (k *hashedKeyring) SetWithOptions(service, user, password string, opts any) error {
+	ok, isOptionsKeyring := k.Keyring.(OptionsKeyring)
+	if !isOptionsKeyring {
+		return k.Set(service, user, password)
+	}
+
+	return k.set(service, user, password, func(service, user, password string) error {
+		return ok.SetWithOptions(service, user, password, opts)
+	})
+}
+
+// set writes the entry of the hash with the function, and its original name if the keyring is able to list its
+// entries.
+func (k *hashedKeyring) set(service, user, password string, set func(service, user, password string) error) error {
+	h := k.hash(user)
+
+	if k.indexed() {
+		if err := set(service, h+keyNameSuffix, user); err != nil {
+			return err
+		}
+	}
+
+	return set(service, h, password)
+}
+
+func (k *hashedKeyring) Delete(service, user string) error {
+	return k.delete(service, user, k.Keyring.Delete)
+}
+
+func (k *hashedKeyring) DeleteContext(ctx context.Context, service, user string) error {
+	return k.delete(service, user, contextKeyring{ctx: ctx, Keyring: k.Keyring}.Delete)
+}
+
+func (k *hashedKeyring) DeleteWithOptions(service, user string, opts any) error {
+	ok, isOptionsKeyring := k.Keyring.(OptionsKeyring)
+	if !isOptionsKeyring {
+		return k.Delete(service, user)
+	}
+
+	return k.delete(service, user, func(service, user string) error {
+		return ok.DeleteWithOptions(service, user, opts)
+	})
+}
+
+// delete deletes the entry of the hash with the function, and its original name if the keyring is able to list its
+// entries.
+func (k *hashedKeyring) delete(service, user string, del func(service, user string) error) error {
+	h := k.hash(user)
+
+	if err := del(service, h); err != nil {
+		return err
+	}
+
+	if k.indexed() {
+		if err := del(service, h+keyNameSuffix); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Services lists the services, their names are not hashed.
+func (k *hashedKeyring) Services() ([]string, error) {
+	l, ok := k.Keyring.(ServiceLister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return l.Services() //nolint: wrapcheck
+}
+
+// List returns the original names of the entries. The entries without an original name are skipped.
+func (k *hashedKeyring) List(service string) ([]string, error) {
+	l, ok := k.Keyring.(Lister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	entries, err := l.List(service)
+	if err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+
+	names := make([]string, 0, len(entries)/2)
+
+	for _, e := range entries {
+		if !strings.HasSuffix(e, keyNameSuffix) {
+			continue
+		}
+
+		name, err := k.Keyring.Get(service, e)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err //nolint: wrapcheck
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,165 @@
+package secretstorage_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+var errNameTooLong = errors.New("name too long")
+
+// limitedKeyring is a memoryKeyring that rejects the long names.
+type limitedKeyring struct {
+	*memoryKeyring
+}
+
+func (k *limitedKeyring) Set(service, user, password string) error {
+	if len(user) > 255 {
+		return errNameTooLong
+	}
+
+	return k.memoryKeyring.Set(service, user, password)
+}
+
+func TestKeyringStorage_KeyHasher(t *testing.T) {
+	t.Parallel()
+
+	key := strings.Repeat("k", 300)
+	value := randString(5000)
+
+	k := &limitedKeyring{memoryKeyring: newMemoryKeyring()}
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.Set(t.Name(), key, "value")
+	require.ErrorIs(t, err, errNameTooLong)
+
+	s = secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithKeyHasher(nil),
+	)
+
+	require.NoError(t, s.Set(t.Name(), key, value))
+	require.NoError(t, s.Set(t.Name(), "short", "value"))
+
+	for name := range k.entries(t.Name()) {
+		assert.LessOrEqual(t, len(name), 68)
+		assert.NotContains(t, name, "kkkk")
+	}
+
+	actual, err := s.Get(t.Name(), key)
+	require.NoError(t, err)
+
+	assert.Equal(t, value, actual)
+
+	all, err := s.GetAll(t.Name())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{key: value, "short": "value"}, all)
+
+	require.NoError(t, s.Delete(t.Name(), key))
+	require.NoError(t, s.Delete(t.Name(), "short"))
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_KeyHasher_Custom(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "KEY").
+			Return("value", nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithKeyHasher(strings.ToUpper),
+	)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_KeyHasher_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(mock.NopKeyring(t)),
+		secretstorage.WithKeyHasher(nil),
+	)
+
+	actual, err := s.GetAll(t.Name())
+
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.Nil(t, actual)
+}
+
+func TestKeyringStorage_KeyHasher_BackendOptions(t *testing.T) {
+	t.Parallel()
+
+	k := newOptionsKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithKeyHasher(func(s string) string { return "h-" + s }),
+	)
+
+	opts := keychainOptions{RequireBiometry: true}
+	ctx := secretstorage.ContextWithBackendOptions(context.Background(), opts)
+
+	require.NoError(t, s.SetContext(ctx, t.Name(), "key", "value"))
+
+	actual, err := s.GetContext(ctx, t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	require.NoError(t, s.DeleteContext(ctx, t.Name(), "key"))
+
+	calls := k.recorded()
+	require.NotEmpty(t, calls)
+
+	for _, c := range calls {
+		// The names are hashed, and so are the entries of the original names.
+		assert.True(t, strings.HasPrefix(c.user, "h-"), c.user)
+		assert.Equal(t, opts, c.opts)
+	}
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_KeyHasher_ContextKeyring(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	ck := mock.NewContextKeyring(t)
+
+	ck.On("SetContext", ctx, t.Name(), "h-key", "value").
+		Return(nil).Once()
+
+	ck.On("GetContext", ctx, t.Name(), "h-key").
+		Return("value", nil).Once()
+
+	// The mock fails the test if the methods without context are called.
+	k := &bothKeyring{Keyring: mock.NopKeyring(t), ContextKeyring: ck}
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithKeyHasher(func(s string) string { return "h-" + s }),
+		secretstorage.WithNoPreDelete(),
+	)
+
+	require.NoError(t, s.SetContext(ctx, t.Name(), "key", "value"))
+
+	actual, err := s.GetContext(ctx, t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+}
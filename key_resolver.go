@@ -0,0 +1,68 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownKeyID indicates that a KeyResolver does not know the id of a key.
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+var (
+	_ KeyResolver = KeyResolverFunc(nil)
+	_ KeyResolver = StaticKeyResolver(nil)
+)
+
+// KeyResolver resolves the passphrases of EncryptedStorage by their ids, see WithKeyResolver.
+type KeyResolver interface {
+	// ResolveKey returns the passphrase of the id. It returns ErrUnknownKeyID if the id is not known.
+	ResolveKey(id string) ([]byte, error)
+}
+
+// KeyResolverFunc is a function that implements KeyResolver.
+type KeyResolverFunc func(id string) ([]byte, error)
+
+// ResolveKey calls the function.
+func (f KeyResolverFunc) ResolveKey(id string) ([]byte, error) {
+	return f(id)
+}
+
+// StaticKeyResolver resolves the passphrases from a map of their ids.
+type StaticKeyResolver map[string][]byte
+
+// ResolveKey returns the passphrase of the id, or ErrUnknownKeyID if it is not in the map.
+func (r StaticKeyResolver) ResolveKey(id string) ([]byte, error) {
+	passphrase, ok := r[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, id)
+	}
+
+	return passphrase, nil
+}
+
+// WithKeyResolver resolves the passphrases of EncryptedStorage by their ids, for the rotation of the passphrases, or
+// the setups with several of them. The new values are encrypted with the passphrase of the active id, that is recorded
+// in the header of the data, and the values are decrypted with the passphrase of the id in their header. The values
+// that are written without a resolver have no id, they are still decrypted with the passphrase of NewEncryptedStorage.
+//
+// The writes and the reads fail with ErrUnknownKeyID if the resolver does not know the id.
+func WithKeyResolver[V any](r KeyResolver, activeKeyID string) EncryptedStorageOption[V] {
+	return encryptedStorageOptionFunc[V](func(s *EncryptedStorage[V]) {
+		s.resolver = r
+		s.activeKeyID = activeKeyID
+	})
+}
+
+// resolveKey returns the passphrase of the id.
+func (s *EncryptedStorage[V]) resolveKey(id string) ([]byte, error) {
+	if s.resolver == nil {
+		return nil, fmt.Errorf("%w: %q, no key resolver is configured", ErrUnknownKeyID, id)
+	}
+
+	passphrase, err := s.resolver.ResolveKey(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve key: %w", err)
+	}
+
+	return passphrase, nil
+}
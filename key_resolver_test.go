@@ -0,0 +1,115 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestEncryptedStorage_KeyResolver(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	keys := secretstorage.StaticKeyResolver{
+		"2023": []byte("old passphrase"),
+		"2024": []byte("new passphrase"),
+	}
+
+	// The values written without a resolver have no key id.
+	legacy := secretstorage.NewEncryptedStorage[string](inner, []byte("legacy passphrase"))
+
+	require.NoError(t, legacy.Set(t.Name(), "legacy", "l3gacy"))
+
+	old := secretstorage.NewEncryptedStorage[string](inner, []byte("legacy passphrase"),
+		secretstorage.WithKeyResolver[string](keys, "2023"),
+	)
+
+	require.NoError(t, old.Set(t.Name(), "old", "0ld"))
+	assert.Contains(t, k.entries(t.Name())["old"], "key-id=2023")
+
+	s := secretstorage.NewEncryptedStorage[string](inner, []byte("legacy passphrase"),
+		secretstorage.WithKeyResolver[string](keys, "2024"),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "new", "n3w"))
+	assert.Contains(t, k.entries(t.Name())["new"], "key-id=2024")
+
+	for key, expected := range map[string]string{"legacy": "l3gacy", "old": "0ld", "new": "n3w"} {
+		actual, err := s.Get(t.Name(), key)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	// The ids are resolved, the passphrase of the storage is not used.
+	other := secretstorage.NewEncryptedStorage[string](inner, []byte("another passphrase"),
+		secretstorage.WithKeyResolver[string](keys, "2024"),
+	)
+
+	actual, err := other.Get(t.Name(), "old")
+	require.NoError(t, err)
+	assert.Equal(t, "0ld", actual)
+}
+
+func TestEncryptedStorage_KeyResolver_UnknownKeyID(t *testing.T) {
+	t.Parallel()
+
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	s := secretstorage.NewEncryptedStorage[string](inner, nil,
+		secretstorage.WithKeyResolver[string](secretstorage.StaticKeyResolver{"2024": []byte("passphrase")}, "2024"),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	// The key id of the data is not known anymore.
+	rotated := secretstorage.NewEncryptedStorage[string](inner, nil,
+		secretstorage.WithKeyResolver[string](secretstorage.StaticKeyResolver{"2025": []byte("passphrase")}, "2025"),
+	)
+
+	_, err := rotated.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrUnknownKeyID)
+	assert.EqualError(t, err, `failed to resolve key: unknown key id: "2024"`)
+
+	// The data has a key id, but there is no resolver.
+	plain := secretstorage.NewEncryptedStorage[string](inner, []byte("passphrase"))
+
+	_, err = plain.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrUnknownKeyID)
+
+	// The active key id is not known.
+	unknown := secretstorage.NewEncryptedStorage[string](inner, nil,
+		secretstorage.WithKeyResolver[string](secretstorage.StaticKeyResolver{}, "2026"),
+	)
+
+	err = unknown.Set(t.Name(), "key", "p4ssw0rd")
+	require.ErrorIs(t, err, secretstorage.ErrUnknownKeyID)
+}
+
+func TestEncryptedStorage_KeyResolverFunc(t *testing.T) {
+	t.Parallel()
+
+	inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	var resolved []string
+
+	r := secretstorage.KeyResolverFunc(func(id string) ([]byte, error) {
+		resolved = append(resolved, id)
+
+		return []byte("passphrase of " + id), nil
+	})
+
+	s := secretstorage.NewEncryptedStorage[string](inner, nil, secretstorage.WithKeyResolver[string](r, "kms-1"))
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+
+	assert.Equal(t, []string{"kms-1", "kms-1"}, resolved)
+}
@@ -0,0 +1,38 @@
+package secretstorage
+
+// KeyedStorage stores the values by a key of any comparable type, such as a struct, in another storage. The mapper
+// derives the service and the key of the underlying storage from the key, so that it is done consistently across the
+// call sites. The mapper must map the distinct keys to distinct services or keys, otherwise they collide.
+type KeyedStorage[K comparable, V any] struct {
+	storage Storage[V]
+	mapper  func(K) (service string, key string)
+}
+
+// Get gets the value of the key.
+func (s *KeyedStorage[K, V]) Get(k K) (V, error) {
+	service, key := s.mapper(k)
+
+	return s.storage.Get(service, key) //nolint: wrapcheck
+}
+
+// Set sets the value of the key.
+func (s *KeyedStorage[K, V]) Set(k K, value V) error {
+	service, key := s.mapper(k)
+
+	return s.storage.Set(service, key, value) //nolint: wrapcheck
+}
+
+// Delete deletes the value of the key.
+func (s *KeyedStorage[K, V]) Delete(k K) error {
+	service, key := s.mapper(k)
+
+	return s.storage.Delete(service, key) //nolint: wrapcheck
+}
+
+// NewKeyedStorage creates a new KeyedStorage.
+func NewKeyedStorage[K comparable, V any](storage Storage[V], mapper func(K) (service string, key string)) *KeyedStorage[K, V] {
+	return &KeyedStorage[K, V]{
+		storage: storage,
+		mapper:  mapper,
+	}
+}
@@ -0,0 +1,76 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+type secretID struct {
+	Tenant   string
+	Resource string
+	Field    string
+}
+
+func secretIDMapper(id secretID) (string, string) {
+	return "tenant/" + id.Tenant, fmt.Sprintf("%q/%q", id.Resource, id.Field)
+}
+
+func TestKeyedStorage(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyedStorage[secretID, string](
+		secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k)),
+		secretIDMapper,
+	)
+
+	ids := []secretID{
+		{Tenant: "acme", Resource: "db", Field: "password"},
+		{Tenant: "acme", Resource: "db/password", Field: ""},
+		{Tenant: "acme", Resource: "db", Field: "user"},
+		{Tenant: "globex", Resource: "db", Field: "password"},
+	}
+
+	for i, id := range ids {
+		require.NoError(t, s.Set(id, fmt.Sprintf("value %d", i)))
+	}
+
+	for i, id := range ids {
+		actual, err := s.Get(id)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("value %d", i), actual)
+	}
+
+	assert.Equal(t, map[string]string{
+		`"db"/"password"`:  "value 0",
+		`"db/password"/""`: "value 1",
+		`"db"/"user"`:      "value 2",
+	}, k.entries("tenant/acme"))
+
+	require.NoError(t, s.Delete(ids[0]))
+
+	_, err := s.Get(ids[0])
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	actual, err := s.Get(ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, "value 1", actual)
+}
+
+func TestKeyedStorage_Error(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyedStorage[secretID, string](mock.MockStorage(func(s *mock.Storage[string]) {
+		s.On("Set", "tenant/acme", `"db"/"password"`, "value").Return(assert.AnError)
+	})(t), secretIDMapper)
+
+	err := s.Set(secretID{Tenant: "acme", Resource: "db", Field: "password"}, "value")
+
+	require.ErrorIs(t, err, assert.AnError)
+}
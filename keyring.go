@@ -1,13 +1,20 @@
 package secretstorage
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"encoding"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"mime"
+	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/zalando/go-keyring"
 	"go.uber.org/multierr"
@@ -18,12 +25,28 @@ var (
 	ErrNotFound = keyring.ErrNotFound
 	// ErrUnsupportedType is an unsupported type error.
 	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrMarshal indicates that the value could not be marshaled for writing to the keyring.
+	ErrMarshal = errors.New("failed to marshal data")
+	// ErrKeyringWrite indicates that the keyring failed to write the data.
+	ErrKeyringWrite = errors.New("failed to write data to keyring")
+	// ErrTooManyPages indicates that the data needs more pages than allowed.
+	ErrTooManyPages = errors.New("too many pages")
+	// ErrCorruptSecret indicates that the reassembled data does not match its header.
+	ErrCorruptSecret = errors.New("corrupt secret")
+	// ErrEmptyMarshal indicates that the value was marshaled to an empty data, see WithRejectEmptyMarshal.
+	ErrEmptyMarshal = errors.New("value is marshaled to empty data")
+	// ErrInvalidPageCount indicates that the number of pages of a multipart data is invalid, for example less than 2.
+	ErrInvalidPageCount = errors.New("invalid secret pages")
+	// ErrInvalidPage indicates that the number of a page is invalid, for example less than 1, see DeletePage.
+	ErrInvalidPage = errors.New("invalid secret page")
 )
 
 const (
 	mimeMultipartSecret = "application/multipart-secret"
+	mimeNilSecret       = "application/nil-secret"
+	mimeEmptySecret     = "application/empty-secret"
 	minPages            = 2
-	maxLength           = 2048
+	defaultMaxLength    = 2048
 )
 
 var (
@@ -33,226 +56,864 @@ var (
 
 // KeyringStorage is a storage implementation that uses the OS keyring.
 type KeyringStorage[V any] struct {
+	keyringStorageConfig
+
+	services *sync.Map
+	configMu *sync.RWMutex
+	// indexed are the services that are known to be in the service index.
+	indexed *sync.Map
+	// entries are the keyring entries that the storage uses, see WithEntryBudget.
+	entries *entryUsage
+	// written are the keys that the storage writes, see DeleteAll.
+	written *writtenKeys
+}
+
+// keyringStorageConfig is the configuration of KeyringStorage, that Reconfigure replaces.
+type keyringStorageConfig struct {
 	keyring keyring.Keyring
-	mu      sync.Map
+	clock   Clock
+
+	codec            Codec
+	legacyCodecs     []Codec
+	circuitBreaker   *circuitBreaker
+	maxConcurrency   int
+	partialBulkReads bool
+	atomicSwap       bool
+	readOnly         bool
+	maxLength        int
+	maxPages         int
+	notFoundError    error
+	rejectEmpty      bool
+	storeLength      bool
+	keyHasher        func(string) string
+	sizeLimitErrors  bool
+	pageFormat       pageFormat
+	serviceIndex     bool
+	latencyObserver  func(LatencySample)
+	aggressiveDelete bool
+	verifyWrite      bool
+	resumableWrites  bool
+	readAliases      func(key string) []string
+	compression      bool
+	encryptionKey    []byte
+	decryptionKeys   [][]byte
+	plaintext        bool
+	randReader       io.Reader
+	panicRecovery    bool
+	entryBudget      int
+	serviceLock      bool
+	caseFallback     bool
+	contextualErrors bool
+	timeouts         operationTimeouts
+	softDelete       bool
+	forceCodec       bool
+	defaultService   string
+	nilSlices        bool
+	noPreDelete      bool
+	pagesPerCall     int
+	parallelReads    int
+}
+
+func (ss *KeyringStorage[V]) serviceLocks(service string) *serviceLocks {
+	// Load first, so that the locks are only allocated when they do not exist yet.
+	l, ok := ss.services.Load(service)
+	if !ok {
+		l, _ = ss.services.LoadOrStore(service, &serviceLocks{})
+	}
+
+	return l.(*serviceLocks) //nolint: forcetypeassert
 }
 
-func (ss *KeyringStorage[V]) mutex(service, key string) *sync.RWMutex {
-	m, _ := ss.mu.LoadOrStore(fmt.Sprintf("%s:%s", service, key), &sync.RWMutex{})
+func (ss *KeyringStorage[V]) mutex(service, key string) keyMutex {
+	l := ss.serviceLocks(service)
 
-	return m.(*sync.RWMutex) //nolint: forcetypeassert
+	if ss.serviceLock {
+		return keyMutex{service: &l.mu, key: &l.serial, exclusive: true}
+	}
+
+	m, ok := l.keys.Load(key)
+	if !ok {
+		m, _ = l.keys.LoadOrStore(key, &sync.RWMutex{})
+	}
+
+	return keyMutex{service: &l.mu, key: m.(*sync.RWMutex)} //nolint: forcetypeassert
 }
 
 func (ss *KeyringStorage[V]) withKeyring(keyring keyring.Keyring) {
 	ss.keyring = keyring
 }
 
-func (ss *KeyringStorage[V]) get(service string, key string) (V, error) {
+func (ss *KeyringStorage[V]) withClock(clock Clock) {
+	ss.clock = clock
+}
+
+func (ss *KeyringStorage[V]) withCircuitBreaker(threshold int, cooldown time.Duration) {
+	if threshold < 1 {
+		ss.circuitBreaker = nil
+
+		return
+	}
+
+	ss.circuitBreaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (ss *KeyringStorage[V]) withMaxConcurrency(n int) {
+	ss.maxConcurrency = n
+}
+
+func (ss *KeyringStorage[V]) withPartialBulkReads() {
+	ss.partialBulkReads = true
+}
+
+func (ss *KeyringStorage[V]) withAtomicSwap() {
+	ss.atomicSwap = true
+}
+
+func (ss *KeyringStorage[V]) withReadOnly() {
+	ss.readOnly = true
+}
+
+func (ss *KeyringStorage[V]) withMaxLength(n int) {
+	if n < 1 {
+		n = defaultMaxLength
+	}
+
+	ss.maxLength = n
+}
+
+func (ss *KeyringStorage[V]) withMaxPages(n int) {
+	ss.maxPages = n
+}
+
+func (ss *KeyringStorage[V]) withNotFoundError(err error) {
+	ss.notFoundError = err
+}
+
+func (ss *KeyringStorage[V]) withRejectEmptyMarshal() {
+	ss.rejectEmpty = true
+}
+
+func (ss *KeyringStorage[V]) withStoredLength() {
+	ss.storeLength = true
+}
+
+// notFound makes the not found error match the error configured with WithNotFoundError.
+func (ss *KeyringStorage[V]) notFound(err error) error {
+	if ss.notFoundError != nil && errors.Is(err, ErrNotFound) {
+		return tagError(ss.notFoundError, err)
+	}
+
+	return err
+}
+
+// get reads and decodes the value, it also returns the number of pages if the data is multipart.
+func (ss *KeyringStorage[V]) get(service string, key string) (V, int, error) {
 	var result V
 
+	d, params, targetService, targetKey, err := ss.getRawTarget(service, key)
+	if err != nil {
+		return result, 0, err
+	}
+
+	pages, _ := strconv.Atoi(params["pages"]) //nolint: errcheck
+
+	if err := ss.decode(targetService, targetKey, d, &result); err != nil {
+		return result, pages, fmt.Errorf("failed to unmarshal data read from keyring: %w", err)
+	}
+
+	return result, pages, nil
+}
+
+// getRaw reads the data and decodes it if it is in one of the stored formats, such as multipart.
+func (ss *KeyringStorage[V]) getRaw(service string, key string) (string, error) {
+	d, _, err := ss.getRawHeader(service, key)
+
+	return d, err
+}
+
+// getRawHeader is like getRaw, and also returns the parameters of the header of the stored format, if any.
+func (ss *KeyringStorage[V]) getRawHeader(service string, key string) (string, map[string]string, error) {
+	d, params, _, _, err := ss.getRawTarget(service, key)
+
+	return d, params, err
+}
+
+// getRawTarget is like getRawHeader, and also returns the service and the key that the data is read from, which are
+// the ones of the target if the key is a reference. The encrypted data is bound to them, see WithEncryption.
+func (ss *KeyringStorage[V]) getRawTarget(service string, key string) (string, map[string]string, string, string, error) {
 	d, err := ss.keyring.Get(service, key)
 	if err != nil {
-		return result, fmt.Errorf("failed to read data from keyring: %w", err)
+		return "", nil, "", "", fmt.Errorf("failed to read data from keyring: %w", err)
 	}
 
-	if strings.HasPrefix(d, mimeMultipartSecret) {
-		_, params, err := mime.ParseMediaType(d)
-		if err != nil {
-			return result, fmt.Errorf("failed to get params from data: %w", err)
-		}
+	decode, ok := storedFormats.lookup(d)
+	if !ok {
+		return d, nil, service, key, nil
+	}
 
-		pages, err := strconv.Atoi(params["pages"])
-		if err != nil {
-			return result, fmt.Errorf("failed to get pages from data: %w", err)
-		}
+	// The header has been validated by the decoder.
+	header, _, _ := strings.Cut(d, "\n")
+	_, params, _ := mime.ParseMediaType(header) //nolint: errcheck
 
-		if pages < minPages {
-			return result, fmt.Errorf("invalid secret pages: %d", pages) //nolint: goerr113
-		}
+	if err := ss.checkExpiry(params); err != nil {
+		return "", nil, "", "", fmt.Errorf("failed to read data from keyring: %w", err)
+	}
 
-		var sb strings.Builder
+	if isReference(d) {
+		if d, service, key, err = resolveReference(ss.keyring, service, key, d); err != nil {
+			return "", nil, "", "", err
+		}
 
-		for i := 1; i <= pages; i++ {
-			p, err := ss.keyring.Get(service, formatPage(key, i))
-			if err != nil {
-				return result, fmt.Errorf("failed to read multipart data #%d from keyring: %w", i, err)
+		// The expired target is not found, it is not deleted through the reference, which is kept.
+		if err := ss.checkDataExpiry(d); err != nil {
+			if errors.Is(err, errExpired) {
+				err = ErrNotFound
 			}
 
-			sb.WriteString(p)
+			return "", nil, "", "", fmt.Errorf("failed to read referenced data %q/%q from keyring: %w", service, key, err)
 		}
-
-		d = sb.String()
 	}
 
-	if err := unmarshalData(d, &result); err != nil {
-		return result, fmt.Errorf("failed to unmarshal data read from keyring: %w", err)
+	d, err = decode(ss.keyring, ss.pageFormat, ss.readsPerCall(), service, key, d)
+	if err != nil {
+		return "", nil, "", "", err
 	}
 
-	return result, nil
+	return d, params, service, key, nil
 }
 
 func (ss *KeyringStorage[V]) set(service string, key string, value string) error {
 	if err := ss.keyring.Set(service, key, value); err != nil {
-		return fmt.Errorf("failed to write data to keyring: %w", err)
+		return fmt.Errorf("failed to write data to keyring: %w", tagError(ErrKeyringWrite, ss.sizeLimitError(err, len(value))))
 	}
 
 	return nil
 }
 
-func (ss *KeyringStorage[V]) setMultipart(service string, key string, value string) error {
+// newMultipartHeader returns the header of the data of the length, and the size of its pages.
+func (ss *KeyringStorage[V]) newMultipartHeader(length int, generation int, labels map[string]string) (multipartHeader, int) {
+	size := ss.maxLength
+	h := multipartHeader{pages: ss.countPages(length), generation: generation, labels: labels}
+
+	// The data fits in a single entry, but not with its labels, or its tombstone, see WithSoftDelete, it is split in
+	// halves. A tiny data is not split, so that no page is empty, the empty data has no page at all.
+	if h.pages < minPages {
+		h.pages = min(length, minPages)
+		size = max((length+1)/minPages, 1)
+	}
+
+	if ss.storeLength {
+		h.length = length
+	}
+
+	return h, size
+}
+
+func (ss *KeyringStorage[V]) setMultipart(service string, key string, value string, generation int, labels map[string]string) error {
 	var err error
 
 	length := len(value)
+	h, size := ss.newMultipartHeader(length, generation, labels)
 
-	pages := length / maxLength
-	if length%maxLength != 0 {
-		pages++
+	// A header with less than 2 pages and no labels could not be read back, the data must be written in a single entry
+	// instead.
+	if err := h.checkPages(ss.pageFormat); err != nil {
+		return fmt.Errorf("refusing to write multipart data: %w", err)
+	}
+
+	if header := h.String(); len(labels) > 0 && len(header) > ss.maxLength {
+		return fmt.Errorf("%w: the labels are too long: %d, the max length is %d", ErrInvalidLabel, len(header), ss.maxLength)
 	}
 
-	page := 0
+	written := make([]bool, h.pages+1)
 
 	defer func() {
-		if err != nil {
-			for i := 1; i < page; i++ {
-				_ = ss.keyring.Delete(service, formatPage(key, i)) //nolint: errcheck
+		// The pages are kept so that the next write of the same data resumes from the failed page.
+		if err != nil && !ss.resumableWrites {
+			for i := 1; i <= h.pages; i++ {
+				if written[i] {
+					_ = ss.keyring.Delete(service, h.pageKey(ss.pageFormat, key, i)) //nolint: errcheck
+				}
 			}
 		}
 	}()
 
-	for page = 1; page <= pages; page++ {
-		end := page * maxLength
+	err = forEachPage(h.pages, ss.pagesPerCall, func(page int) error {
+		end := page * size
 		if end > length {
 			end = length
 		}
 
-		data := value[(page-1)*maxLength : end]
+		data := value[(page-1)*size : end]
 
-		if err = ss.keyring.Set(service, formatPage(key, page), data); err != nil {
-			return fmt.Errorf("failed to write multipart data #%d to keyring: %w", page, err)
+		if ss.resumableWrites && ss.hasPage(service, h.pageKey(ss.pageFormat, key, page), data) {
+			return nil
 		}
-	}
 
-	value = mime.FormatMediaType(mimeMultipartSecret, map[string]string{"pages": strconv.Itoa(pages)})
+		if err := ss.keyring.Set(service, h.pageKey(ss.pageFormat, key, page), data); err != nil {
+			err = ss.sizeLimitError(err, len(data))
 
-	if err = ss.keyring.Set(service, key, value); err != nil {
-		return fmt.Errorf("failed to write data to keyring: %w", err)
+			return fmt.Errorf("failed to write multipart data #%d to keyring: %w", page, tagError(ErrKeyringWrite, err))
+		}
+
+		written[page] = true
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = ss.keyring.Set(service, key, h.String()); err != nil {
+		return fmt.Errorf("failed to write data to keyring: %w", tagError(ErrKeyringWrite, err))
 	}
 
 	return nil
 }
 
-func (ss *KeyringStorage[V]) delete(service string, key string) error {
-	var err error
+// delete deletes the data, it also returns the header of the data if it is multipart.
+func (ss *KeyringStorage[V]) delete(service string, key string) (multipartHeader, error) {
+	return ss.deleteData(service, key, false)
+}
 
+// deleteData deletes the data, it also returns the header of the data if it is multipart. The data whose header has an
+// invalid page count is left as is, unless it is about to be replaced: then the main entry is deleted with the pages
+// that are found, so that a corrupt header does not prevent the next writes.
+func (ss *KeyringStorage[V]) deleteData(service string, key string, replace bool) (multipartHeader, error) {
 	d, err := ss.keyring.Get(service, key)
 	if err != nil {
-		return fmt.Errorf("failed to delete data in keyring: %w", err)
+		return multipartHeader{}, fmt.Errorf("failed to delete data in keyring: %w", err)
 	}
 
-	deleteMainKey := true
+	// The pages of a value that is marked as deleted are deleted too, see WithSoftDelete.
+	d = untombstone(d)
 
-	if strings.HasPrefix(d, mimeMultipartSecret) {
-		var (
-			params map[string]string
-			pages  int
-		)
+	if !isMultipart(d) {
+		return multipartHeader{}, ss.deleteEntries(service, key, nil)
+	}
 
-		_, params, err = mime.ParseMediaType(d)
-		if err != nil {
-			return fmt.Errorf("failed to get params from data for deletion: %w", err)
+	h, err := parseMultipartHeader(d)
+	if err != nil {
+		var hErr *headerError
+
+		if errors.As(err, &hErr) {
+			return multipartHeader{}, fmt.Errorf("failed to get %s from data for deletion: %w", hErr.field, hErr.err)
+		}
+
+		return multipartHeader{}, err
+	}
+
+	if err := h.checkPages(ss.pageFormat); err != nil {
+		if !replace {
+			return multipartHeader{}, err
+		}
+
+		return multipartHeader{}, ss.deleteCorrupt(service, key, h)
+	}
+
+	return h, ss.deleteEntries(service, key, &h)
+}
+
+// deleteCorrupt deletes the data whose header has an invalid page count: the pages are deleted until one is not found,
+// and then the main entry.
+func (ss *KeyringStorage[V]) deleteCorrupt(service string, key string, h multipartHeader) error {
+	if err := ss.deleteFoundPages(service, key, h); err != nil {
+		return err
+	}
+
+	if err := ss.keyring.Delete(service, key); err != nil {
+		return fmt.Errorf("failed to delete data in keyring: %w", err)
+	}
+
+	return nil
+}
+
+// deleteFoundPages deletes the pages of the data, from the first one, until a page is not found. It is used when the
+// number of pages in the header can not be trusted.
+func (ss *KeyringStorage[V]) deleteFoundPages(service string, key string, h multipartHeader) error {
+	for page := 1; page <= ss.pageFormat.maxPages(); page++ {
+		err := h.deletePage(ss.keyring, ss.pageFormat, service, key, page)
+		if errors.Is(err, ErrNotFound) {
+			return nil
 		}
 
-		pages, err = strconv.Atoi(params["pages"])
 		if err != nil {
-			return fmt.Errorf("failed to get pages from data for deletion: %w", err)
+			return fmt.Errorf("failed to delete multipart data #%d in keyring: %w", page, err)
 		}
+	}
+
+	return nil
+}
 
-		deleteMainKey = false
+// deleteEntries deletes the pages of the data, if it is multipart, and then the main entry. The main entry is kept if
+// none of the pages could be deleted, so that the deletion can be retried.
+func (ss *KeyringStorage[V]) deleteEntries(service string, key string, h *multipartHeader) error {
+	var (
+		err  error
+		mErr *MultipartDeleteError
+	)
 
-		for i := 1; i <= pages; i++ {
-			if err = ss.keyring.Delete(service, formatPage(key, i)); err != nil {
+	deleteMainKey := true
+
+	if h != nil {
+		// The header of an empty data has no page, see newMultipartHeader.
+		deleteMainKey = h.pages == 0
+		mErr = &MultipartDeleteError{Service: service, Key: key}
+
+		for i := 1; i <= h.pages; i++ {
+			if err = h.deletePage(ss.keyring, ss.pageFormat, service, key, i); err != nil {
 				err = fmt.Errorf("failed to delete multipart data #%d in keyring: %w", i, err)
 
+				mErr.Failed = append(mErr.Failed, i)
+
+				for j := i + 1; j <= h.pages; j++ {
+					mErr.Skipped = append(mErr.Skipped, j)
+				}
+
 				break
 			}
 
+			mErr.Deleted = append(mErr.Deleted, i)
 			deleteMainKey = true
 		}
 	}
 
-	if !deleteMainKey {
-		return err
+	if deleteMainKey {
+		if dErr := ss.keyring.Delete(service, key); dErr != nil {
+			err = multierr.Combine(err, fmt.Errorf("failed to delete data in keyring: %w", dErr))
+		} else if mErr != nil {
+			mErr.HeaderDeleted = true
+		}
 	}
 
-	if dErr := ss.keyring.Delete(service, key); dErr != nil {
-		err = multierr.Combine(err, fmt.Errorf("failed to delete data in keyring: %w", dErr))
+	if err == nil || mErr == nil {
+		return err
 	}
 
-	return err
+	mErr.Err = err
+
+	return mErr
 }
 
 // Get gets the value for the given key.
 func (ss *KeyringStorage[V]) Get(service string, key string) (V, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	v, err := ss.getKey(service, key)
+
+	return v, ss.contextualError(err, service, key)
+}
+
+// getKey gets the value of the key, or of its case variant or its first alias that is found if the key is not found.
+func (ss *KeyringStorage[V]) getKey(service string, key string) (V, error) {
+	v, err := ss.getLocked(service, key)
+	if !errors.Is(err, ErrNotFound) {
+		return v, err
+	}
+
+	if variant, ok := caseVariant(key); ok && ss.caseFallback {
+		if cv, cErr := ss.getLocked(service, variant); !errors.Is(cErr, ErrNotFound) {
+			slog.Debug("secret is found with another case", "service", service, "key", key, "variant", variant)
+
+			return cv, cErr
+		}
+	}
+
+	if ss.readAliases == nil {
+		return v, ss.notFound(err)
+	}
+
+	for _, alias := range ss.readAliases(key) {
+		if av, aErr := ss.getLocked(service, alias); !errors.Is(aErr, ErrNotFound) {
+			return av, aErr
+		}
+	}
+
+	return v, ss.notFound(err)
+}
+
+// getLocked locks the key and gets its value, and deletes the value if it has expired, see WithExpiry.
+func (ss *KeyringStorage[V]) getLocked(service string, key string) (V, error) {
 	mu := ss.mutex(service, key)
 
 	mu.RLock()
-	defer mu.RUnlock()
 
-	return ss.get(service, key)
+	start := ss.startTimer()
+	v, pages, err := ss.get(service, key)
+
+	ss.observeLatency(start, "get", service, key, pages, err)
+
+	mu.RUnlock()
+
+	// The expired value is deleted under the lock for writing.
+	if errors.Is(err, errExpired) {
+		ss.deleteExpired(service, key)
+	}
+
+	return v, err
 }
 
-// Set sets the value for the given key.
+// Set sets the value for the given key. The value that starts like one of the headers of the storage, such as
+// "application/secret-reference", or that reads like the sentinel of a nil or an empty value, such as
+// "application/nil-secret", is escaped, so that it is read back as is.
 func (ss *KeyringStorage[V]) Set(service string, key string, value V) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	return ss.contextualError(ss.setValue(service, key, value, nil), service, key)
+}
+
+// setValue locks the key and sets its value, with the labels.
+func (ss *KeyringStorage[V]) setValue(service string, key string, value V, labels map[string]string) error {
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
 	mu := ss.mutex(service, key)
 
 	mu.Lock()
 	defer mu.Unlock()
 
-	var err error
+	start := ss.startTimer()
+
+	d, err := ss.encode(service, key, value)
+	if err != nil {
+		err = fmt.Errorf("failed to marshal data for writing to keyring: %w", tagError(ErrMarshal, err))
+
+		ss.observeLatency(start, "set", service, key, 0, err)
+
+		return err
+	}
+
+	if ss.verifyWrite {
+		err = ss.setRawVerified(service, key, d, labels)
+	} else {
+		err = ss.setRaw(service, key, d, labels)
+	}
+
+	ss.observeLatency(start, "set", service, key, ss.plan(len(d)).Pages, err)
+
+	return err
+}
+
+// setRaw replaces the old data with the new one, and records the service in the service index, if enabled. The
+// reserved keys are rejected, see Rotate.
+func (ss *KeyringStorage[V]) setRaw(service string, key string, d string, labels map[string]string) error {
+	if isPreviousKey(key) {
+		return fmt.Errorf("%w: %q", ErrReservedKey, key)
+	}
+
+	return ss.setEntry(service, key, d, labels)
+}
 
-	d, err := marshalData(value)
+// setEntry is like setRaw, and also writes the reserved keys.
+func (ss *KeyringStorage[V]) setEntry(service string, key string, d string, labels map[string]string) error {
+	revert, err := ss.reserveEntries(service, key, d, labels)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data for writing to keyring: %w", err)
+		return err
+	}
+
+	if err := ss.writeRaw(service, key, d, labels); err != nil {
+		revert()
+
+		return err
+	}
+
+	return ss.indexService(service)
+}
+
+// writeRaw replaces the old data with the new one, and splits the data into pages if it is too long.
+func (ss *KeyringStorage[V]) writeRaw(service string, key string, d string, labels map[string]string) error {
+	if err := ss.checkPages(ss.plan(len(d))); err != nil {
+		return err
+	}
+
+	// The key is recorded before the write, that may fail after writing some pages.
+	ss.written.add(service, key)
+
+	if ss.atomicSwap {
+		return ss.swapRaw(service, key, d, labels)
+	}
+
+	// The header of the new data tells its number of pages, the old pages beyond it are not read.
+	if ss.noPreDelete {
+		return ss.write(service, key, d, 0, labels)
 	}
 
 	// Delete the data because it could be multipart.
-	if err = ss.delete(service, key); err != nil && !errors.Is(err, ErrNotFound) {
-		return fmt.Errorf("failed to delete old data in keyring: %w", errors.Unwrap(err))
+	if _, err := ss.deleteData(service, key, true); err != nil && !errors.Is(err, ErrNotFound) {
+		var mErr *MultipartDeleteError
+
+		// Keep the details of a partial deletion, without repeating the page in the message.
+		if errors.As(err, &mErr) {
+			if cause := errors.Unwrap(mErr.Err); cause != nil {
+				err = tagError(mErr, cause)
+			}
+		} else if cause := errors.Unwrap(err); cause != nil {
+			err = cause
+		}
+
+		return fmt.Errorf("failed to delete old data in keyring: %w", tagError(ErrKeyringWrite, err))
 	}
 
-	length := len(d)
-	if length <= maxLength {
-		return ss.set(service, key, d)
+	return ss.write(service, key, d, 0, labels)
+}
+
+// write writes the data in a single entry if it fits, with the labels, or splits it into pages otherwise.
+func (ss *KeyringStorage[V]) write(service string, key string, d string, generation int, labels map[string]string) error {
+	if e := labeledEntry(labels, d); len(e) <= ss.maxLength {
+		return ss.set(service, key, e)
 	}
 
-	return ss.setMultipart(service, key, d)
+	return ss.setMultipart(service, key, d, generation, labels)
 }
 
 // Delete deletes the value for the given key.
 func (ss *KeyringStorage[V]) Delete(service string, key string) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ss.contextualError(ErrReadOnly, service, key)
+	}
+
 	mu := ss.mutex(service, key)
 
 	mu.Lock()
 	defer mu.Unlock()
 
-	return ss.delete(service, key)
+	start := ss.startTimer()
+	pages, err := ss.deleteValue(service, key)
+
+	ss.observeLatency(start, "delete", service, key, pages, err)
+
+	return ss.contextualError(ss.notFound(err), service, key)
 }
 
-// NewKeyringStorage creates a new KeyringStorage that uses the OS keyring.
+// deleteValue deletes the value, or marks it as deleted, see WithSoftDelete, and deletes its previous value, see Rotate.
+// The key must be locked by the caller.
+func (ss *KeyringStorage[V]) deleteValue(service string, key string) (int, error) {
+	var (
+		pages int
+		err   error
+	)
+
+	if ss.softDelete {
+		pages, err = ss.markDeleted(service, key)
+	} else {
+		var h multipartHeader
+
+		h, err = ss.delete(service, key)
+		pages = h.pages
+
+		// Only the multipart data tells its orphaned pages from the keys on their own, see WithAggressiveDelete.
+		if ss.aggressiveDelete && err == nil && h.pages > 0 {
+			err = ss.deleteOrphans(service, key, h)
+		}
+
+		if err == nil || errors.Is(err, ErrNotFound) {
+			ss.releaseEntries(service, key)
+		}
+	}
+
+	if err == nil || errors.Is(err, ErrNotFound) {
+		if pErr := ss.deletePrevious(service, key); pErr != nil {
+			return pages, pErr
+		}
+	}
+
+	return pages, err
+}
+
+// DeleteKnown deletes the value for the given key, like Delete, but without reading the header first. It is meant for
+// the callers that already know the layout of the value: pages is 0 for a value stored in a single entry, or the
+// number of pages of a multipart value.
+//
+// The pages written with WithAtomicSwap may have a generation in their keys, DeleteKnown does not know about it, use
+// Delete for them.
+func (ss *KeyringStorage[V]) DeleteKnown(service string, key string, pages int) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	if pages < 0 || (pages > 0 && pages < minPages) {
+		return fmt.Errorf("%w: %d", ErrInvalidPageCount, pages)
+	}
+
+	return ss.deleteKnown(service, key, pages)
+}
+
+// DeleteSingle deletes the value for the given key, like Delete, but without reading the header first, for the values
+// that are known to be stored in a single entry: only the main entry is deleted, so it saves a call to the keyring.
+// ErrNotFound is returned if the value does not exist.
+//
+// It must not be used for the multipart values, their pages would be left behind in the keyring, orphaned, see
+// DeleteKnown.
+func (ss *KeyringStorage[V]) DeleteSingle(service string, key string) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	return ss.deleteKnown(service, key, 0)
+}
+
+// deleteKnown locks the key and deletes its main entry, and its pages if it is multipart.
+func (ss *KeyringStorage[V]) deleteKnown(service string, key string, pages int) error {
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var h *multipartHeader
+
+	if pages > 0 {
+		h = &multipartHeader{pages: pages}
+	}
+
+	err := ss.deleteEntries(service, key, h)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		ss.releaseEntries(service, key)
+	}
+
+	return ss.notFound(err)
+}
+
+// EntryCount returns the number of keyring entries that the value would use once stored: 1 if the value fits in a
+// single entry, or the number of pages plus the header if it is multipart. The keyring is not called.
+func (ss *KeyringStorage[V]) EntryCount(value V) (int, error) {
+	defer ss.rlockConfig()()
+
+	// The service and the key do not change the length of the data.
+	d, err := ss.encode("", "", value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal data: %w", tagError(ErrMarshal, err))
+	}
+
+	if p := ss.plan(len(d)); p.Multipart {
+		return p.Pages + 1, nil
+	}
+
+	return 1, nil
+}
+
+// NewKeyringStorage creates a new KeyringStorage that uses the OS keyring. It panics with ErrInvalidOption if one of the
+// options is invalid or conflicts with another, like Reconfigure fails. The codec that conflicts with the type of the
+// values fails the writes instead, see WithForceCodec.
 func NewKeyringStorage[V any](opts ...KeyringStorageOption) *KeyringStorage[V] {
 	s := &KeyringStorage[V]{
-		keyring: defaultKeyring{},
+		keyringStorageConfig: keyringStorageConfig{
+			keyring:    defaultKeyring{},
+			clock:      systemClock{},
+			codec:      TextCodec{},
+			maxLength:  defaultMaxLength,
+			pageFormat: defaultPageFormat,
+		},
+		services: &sync.Map{},
+		configMu: &sync.RWMutex{},
+		indexed:  &sync.Map{},
+		entries:  &entryUsage{keys: make(map[string]map[string]int)},
+		written:  &writtenKeys{keys: make(map[string]map[string]struct{})},
 	}
 
 	for _, opt := range opts {
 		opt.applyKeyringStorageOption(s)
 	}
 
+	if err := s.validate(); err != nil {
+		panic(err)
+	}
+
+	s.guardKeyring()
+
 	return s
 }
 
+// guardKeyring decorates the keyring with the key hasher and the protections that are configured. The keyring is
+// always guarded, so that its results are normalized.
+func (ss *KeyringStorage[V]) guardKeyring() {
+	if ss.keyHasher != nil {
+		ss.keyring = &hashedKeyring{Keyring: ss.keyring, hash: ss.keyHasher}
+	}
+
+	if ss.timeouts.enabled() {
+		ss.keyring = &timeoutKeyring{Keyring: ss.keyring, timeouts: ss.timeouts.resolve()}
+	}
+
+	k := &guardedKeyring{
+		Keyring:       ss.keyring,
+		breaker:       ss.circuitBreaker,
+		recoverPanics: ss.panicRecovery,
+	}
+
+	if k.breaker != nil {
+		k.breaker.clock = ss.clock
+	}
+
+	if ss.maxConcurrency > 0 {
+		k.semaphore = make(chan struct{}, ss.maxConcurrency)
+	}
+
+	ss.keyring = k
+}
+
 type configurableKeyringStorage interface {
 	withKeyring(k keyring.Keyring)
+	withClock(c Clock)
+	withCircuitBreaker(threshold int, cooldown time.Duration)
+	withMaxConcurrency(n int)
+	withPartialBulkReads()
+	withAtomicSwap()
+	withReadOnly()
+	withMaxLength(n int)
+	withMaxPages(n int)
+	withNotFoundError(err error)
+	withRejectEmptyMarshal()
+	withStoredLength()
+	withKeyHasher(hash func(string) string)
+	withSizeLimitErrors()
+	withPageFormat(sep string, width int)
+	withServiceIndex()
+	withLatencyObserver(fn func(LatencySample))
+	withAggressiveDelete()
+	withVerifyWrite()
+	withResumableWrites()
+	withReadAliases(aliases func(key string) []string)
+	withCompression()
+	withEncryption(key []byte)
+	withDecryptionKeys(keys ...[]byte)
+	withPlaintextFallback()
+	withPanicRecovery()
+	withLegacyPageFormat()
+	withEntryBudget(n int)
+	withServiceLock()
+	withCaseInsensitiveFallback()
+	withContextualErrors()
+	withPagesPerCall(n int)
+	withParallelReads(n int)
+	withOperationTimeout(d time.Duration)
+	withGetTimeout(d time.Duration)
+	withSetTimeout(d time.Duration)
+	withDeleteTimeout(d time.Duration)
+	withSoftDelete()
+	withCodec(c Codec)
+	withLegacyCodecs(codecs ...Codec)
+	withForceCodec()
+	withDefaultService(service string)
+	withNilSlices()
+	withNoPreDelete()
+	withRandReader(r io.Reader)
 }
 
 // KeyringStorageOption is an option to configure KeyringStorage.
@@ -267,23 +928,102 @@ func (f keyringStorageOptionFunc) applyKeyringStorageOption(ss configurableKeyri
 }
 
 // WithKeyring sets the keyring to use.
+//
+// The storage ignores the value returned together with an error by the Get method of the keyring, and the keys returned
+// together with an error by the List method, if any. Such results are treated as failures, even if the keyring returns
+// partial data with a warning.
 func WithKeyring(k keyring.Keyring) KeyringStorageOption {
 	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
 		ss.withKeyring(k)
 	})
 }
 
-func formatPage(key string, page int) string {
-	return fmt.Sprintf("%s-%04d", key, page)
+// WithMaxConcurrency limits the number of simultaneous calls to the keyring, across all the keys. This is independent
+// of the per-key locks and is useful to avoid overwhelming the keyring with bulk operations.
+func WithMaxConcurrency(n int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withMaxConcurrency(n)
+	})
+}
+
+// WithMaxLength sets the maximum length of the data stored in a single keyring entry, the longer data is split into
+// pages. The default is 2048 bytes, a value less than 1 restores it.
+func WithMaxLength(n int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withMaxLength(n)
+	})
+}
+
+// WithMaxPages limits the number of pages that a multipart data can be split into. Writing a data that needs more
+// pages fails with ErrTooManyPages, before anything is written to the keyring.
+func WithMaxPages(n int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withMaxPages(n)
+	})
+}
+
+// WithNotFoundError makes the storage return an error that matches the given error with errors.Is, when a secret is
+// not found. The error still matches ErrNotFound.
+func WithNotFoundError(err error) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withNotFoundError(err)
+	})
+}
+
+// WithRejectEmptyMarshal makes Set fail with ErrEmptyMarshal when the value is marshaled to an empty data, instead of
+// storing it. Some keyrings do not tell an empty secret from a missing one.
+func WithRejectEmptyMarshal() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withRejectEmptyMarshal()
+	})
+}
+
+// WithStoredLength makes the storage record the length of the multipart data in the header, so that a page that is
+// truncated is detected when the data is read, with ErrCorruptSecret. The data without the length is read without the
+// check.
+func WithStoredLength() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withStoredLength()
+	})
+}
+
+// WithPartialBulkReads makes the bulk reads, such as GetAll, skip the keys that could not be read and return the
+// partial result together with the errors, instead of aborting at the first error.
+func WithPartialBulkReads() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withPartialBulkReads()
+	})
+}
+
+// countPages returns the number of pages needed to store the data of the given length.
+func (ss *KeyringStorage[V]) countPages(length int) int {
+	pages := length / ss.maxLength
+	if length%ss.maxLength != 0 {
+		pages++
+	}
+
+	return pages
 }
 
 func marshalData(v any) (string, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return marshalNil(rv.Type())
+	}
+
 	switch v := v.(type) {
 	case string:
-		return v, nil
+		return escapeLiteral(v), nil
 
 	case []byte:
-		return string(v), nil
+		return escapeLiteral(string(v)), nil
+
+	case url.Values:
+		// Some keyrings do not tell an empty secret from a missing one.
+		if len(v) == 0 {
+			return mimeEmptySecret, nil
+		}
+
+		return v.Encode(), nil
 
 	case encoding.TextMarshaler:
 		b, err := v.MarshalText()
@@ -291,27 +1031,163 @@ func marshalData(v any) (string, error) {
 			return "", err //nolint: wrapcheck
 		}
 
-		return string(b), nil
+		// Some keyrings do not tell an empty secret from a missing one.
+		if len(b) == 0 {
+			return mimeEmptySecret, nil
+		}
+
+		return escapeLiteral(string(b)), nil
+
+	case driver.Valuer:
+		return marshalValuer(v)
+	}
+
+	switch rv := reflect.ValueOf(v); {
+	// The defined types of string and []byte, such as enums.
+	case rv.Kind() == reflect.String:
+		return escapeLiteral(rv.String()), nil
+
+	case isByteSlice(rv):
+		return escapeLiteral(string(rv.Bytes())), nil
+
+	// Marshal the value that the pointer points to.
+	case rv.Kind() == reflect.Pointer:
+		return marshalData(rv.Elem().Interface())
 	}
 
 	return "", fmt.Errorf("%w: %T", ErrUnsupportedType, v)
 }
 
+// marshalValuer marshals the value of a driver.Valuer, which must be a string, a byte slice, or nil.
+func marshalValuer(v driver.Valuer) (string, error) {
+	value, err := v.Value()
+	if err != nil {
+		return "", err //nolint: wrapcheck
+	}
+
+	var d string
+
+	switch value := value.(type) {
+	case nil:
+		return mimeNilSecret, nil
+
+	case string:
+		d = value
+
+	case []byte:
+		d = string(value)
+
+	default:
+		return "", fmt.Errorf("%w: %T returns %T from Value, expected string or []byte", ErrUnsupportedType, v, value)
+	}
+
+	// Some keyrings do not tell an empty secret from a missing one.
+	if d == "" {
+		return mimeEmptySecret, nil
+	}
+
+	return escapeLiteral(d), nil
+}
+
+// marshalNil returns the sentinel that represents a nil pointer, if the type that the pointer points to is supported.
+func marshalNil(t reflect.Type) (string, error) {
+	if _, err := marshalData(reflect.New(t.Elem()).Interface()); errors.Is(err, ErrUnsupportedType) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+
+	return mimeNilSecret, nil
+}
+
 func unmarshalData(v string, dest any) error {
 	switch dest := dest.(type) {
 	case *string:
-		*dest = v
+		*dest = unescapeLiteral(v)
 
 	case *[]byte:
-		*dest = []byte(v)
+		*dest = []byte(unescapeLiteral(v))
+
+	case *url.Values:
+		if v == mimeEmptySecret {
+			v = ""
+		}
+
+		q, err := url.ParseQuery(v)
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		*dest = q
 
 	case encoding.TextUnmarshaler:
+		if v == mimeEmptySecret {
+			v = ""
+		}
+
+		v = unescapeLiteral(v)
+
 		return dest.UnmarshalText([]byte(v)) //nolint: wrapcheck
 
+	case sql.Scanner:
+		return unmarshalScanner(v, dest)
+
 	default:
-		return fmt.Errorf("%w: %T", ErrUnsupportedType, dest)
+		rv := reflect.ValueOf(dest)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return fmt.Errorf("%w: %T", ErrUnsupportedType, dest)
+		}
+
+		switch e := rv.Elem(); {
+		// The defined types of string and []byte, such as enums.
+		case e.Kind() == reflect.String:
+			e.SetString(unescapeLiteral(v))
+
+		case isByteSlice(e):
+			e.SetBytes([]byte(unescapeLiteral(v)))
+
+		// The destination is a pointer to a pointer, allocate the value that it points to.
+		case e.Kind() == reflect.Pointer:
+			return unmarshalPointer(v, e)
+
+		default:
+			return fmt.Errorf("%w: %T", ErrUnsupportedType, dest)
+		}
+	}
+
+	return nil
+}
+
+// unmarshalScanner scans the data into a sql.Scanner, or nil if the Valuer returned nil.
+func unmarshalScanner(v string, dest sql.Scanner) error {
+	switch v {
+	case mimeNilSecret:
+		return dest.Scan(nil) //nolint: wrapcheck
+
+	case mimeEmptySecret:
+		v = ""
+	}
+
+	return dest.Scan(unescapeLiteral(v)) //nolint: wrapcheck
+}
+
+func isByteSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+}
+
+func unmarshalPointer(v string, dest reflect.Value) error {
+	if v == mimeNilSecret {
+		dest.Set(reflect.Zero(dest.Type()))
+
+		return nil
 	}
 
+	p := reflect.New(dest.Type().Elem())
+
+	if err := unmarshalData(v, p.Interface()); err != nil {
+		return err
+	}
+
+	dest.Set(p)
+
 	return nil
 }
 
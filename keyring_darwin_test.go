@@ -32,3 +32,24 @@ func TestKeyringStorage_Set_BigData(t *testing.T) {
 
 	assert.Equal(t, data, actual)
 }
+
+func TestKeyringStorage_DeleteAll_BigData(t *testing.T) {
+	t.Parallel()
+
+	key := randKey(15)
+	data := randString(10562)
+
+	ss := secretstorage.NewKeyringStorage[string]()
+
+	err := ss.Set(t.Name(), key, data)
+	require.NoError(t, err)
+
+	err = ss.DeleteAll(t.Name())
+	require.NoError(t, err)
+
+	assertSecretNotFound(t, key)
+
+	for page := 1; page <= 6; page++ {
+		assertSecretNotFound(t, formatPage(key, page))
+	}
+}
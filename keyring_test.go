@@ -1,10 +1,13 @@
 package secretstorage_test
 
 import (
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +30,60 @@ func TestKeyringStorage_Get_SecretNotFound(t *testing.T) {
 	require.EqualError(t, err, "failed to read data from keyring: secret not found in keyring")
 }
 
+func TestKeyringStorage_Get_SecretNotFound_CustomError(t *testing.T) {
+	t.Parallel()
+
+	errCustom := errors.New("custom not found")
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithNotFoundError(errCustom),
+	)
+
+	_, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, "failed to read data from keyring: secret not found in keyring")
+	require.ErrorIs(t, err, errCustom)
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	r, err := s.GetReader(t.Name(), "key")
+
+	require.ErrorIs(t, err, errCustom)
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Nil(t, r)
+
+	err = s.Delete(t.Name(), "key")
+
+	require.ErrorIs(t, err, errCustom)
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	err = s.DeleteKnown(t.Name(), "key", 0)
+
+	require.ErrorIs(t, err, errCustom)
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_Get_Failure_CustomNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	errCustom := errors.New("custom not found")
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("", assert.AnError)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithNotFoundError(errCustom),
+	)
+
+	_, err := s.Get(t.Name(), "key")
+
+	require.ErrorIs(t, err, assert.AnError)
+	require.NotErrorIs(t, err, errCustom)
+}
+
 func TestKeyringStorage_Get_UnsupportedType(t *testing.T) {
 	t.Parallel()
 
@@ -165,6 +222,7 @@ func TestKeyringStorage_Get_Failure_MultipartWrongPages(t *testing.T) {
 
 	actual, err := s.Get(t.Name(), key)
 
+	require.ErrorIs(t, err, secretstorage.ErrInvalidPageCount)
 	require.EqualError(t, err, `invalid secret pages: 1`)
 	assert.Empty(t, actual)
 }
@@ -186,6 +244,30 @@ func TestKeyringStorage_Get_Failure_MultipartMissingPage(t *testing.T) {
 	assert.Empty(t, actual)
 }
 
+func TestKeyringStorage_Get_Failure_ValueWithError(t *testing.T) {
+	t.Parallel()
+
+	// The keyring returns a value with an error, the pages must not be read.
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("application/multipart-secret; pages=2", assert.AnError)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	actual, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, `failed to read data from keyring: assert.AnError general error for testing`)
+	assert.Empty(t, actual)
+
+	r, err := s.GetReader(t.Name(), "key")
+
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, r)
+
+	k.AssertNumberOfCalls(t, "Get", 2)
+}
+
 func TestKeyringStorage_Set_UnsupportedType(t *testing.T) {
 	t.Parallel()
 
@@ -220,6 +302,21 @@ func TestKeyringStorage_Set_Failure(t *testing.T) {
 
 	err := s.Set(t.Name(), key, "value")
 	require.EqualError(t, err, `failed to write data to keyring: assert.AnError general error for testing`)
+	require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
+	require.ErrorIs(t, err, assert.AnError)
+	require.NotErrorIs(t, err, secretstorage.ErrMarshal)
+}
+
+func TestKeyringStorage_Set_Failure_Marshal(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[chan struct{}](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.Set(t.Name(), "key", make(chan struct{}))
+	require.EqualError(t, err, "failed to marshal data for writing to keyring: unsupported type: chan struct {}")
+	require.ErrorIs(t, err, secretstorage.ErrMarshal)
+	require.ErrorIs(t, err, secretstorage.ErrUnsupportedType)
+	require.NotErrorIs(t, err, secretstorage.ErrKeyringWrite)
 }
 
 func TestKeyringStorage_Set_Failure_CouldNotGetOldDataForDeletion(t *testing.T) {
@@ -286,6 +383,79 @@ func TestKeyringStorage_Set_Success_ByteSlice(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestKeyringStorage_URLValues(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[url.Values](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(16))
+
+	expected := url.Values{
+		"access_token": {"a&b=c d"},
+		"scope":        {"read write", "admin&root", "="},
+		"empty":        {""},
+		"key with=&":   {"value"},
+	}
+
+	require.NoError(t, s.Set(t.Name(), "key", expected))
+
+	assert.Equal(t, "application/multipart-secret; pages=7", k.entries(t.Name())["key"])
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	require.NoError(t, s.Set(t.Name(), "key", url.Values{}))
+
+	actual, err = s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{}, actual)
+
+	require.NoError(t, k.Set(t.Name(), "key", "a=%zz"))
+
+	_, err = s.Get(t.Name(), "key")
+	require.EqualError(t, err, `failed to unmarshal data read from keyring: invalid URL escape "%zz"`)
+}
+
+type (
+	Environment string
+	Blob        []byte
+)
+
+func TestKeyringStorage_DefinedStringType(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[Environment](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "key", Environment("production")))
+
+	assert.Equal(t, "production", k.entries(t.Name())["key"])
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, Environment("production"), actual)
+}
+
+func TestKeyringStorage_DefinedByteSliceType(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[Blob](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(4))
+
+	require.NoError(t, s.Set(t.Name(), "key", Blob("\x00\x01binary")))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, Blob("\x00\x01binary"), actual)
+
+	// The pointers to the defined types are supported too.
+	p := secretstorage.NewKeyringStorage[*Blob](secretstorage.WithKeyring(k))
+
+	actualPointer, err := p.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, Blob("\x00\x01binary"), *actualPointer)
+}
+
 func TestKeyringStorage_Set_Success_TextMarshaler(t *testing.T) {
 	t.Parallel()
 
@@ -390,6 +560,69 @@ func TestKeyringStorage_Set_Success_Multipart_DeleteOldData(t *testing.T) {
 	assert.Equal(t, data[2048:], part2)
 }
 
+func TestKeyringStorage_Set_Success_Multipart_Boundaries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		length        int
+		expectedPages []int
+	}{
+		{length: 2047},
+		{length: 2048},
+		{length: 2049, expectedPages: []int{2048, 1}},
+		{length: 4095, expectedPages: []int{2048, 2047}},
+		{length: 4096, expectedPages: []int{2048, 2048}},
+		{length: 4097, expectedPages: []int{2048, 2048, 1}},
+		{length: 6144, expectedPages: []int{2048, 2048, 2048}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(strconv.Itoa(tc.length), func(t *testing.T) {
+			t.Parallel()
+
+			data := randString(tc.length)
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			plan, err := s.PlanSet(data)
+			require.NoError(t, err)
+
+			require.NoError(t, s.Set(t.Name(), "key", data))
+
+			entries := k.entries(t.Name())
+
+			if len(tc.expectedPages) == 0 {
+				assert.Equal(t, secretstorage.PlanInfo{Length: tc.length}, plan)
+				assert.Equal(t, map[string]string{"key": data}, entries)
+			} else {
+				assert.Equal(t, secretstorage.PlanInfo{Length: tc.length, Multipart: true, Pages: len(tc.expectedPages)}, plan)
+				assert.Len(t, entries, len(tc.expectedPages)+1)
+				assert.Equal(t, fmt.Sprintf("application/multipart-secret; pages=%d", len(tc.expectedPages)), entries["key"])
+
+				// No page is empty, and the pages are the data in order.
+				var joined strings.Builder
+
+				for i, size := range tc.expectedPages {
+					page := entries[formatPage("key", i+1)]
+
+					assert.Len(t, page, size)
+
+					joined.WriteString(page)
+				}
+
+				assert.Equal(t, data, joined.String())
+			}
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, data, actual)
+		})
+	}
+}
+
 func TestKeyringStorage_Set_Failure_Multipart_CouldNotSetPage1(t *testing.T) {
 	t.Parallel()
 
@@ -408,6 +641,7 @@ func TestKeyringStorage_Set_Failure_Multipart_CouldNotSetPage1(t *testing.T) {
 
 	err := s.Set(t.Name(), key, data)
 	require.EqualError(t, err, `failed to write multipart data #1 to keyring: assert.AnError general error for testing`)
+	require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
 }
 
 func TestKeyringStorage_Set_Failure_Multipart_CouldNotSetPage2(t *testing.T) {
@@ -478,6 +712,41 @@ func TestKeyringStorage_Set_Failure_Multipart_CouldNotDeletePage2(t *testing.T)
 
 	err := s.Set(t.Name(), key, data)
 	require.EqualError(t, err, `failed to delete old data in keyring: assert.AnError general error for testing`)
+	require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
+
+	var mErr *secretstorage.MultipartDeleteError
+
+	require.ErrorAs(t, err, &mErr)
+	assert.Equal(t, []int{2}, mErr.Failed)
+}
+
+func TestKeyringStorage_Set_Failure_TooManyPages(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(mock.NopKeyring(t)),
+		secretstorage.WithMaxPages(3),
+	)
+
+	err := s.Set(t.Name(), "key", randString(3*2048+1))
+
+	require.EqualError(t, err, "too many pages: the data needs 4 pages, the limit is 3")
+	require.ErrorIs(t, err, secretstorage.ErrTooManyPages)
+}
+
+func TestKeyringStorage_Set_Success_MaxPages(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxPages(3),
+	)
+
+	err := s.Set(t.Name(), "key", randString(3*2048))
+	require.NoError(t, err)
+
+	assert.Len(t, k.entries(t.Name()), 4)
 }
 
 func TestKeyringStorage_Delete_Failure_SecretNotFound(t *testing.T) {
@@ -515,6 +784,93 @@ func TestKeyringStorage_Delete_Failure_MultipartInvalidPages(t *testing.T) {
 	require.EqualError(t, err, `failed to get pages from data for deletion: strconv.Atoi: parsing "hello": invalid syntax`)
 }
 
+func TestKeyringStorage_Set_MultipartWrongPages(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario   string
+		pages      int
+		atomicSwap bool
+	}{
+		{scenario: "no pages", pages: 0},
+		{scenario: "single page", pages: 1},
+		{scenario: "more pages than the format accommodates", pages: 10000},
+		{scenario: "more pages than the format accommodates, atomic swap", pages: 10000, atomicSwap: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+
+			require.NoError(t, k.Set(t.Name(), "key", fmt.Sprintf("application/multipart-secret; pages=%d", tc.pages)))
+			require.NoError(t, k.Set(t.Name(), formatPage("key", 1), "hello"))
+			require.NoError(t, k.Set(t.Name(), formatPage("key", 2), "world"))
+
+			opts := []secretstorage.KeyringStorageOption{secretstorage.WithKeyring(k)}
+
+			if tc.atomicSwap {
+				opts = append(opts, secretstorage.WithAtomicSwap())
+			}
+
+			s := secretstorage.NewKeyringStorage[string](opts...)
+
+			// The corrupt header is replaced, with the pages that are found.
+			require.NoError(t, s.Set(t.Name(), "key", "value"))
+
+			assert.Equal(t, map[string]string{"key": "value"}, k.entries(t.Name()))
+		})
+	}
+}
+
+func TestKeyringStorage_Delete_Failure_MultipartWrongPages(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		pages    int
+	}{
+		{scenario: "no pages", pages: 0},
+		{scenario: "single page", pages: 1},
+		{scenario: "more pages than the format accommodates", pages: 10000},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			key := randKey(12)
+
+			setKeyringSecretAndCleanUp(t, key, fmt.Sprintf("application/multipart-secret; pages=%d", tc.pages))
+			setKeyringSecretAndCleanUp(t, formatPage(key, 1), "value")
+
+			s := secretstorage.NewKeyringStorage[string]()
+
+			_, err := s.Get(t.Name(), key)
+
+			require.ErrorIs(t, err, secretstorage.ErrInvalidPageCount)
+			require.EqualError(t, err, fmt.Sprintf("invalid secret pages: %d", tc.pages))
+
+			err = s.Delete(t.Name(), key)
+
+			require.ErrorIs(t, err, secretstorage.ErrInvalidPageCount)
+			require.EqualError(t, err, fmt.Sprintf("invalid secret pages: %d", tc.pages))
+
+			// Nothing is deleted.
+			_, err = keyring.Get(t.Name(), key)
+			require.NoError(t, err)
+
+			_, err = keyring.Get(t.Name(), formatPage(key, 1))
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestKeyringStorage_Delete_Failure_MultipartMissingPage(t *testing.T) {
 	t.Parallel()
 
@@ -575,6 +931,16 @@ func TestKeyringStorage_Delete_Failure_Multipart_FailedToDeletePage2_Successfull
 
 	err := s.Delete(t.Name(), key)
 	require.EqualError(t, err, `failed to delete multipart data #2 in keyring: assert.AnError general error for testing`)
+	require.ErrorIs(t, err, assert.AnError)
+
+	var mErr *secretstorage.MultipartDeleteError
+
+	require.ErrorAs(t, err, &mErr)
+
+	assert.Equal(t, []int{1}, mErr.Deleted)
+	assert.Equal(t, []int{2}, mErr.Failed)
+	assert.Equal(t, []int{3}, mErr.Skipped)
+	assert.True(t, mErr.HeaderDeleted)
 }
 
 func TestKeyringStorage_Delete_Failure_Multipart_FailedToDeletePage2_FailedToDeleteOriginalKey(t *testing.T) {
@@ -600,6 +966,15 @@ func TestKeyringStorage_Delete_Failure_Multipart_FailedToDeletePage2_FailedToDel
 
 	err := s.Delete(t.Name(), key)
 	require.EqualError(t, err, `failed to delete multipart data #2 in keyring: assert.AnError general error for testing; failed to delete data in keyring: assert.AnError general error for testing`)
+
+	var mErr *secretstorage.MultipartDeleteError
+
+	require.ErrorAs(t, err, &mErr)
+
+	assert.Equal(t, []int{1}, mErr.Deleted)
+	assert.Equal(t, []int{2}, mErr.Failed)
+	assert.Equal(t, []int{3}, mErr.Skipped)
+	assert.False(t, mErr.HeaderDeleted)
 }
 
 func TestKeyringStorage_Delete_Failure_Multipart_FailedToDeleteOriginalKey(t *testing.T) {
@@ -662,6 +1037,375 @@ func TestKeyringStorage_Delete_Success_Multipart(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestKeyringStorage_EntryCount(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		length   int
+		expected int
+	}{
+		{
+			scenario: "empty",
+			length:   0,
+			expected: 1,
+		},
+		{
+			scenario: "single",
+			length:   2048,
+			expected: 1,
+		},
+		{
+			scenario: "multipart",
+			length:   2049,
+			expected: 3,
+		},
+		{
+			scenario: "multipart with full pages",
+			length:   6144,
+			expected: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+			actual, err := s.EntryCount(randString(tc.length))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_EntryCount_UnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[chan struct{}](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	actual, err := s.EntryCount(make(chan struct{}))
+
+	require.EqualError(t, err, "failed to marshal data: unsupported type: chan struct {}")
+	require.ErrorIs(t, err, secretstorage.ErrMarshal)
+	assert.Zero(t, actual)
+}
+
+func TestKeyringStorage_DeleteKnown_Single(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Delete", t.Name(), "key").
+			Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.DeleteKnown(t.Name(), "key", 0)
+	require.NoError(t, err)
+
+	k.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestKeyringStorage_DeleteSingle(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Delete", t.Name(), "key").
+			Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.DeleteSingle(t.Name(), "key")
+	require.NoError(t, err)
+
+	k.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestKeyringStorage_DeleteSingle_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	err := s.DeleteSingle(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_DeleteSingle_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)), secretstorage.WithReadOnly())
+
+	err := s.DeleteSingle(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
+
+func TestKeyringStorage_DeleteKnown_Multipart(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Delete", t.Name(), formatPage("key", 1)).Return(nil)
+		k.On("Delete", t.Name(), formatPage("key", 2)).Return(nil)
+		k.On("Delete", t.Name(), "key").Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.DeleteKnown(t.Name(), "key", 2)
+	require.NoError(t, err)
+
+	k.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestKeyringStorage_DeleteKnown_Failure(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Delete", t.Name(), formatPage("key", 1)).Return(nil)
+		k.On("Delete", t.Name(), formatPage("key", 2)).Return(secretstorage.ErrNotFound)
+		k.On("Delete", t.Name(), "key").Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.DeleteKnown(t.Name(), "key", 3)
+	require.EqualError(t, err, "failed to delete multipart data #2 in keyring: secret not found in keyring")
+}
+
+func TestKeyringStorage_DeleteKnown_InvalidPages(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.DeleteKnown(t.Name(), "key", 1)
+	require.EqualError(t, err, "invalid secret pages: 1")
+
+	err = s.DeleteKnown(t.Name(), "key", -1)
+	require.EqualError(t, err, "invalid secret pages: -1")
+	require.ErrorIs(t, err, secretstorage.ErrInvalidPageCount)
+}
+
+func TestKeyringStorage_Pointer_String(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[*string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	value := "value"
+
+	err := s.Set(t.Name(), "key", &value)
+	require.NoError(t, err)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	require.NotNil(t, actual)
+	assert.Equal(t, value, *actual)
+
+	err = s.Set(t.Name(), "key", nil)
+	require.NoError(t, err)
+
+	actual, err = s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Nil(t, actual)
+}
+
+func TestKeyringStorage_Pointer_TextMarshaler(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[*customStruct](secretstorage.WithKeyring(k))
+
+	value := &customStruct{Name: randString(5000)}
+
+	err := s.Set(t.Name(), "key", value)
+	require.NoError(t, err)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, value, actual)
+
+	err = s.Set(t.Name(), "key", nil)
+	require.NoError(t, err)
+
+	actual, err = s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Nil(t, actual)
+
+	assert.Equal(t, map[string]string{"key": "application/nil-secret"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Pointer_UnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[*chan struct{}](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.Set(t.Name(), "key", nil)
+	require.EqualError(t, err, "failed to marshal data for writing to keyring: unsupported type: *chan struct {}")
+
+	c := make(chan struct{})
+
+	err = s.Set(t.Name(), "key", &c)
+	require.EqualError(t, err, "failed to marshal data for writing to keyring: unsupported type: chan struct {}")
+}
+
+func TestKeyringStorage_TextMarshaler_Empty(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[customStruct](secretstorage.WithKeyring(k))
+
+	err := s.Set(t.Name(), "key", customStruct{})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"key": "application/empty-secret"}, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, customStruct{}, actual)
+}
+
+func TestKeyringStorage_TextMarshaler_Pointer_Empty(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[*customStruct](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	err := s.Set(t.Name(), "key", &customStruct{})
+	require.NoError(t, err)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, &customStruct{}, actual)
+}
+
+func TestKeyringStorage_RejectEmptyMarshal(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[customStruct](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithRejectEmptyMarshal(),
+	)
+
+	err := s.Set(t.Name(), "key", customStruct{})
+
+	require.EqualError(t, err, "failed to marshal data for writing to keyring: value is marshaled to empty data")
+	require.ErrorIs(t, err, secretstorage.ErrEmptyMarshal)
+	require.ErrorIs(t, err, secretstorage.ErrMarshal)
+
+	assert.Empty(t, k.entries(t.Name()))
+
+	err = s.Set(t.Name(), "key", customStruct{Name: "name"})
+	require.NoError(t, err)
+}
+
+func TestKeyringStorage_RejectEmptyMarshal_String(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(mock.NopKeyring(t)),
+		secretstorage.WithRejectEmptyMarshal(),
+	)
+
+	err := s.Set(t.Name(), "key", "")
+
+	require.ErrorIs(t, err, secretstorage.ErrEmptyMarshal)
+}
+
+func TestKeyringStorage_Valuer(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		value          nullSecret
+		expectedStored string
+	}{
+		{
+			scenario:       "value",
+			value:          nullSecret{Secret: "secret", Valid: true},
+			expectedStored: "secret",
+		},
+		{
+			scenario:       "empty",
+			value:          nullSecret{Valid: true},
+			expectedStored: "application/empty-secret",
+		},
+		{
+			scenario:       "null",
+			value:          nullSecret{},
+			expectedStored: "application/nil-secret",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[nullSecret](secretstorage.WithKeyring(k))
+
+			err := s.Set(t.Name(), "key", tc.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, map[string]string{"key": tc.expectedStored}, k.entries(t.Name()))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.value, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_Valuer_Pointer(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[*nullSecret](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	err := s.Set(t.Name(), "key", &nullSecret{Secret: "secret", Valid: true})
+	require.NoError(t, err)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, &nullSecret{Secret: "secret", Valid: true}, actual)
+}
+
+func TestKeyringStorage_Valuer_UnsupportedValue(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[valuerInt](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.Set(t.Name(), "key", valuerInt(42))
+
+	require.EqualError(t, err, "failed to marshal data for writing to keyring: unsupported type: secretstorage_test.valuerInt returns int64 from Value, expected string or []byte")
+	require.ErrorIs(t, err, secretstorage.ErrUnsupportedType)
+}
+
+func TestKeyringStorage_Scanner_Failure(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[nullSecret](secretstorage.WithKeyring(k))
+
+	require.NoError(t, k.Set(t.Name(), "key", "!"))
+
+	_, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: invalid secret")
+}
+
 type custom int
 
 func (c custom) MarshalText() (text []byte, err error) {
@@ -683,6 +1427,59 @@ func (c *custom) UnmarshalText(text []byte) error {
 	return nil
 }
 
+type customStruct struct {
+	Name string
+}
+
+func (c customStruct) MarshalText() (text []byte, err error) {
+	return []byte(c.Name), nil
+}
+
+func (c *customStruct) UnmarshalText(text []byte) error {
+	c.Name = string(text)
+
+	return nil
+}
+
+// nullSecret implements driver.Valuer and sql.Scanner, like sql.NullString.
+type nullSecret struct {
+	Secret string
+	Valid  bool
+}
+
+func (s nullSecret) Value() (driver.Value, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+
+	return s.Secret, nil
+}
+
+func (s *nullSecret) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*s = nullSecret{}
+
+	case string:
+		if src == "!" {
+			return errors.New("invalid secret")
+		}
+
+		*s = nullSecret{Secret: src, Valid: true}
+
+	default:
+		return fmt.Errorf("unsupported source: %T", src)
+	}
+
+	return nil
+}
+
+type valuerInt int
+
+func (i valuerInt) Value() (driver.Value, error) {
+	return int64(i), nil
+}
+
 func formatPage(key string, page int) string {
 	return fmt.Sprintf("%s-%04d", key, page)
 }
@@ -0,0 +1,209 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrInvalidLabel indicates that the name of a label is empty or has characters other than letters, digits, "-", "_"
+// and ".".
+var ErrInvalidLabel = errors.New("invalid label")
+
+const (
+	mimeLabeledSecret = "application/labeled-secret"
+	labelParamPrefix  = "label-"
+)
+
+// SetOption is an option to configure a single write, see SetWith.
+type SetOption interface {
+	applySetOption(c *setConfig)
+}
+
+type setOptionFunc func(c *setConfig)
+
+func (f setOptionFunc) applySetOption(c *setConfig) {
+	f(c)
+}
+
+type setConfig struct {
+	labels  map[string]string
+	expires time.Time
+}
+
+// WithLabels attaches the labels to the value, such as "env=prod" or "owner=team-x". The names of the labels are case
+// insensitive, they are stored in lower case, and may only have letters, digits, "-", "_" and ".".
+//
+// The labels are stored in clear in the header of the value, next to it, so that they can be read without the value,
+// see Labels. They must never contain the secret, or any part of it.
+func WithLabels(labels map[string]string) SetOption {
+	return setOptionFunc(func(c *setConfig) {
+		if c.labels == nil {
+			c.labels = make(map[string]string, len(labels))
+		}
+
+		for k, v := range labels {
+			c.labels[k] = v
+		}
+	})
+}
+
+// SetWith sets the value for the given key, like Set, with the options of the write, such as WithLabels and
+// WithExpiry. The labels and the expiry of the old value are replaced.
+func (ss *KeyringStorage[V]) SetWith(service string, key string, value V, opts ...SetOption) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	var c setConfig
+
+	for _, opt := range opts {
+		opt.applySetOption(&c)
+	}
+
+	labels, err := sanitizeLabels(c.labels)
+	if err != nil {
+		return err
+	}
+
+	return ss.setValue(service, key, value, withExpiryLabel(labels, c.expires))
+}
+
+// Labels returns the labels of the value, see WithLabels, without reading or decoding the value. The labels are empty
+// if the value is stored without labels.
+func (ss *KeyringStorage[V]) Labels(service string, key string) (map[string]string, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	return ss.labels(service, key)
+}
+
+// labels locks the key and reads its labels from its header.
+func (ss *KeyringStorage[V]) labels(service string, key string) (map[string]string, error) {
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		return nil, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	if isDeleted(d) {
+		return nil, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", ErrNotFound))
+	}
+
+	if !isMultipart(d) && !isLabeled(d) {
+		return map[string]string{}, nil
+	}
+
+	header, _, _ := strings.Cut(d, "\n")
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, &headerError{field: "labels", err: err}
+	}
+
+	if err := ss.checkExpiry(params); err != nil {
+		return nil, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	return labelsFromParams(params), nil
+}
+
+// sanitizeLabels returns the labels with their names in lower case, or ErrInvalidLabel if a name is invalid.
+func sanitizeLabels(labels map[string]string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]string, len(labels))
+
+	for k, v := range labels {
+		name := strings.ToLower(k)
+
+		if !isLabelName(name) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLabel, k)
+		}
+
+		result[name] = v
+	}
+
+	return result, nil
+}
+
+func isLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// labelParams adds the labels to the parameters of a header.
+func labelParams(params map[string]string, labels map[string]string) map[string]string {
+	for k, v := range labels {
+		if k == expiresLabel {
+			params[expiresParam] = v
+		} else {
+			params[labelParamPrefix+k] = v
+		}
+	}
+
+	return params
+}
+
+// labelsFromParams returns the labels in the parameters of a header.
+func labelsFromParams(params map[string]string) map[string]string {
+	labels := make(map[string]string)
+
+	for k, v := range params {
+		if name, ok := strings.CutPrefix(k, labelParamPrefix); ok {
+			labels[name] = v
+		}
+	}
+
+	return labels
+}
+
+// labeledEntry returns the data of a single entry with the labels in a header, or the data as is if there is no label.
+func labeledEntry(labels map[string]string, d string) string {
+	if len(labels) == 0 {
+		return d
+	}
+
+	return mime.FormatMediaType(mimeLabeledSecret, labelParams(map[string]string{}, labels)) + "\n" + d
+}
+
+func isLabeled(d string) bool {
+	return strings.HasPrefix(d, mimeLabeledSecret)
+}
+
+// decodeLabeled returns the data of a single entry without its labels.
+func decodeLabeled(_ keyring.Keyring, _ pageFormat, _ int, _ string, _ string, d string) (string, error) {
+	header, data, ok := strings.Cut(d, "\n")
+	if !ok {
+		return "", &headerError{field: "labels", err: errors.New("missing data")} //nolint: goerr113
+	}
+
+	if _, _, err := mime.ParseMediaType(header); err != nil {
+		return "", &headerError{field: "labels", err: err}
+	}
+
+	return data, nil
+}
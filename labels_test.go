@@ -0,0 +1,186 @@
+package secretstorage_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_SetWith_Labels(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		value    string
+	}{
+		{
+			scenario: "single entry",
+			value:    "secret",
+		},
+		{
+			scenario: "fits without the labels",
+			value:    randString(90),
+		},
+		{
+			scenario: "multipart",
+			value:    randString(300),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(100))
+
+			labels := map[string]string{"Env": "prod", "owner": "team x"}
+
+			require.NoError(t, s.SetWith(t.Name(), "key", tc.value, secretstorage.WithLabels(labels)))
+
+			actual, err := s.Labels(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"env": "prod", "owner": "team x"}, actual)
+
+			value, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, value)
+
+			_, params, err := s.GetFull(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, "prod", params["label-env"])
+
+			r, err := s.GetReader(t.Name(), "key")
+			require.NoError(t, err)
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, tc.value, string(b))
+
+			// Set replaces the labels.
+			require.NoError(t, s.Set(t.Name(), "key", tc.value))
+
+			actual, err = s.Labels(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Empty(t, actual)
+
+			require.NoError(t, s.Delete(t.Name(), "key"))
+			assert.Empty(t, k.entries(t.Name()))
+		})
+	}
+}
+
+func TestKeyringStorage_SetWith_AtomicSwap(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(100),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	value := randString(300)
+
+	require.NoError(t, s.SetWith(t.Name(), "key", value, secretstorage.WithLabels(map[string]string{"env": "prod"})))
+	require.NoError(t, s.SetWith(t.Name(), "key", value, secretstorage.WithLabels(map[string]string{"env": "dev"})))
+
+	actual, err := s.Labels(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "dev"}, actual)
+
+	v, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, value, v)
+}
+
+func TestKeyringStorage_SetWith_InvalidLabel(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	for _, name := range []string{"", "with space", "with=equal"} {
+		err := s.SetWith(t.Name(), "key", "secret", secretstorage.WithLabels(map[string]string{name: "value"}))
+
+		require.ErrorIs(t, err, secretstorage.ErrInvalidLabel)
+	}
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Labels_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	_, err := s.Labels(t.Name(), "key")
+
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_Labels_TinyMultipart(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		header   string
+		pages    map[string]string
+		expected string
+	}{
+		{
+			scenario: "empty",
+			header:   "application/multipart-secret; label-env=prod; pages=0",
+			expected: "",
+		},
+		{
+			scenario: "single page",
+			header:   "application/multipart-secret; label-env=prod; pages=1",
+			pages:    map[string]string{"key-0001": "a"},
+			expected: "a",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			require.NoError(t, k.Set(t.Name(), "key", tc.header))
+
+			for key, page := range tc.pages {
+				require.NoError(t, k.Set(t.Name(), key, page))
+			}
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			labels, err := s.Labels(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"env": "prod"}, labels)
+
+			require.NoError(t, s.Delete(t.Name(), "key"))
+			assert.Empty(t, k.entries(t.Name()))
+		})
+	}
+
+	// Without labels, a header with less than 2 pages is corrupt.
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=1"))
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrInvalidPageCount)
+}
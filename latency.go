@@ -0,0 +1,60 @@
+package secretstorage
+
+import "time"
+
+// LatencySample is the latency of a Get, Set or Delete operation of a KeyringStorage, see WithLatencyObserver.
+type LatencySample struct {
+	// Operation is "get", "set" or "delete".
+	Operation string
+	Service   string
+	Key       string
+	// Pages is the number of pages of the data if it is multipart, 0 otherwise. The operations on the multipart data
+	// call the keyring once per page, they are usually much slower.
+	Pages int
+	// Duration is the latency of the whole operation, including the pages, but not the time spent waiting for the
+	// locks.
+	Duration time.Duration
+	Err      error
+}
+
+// Multipart tells whether the operation was on a multipart data.
+func (s LatencySample) Multipart() bool {
+	return s.Pages > 0
+}
+
+func (ss *KeyringStorage[V]) withLatencyObserver(fn func(LatencySample)) {
+	ss.latencyObserver = fn
+}
+
+// WithLatencyObserver calls the function with the latency of every Get, Set and Delete operation, for example to feed
+// a histogram. The function is called synchronously while the key is locked, it must be fast. The time is measured
+// with the clock of the storage, see WithClock.
+func WithLatencyObserver(fn func(LatencySample)) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withLatencyObserver(fn)
+	})
+}
+
+// startTimer returns the start time of an operation, or the zero time if no observer is configured.
+func (ss *KeyringStorage[V]) startTimer() time.Time {
+	if ss.latencyObserver == nil {
+		return time.Time{}
+	}
+
+	return ss.clock.Now()
+}
+
+func (ss *KeyringStorage[V]) observeLatency(start time.Time, op, service, key string, pages int, err error) {
+	if ss.latencyObserver == nil {
+		return
+	}
+
+	ss.latencyObserver(LatencySample{
+		Operation: op,
+		Service:   service,
+		Key:       key,
+		Pages:     pages,
+		Duration:  ss.clock.Now().Sub(start),
+		Err:       err,
+	})
+}
@@ -0,0 +1,125 @@
+package secretstorage_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+// slowKeyring advances the clock by a millisecond on every call.
+type slowKeyring struct {
+	keyring.Keyring
+
+	clock *fakeClock
+}
+
+func (k *slowKeyring) Set(service, user, password string) error {
+	k.clock.Add(time.Millisecond)
+
+	return k.Keyring.Set(service, user, password)
+}
+
+func (k *slowKeyring) Get(service, user string) (string, error) {
+	k.clock.Add(time.Millisecond)
+
+	return k.Keyring.Get(service, user)
+}
+
+func (k *slowKeyring) Delete(service, user string) error {
+	k.clock.Add(time.Millisecond)
+
+	return k.Keyring.Delete(service, user)
+}
+
+func TestKeyringStorage_LatencyObserver(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		samples []secretstorage.LatencySample
+	)
+
+	c := newFakeClock()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(&slowKeyring{Keyring: newMemoryKeyring(), clock: c}),
+		secretstorage.WithClock(c),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithLatencyObserver(func(s secretstorage.LatencySample) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			samples = append(samples, s)
+		}),
+	)
+
+	require.NoError(t, s.Set("service", "single", "hello"))
+	require.NoError(t, s.Set("service", "multipart", "hello world"))
+
+	_, err := s.Get("service", "single")
+	require.NoError(t, err)
+
+	_, err = s.Get("service", "multipart")
+	require.NoError(t, err)
+
+	_, err = s.Get("service", "unknown")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, s.Delete("service", "single"))
+	require.NoError(t, s.Delete("service", "multipart"))
+
+	expected := []secretstorage.LatencySample{
+		// Reads the old data, and writes the new one.
+		{Operation: "set", Service: "service", Key: "single", Duration: 2 * time.Millisecond},
+		// Reads the old data, and writes the pages and the header.
+		{Operation: "set", Service: "service", Key: "multipart", Pages: 3, Duration: 5 * time.Millisecond},
+		{Operation: "get", Service: "service", Key: "single", Duration: time.Millisecond},
+		{Operation: "get", Service: "service", Key: "multipart", Pages: 3, Duration: 4 * time.Millisecond},
+		{Operation: "get", Service: "service", Key: "unknown", Duration: time.Millisecond},
+		// Reads the data, deletes it, and looks for its previous value.
+		{Operation: "delete", Service: "service", Key: "single", Duration: 3 * time.Millisecond},
+		{Operation: "delete", Service: "service", Key: "multipart", Pages: 3, Duration: 6 * time.Millisecond},
+	}
+
+	require.Len(t, samples, len(expected))
+
+	for i, e := range expected {
+		actual := samples[i]
+
+		if e.Key == "unknown" {
+			require.ErrorIs(t, actual.Err, secretstorage.ErrNotFound)
+		} else {
+			require.NoError(t, actual.Err)
+		}
+
+		actual.Err = nil
+
+		assert.Equal(t, e, actual)
+		assert.Equal(t, e.Pages > 0, actual.Multipart())
+	}
+}
+
+func TestKeyringStorage_LatencyObserver_MarshalError(t *testing.T) {
+	t.Parallel()
+
+	var samples []secretstorage.LatencySample
+
+	s := secretstorage.NewKeyringStorage[int](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithLatencyObserver(func(s secretstorage.LatencySample) {
+			samples = append(samples, s)
+		}),
+	)
+
+	err := s.Set("service", "key", 42)
+	require.ErrorIs(t, err, secretstorage.ErrMarshal)
+
+	require.Len(t, samples, 1)
+	assert.Equal(t, "set", samples[0].Operation)
+	assert.Equal(t, err, samples[0].Err)
+}
@@ -0,0 +1,67 @@
+package secretstorage
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/zalando/go-keyring"
+)
+
+func (ss *KeyringStorage[V]) withLegacyPageFormat() {
+	ss.pageFormat.legacyUnpadded = true
+}
+
+// WithLegacyPageFormat reads the pages that are written without the zero padding, for example "key-1" instead of
+// "key-0001", by older versions or by other tools. When a page is not found, the unpadded key is tried before failing.
+// The pages are also deleted with the fallback, so that the legacy data can be removed.
+//
+// The data is always written with the current page format, see WithPageFormat.
+func WithLegacyPageFormat() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withLegacyPageFormat()
+	})
+}
+
+// legacyPageKey returns the key of a page without the zero padding, or false if the legacy page format is not enabled
+// or the key is the same as the current one.
+func (h multipartHeader) legacyPageKey(f pageFormat, key string, page int) (string, bool) {
+	if !f.legacyUnpadded || h.generation > 0 {
+		return "", false
+	}
+
+	legacy := key + f.sep + strconv.Itoa(page)
+
+	return legacy, legacy != f.format(key, page)
+}
+
+// readPage reads a page of the data, and falls back to the legacy page format if the page is not found.
+func (h multipartHeader) readPage(k keyring.Keyring, f pageFormat, service string, key string, page int) (string, error) {
+	p, err := k.Get(service, h.pageKey(f, key, page))
+	if !errors.Is(err, ErrNotFound) {
+		return p, err //nolint: wrapcheck
+	}
+
+	if legacy, ok := h.legacyPageKey(f, key, page); ok {
+		if p, lErr := k.Get(service, legacy); lErr == nil {
+			return p, nil
+		}
+	}
+
+	return "", err
+}
+
+// deletePage deletes a page of the data, and falls back to the legacy page format if the page is not found.
+func (h multipartHeader) deletePage(k keyring.Keyring, f pageFormat, service string, key string, page int) error {
+	err := k.Delete(service, h.pageKey(f, key, page))
+	if !errors.Is(err, ErrNotFound) {
+		return err //nolint: wrapcheck
+	}
+
+	if legacy, ok := h.legacyPageKey(f, key, page); ok {
+		if lErr := k.Delete(service, legacy); lErr == nil {
+			return nil
+		}
+	}
+
+	return err
+}
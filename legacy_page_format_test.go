@@ -0,0 +1,77 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_LegacyPageFormat(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=3"))
+	require.NoError(t, k.Set(t.Name(), "key-1", "hello"))
+	require.NoError(t, k.Set(t.Name(), "key-2", " worl"))
+	require.NoError(t, k.Set(t.Name(), "key-3", "d"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithLegacyPageFormat())
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", actual)
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_LegacyPageFormat_Mixed(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-0001", "hello "))
+	require.NoError(t, k.Set(t.Name(), "key-2", "world"))
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithLegacyPageFormat(),
+		secretstorage.WithMaxLength(6),
+	)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", actual)
+
+	// The writes use the current page format.
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	expected := map[string]string{
+		"key":      "application/multipart-secret; pages=2",
+		"key-0001": "hello ",
+		"key-0002": "world",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_LegacyPageFormat_Disabled(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-1", "hello "))
+	require.NoError(t, k.Set(t.Name(), "key-2", "world"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	require.EqualError(t, err, "failed to read multipart data #1 from keyring: secret not found in keyring")
+}
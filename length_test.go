@@ -0,0 +1,101 @@
+package secretstorage_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_StoredLength(t *testing.T) {
+	t.Parallel()
+
+	value := randString(5000)
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithStoredLength(),
+	)
+
+	err := s.Set(t.Name(), "key", value)
+	require.NoError(t, err)
+
+	header, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/multipart-secret; length=5000; pages=3", header)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, value, actual)
+
+	// The length is not stored for the data that fits in a single entry.
+	err = s.Set(t.Name(), "key", "value")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"key": "value"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_StoredLength_Truncated(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; length=12; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-0001", "hello "))
+	require.NoError(t, k.Set(t.Name(), "key-0002", "wor"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	actual, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, "corrupt secret: the length is 9, expected 12")
+	require.ErrorIs(t, err, secretstorage.ErrCorruptSecret)
+	assert.Empty(t, actual)
+
+	r, err := s.GetReader(t.Name(), "key")
+	require.NoError(t, err)
+
+	defer r.Close() //nolint: errcheck
+
+	b, err := io.ReadAll(r)
+
+	require.ErrorIs(t, err, secretstorage.ErrCorruptSecret)
+	assert.Equal(t, "hello wor", string(b))
+}
+
+func TestKeyringStorage_StoredLength_Missing(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-0001", "hello "))
+	require.NoError(t, k.Set(t.Name(), "key-0002", "wor"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello wor", actual)
+}
+
+func TestKeyringStorage_StoredLength_Invalid(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; length=hello; pages=2"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	_, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, `failed to get length from data: strconv.Atoi: parsing "hello": invalid syntax`)
+}
@@ -0,0 +1,77 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ListFilter selects the keys that ListFiltered returns. The zero value selects all the keys.
+type ListFilter struct {
+	// Prefix selects the keys that start with it.
+	Prefix string
+	// Labels selects the keys that have all these labels, with the same values, see WithLabels. The names of the labels
+	// are case insensitive.
+	Labels map[string]string
+}
+
+// ListFiltered lists the keys of the service that match the filter, excluding the pages of the multipart values. It
+// returns an empty slice if no key matches.
+//
+// The keys are filtered by prefix first. When the filter has labels, the header of each remaining key is read to get
+// its labels, the values are neither read nor decoded. The keys that are deleted while they are filtered are skipped.
+//
+// The keyring must implement Lister, otherwise ErrListingNotSupported is returned.
+func (ss *KeyringStorage[V]) ListFiltered(service string, filter ListFilter) ([]string, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	labels, err := sanitizeLabels(filter.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := ss.listKeys(service)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, filter.Prefix) {
+			continue
+		}
+
+		if len(labels) > 0 {
+			actual, err := ss.labels(service, key)
+
+			switch {
+			case errors.Is(err, ErrNotFound):
+				continue
+
+			case err != nil:
+				return nil, fmt.Errorf("failed to get labels of %q: %w", key, err)
+
+			case !hasLabels(actual, labels):
+				continue
+			}
+		}
+
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+// hasLabels tells whether the labels have all the expected ones, with the same values.
+func hasLabels(labels map[string]string, expected map[string]string) bool {
+	for k, v := range expected {
+		if actual, ok := labels[k]; !ok || actual != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_ListFiltered(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(100))
+
+	prodX := secretstorage.WithLabels(map[string]string{"env": "prod", "owner": "team-x"})
+	prodY := secretstorage.WithLabels(map[string]string{"env": "prod", "owner": "team-y"})
+
+	require.NoError(t, s.SetWith(t.Name(), "db/password", "secret", prodX))
+	require.NoError(t, s.SetWith(t.Name(), "db/certificate", randString(300), prodX))
+	require.NoError(t, s.SetWith(t.Name(), "api/token", "secret", prodY))
+	require.NoError(t, s.Set(t.Name(), "db/legacy", "secret"))
+
+	testCases := []struct {
+		scenario string
+		filter   secretstorage.ListFilter
+		expected []string
+	}{
+		{
+			scenario: "no filter",
+			expected: []string{"api/token", "db/certificate", "db/legacy", "db/password"},
+		},
+		{
+			scenario: "prefix",
+			filter:   secretstorage.ListFilter{Prefix: "db/"},
+			expected: []string{"db/certificate", "db/legacy", "db/password"},
+		},
+		{
+			scenario: "labels",
+			filter:   secretstorage.ListFilter{Labels: map[string]string{"ENV": "prod", "owner": "team-x"}},
+			expected: []string{"db/certificate", "db/password"},
+		},
+		{
+			scenario: "prefix and labels",
+			filter:   secretstorage.ListFilter{Prefix: "api/", Labels: map[string]string{"env": "prod"}},
+			expected: []string{"api/token"},
+		},
+		{
+			scenario: "no match",
+			filter:   secretstorage.ListFilter{Labels: map[string]string{"env": "dev"}},
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := s.ListFiltered("TestKeyringStorage_ListFiltered", tc.filter)
+			require.NoError(t, err)
+
+			assert.NotNil(t, actual)
+			assert.ElementsMatch(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_ListFiltered_InvalidLabel(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	_, err := s.ListFiltered(t.Name(), secretstorage.ListFilter{Labels: map[string]string{"not valid": "x"}})
+
+	require.ErrorIs(t, err, secretstorage.ErrInvalidLabel)
+}
@@ -0,0 +1,33 @@
+package secretstorage
+
+import "errors"
+
+// ErrListingNotSupported indicates that the keyring is not able to enumerate its entries.
+var ErrListingNotSupported = errors.New("listing is not supported by keyring")
+
+// Lister is an optional interface for keyrings that are able to enumerate the entries of a service.
+type Lister interface {
+	List(service string) ([]string, error)
+}
+
+// ServiceLister is an optional interface for keyrings and storages that are able to enumerate their services, the
+// services that have at least one entry.
+type ServiceLister interface {
+	Services() ([]string, error)
+}
+
+func (ss *KeyringStorage[V]) lister() (Lister, error) {
+	if _, ok := unwrapKeyring(ss.keyring).(Lister); !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return ss.keyring.(Lister), nil //nolint: forcetypeassert
+}
+
+func (ss *KeyringStorage[V]) serviceLister() (ServiceLister, error) {
+	if _, ok := unwrapKeyring(ss.keyring).(ServiceLister); !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	return ss.keyring.(ServiceLister), nil //nolint: forcetypeassert
+}
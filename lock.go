@@ -0,0 +1,80 @@
+package secretstorage
+
+import "sync"
+
+// serviceLocks holds the locks of a service and of its keys.
+type serviceLocks struct {
+	mu   sync.RWMutex
+	keys sync.Map
+	// serial is the lock of all the keys when the operations of the service are serialized, see WithServiceLock.
+	serial sync.RWMutex
+}
+
+// keyMutex locks a key, and shares the lock of its service, so that the operations on the whole service, such as
+// DeleteAll, exclude the operations on its keys.
+type keyMutex struct {
+	service *sync.RWMutex
+	key     *sync.RWMutex
+	// exclusive makes the reads lock the key exclusively too.
+	exclusive bool
+}
+
+func (m keyMutex) Lock() {
+	m.service.RLock()
+	m.key.Lock()
+}
+
+func (m keyMutex) Unlock() {
+	m.key.Unlock()
+	m.service.RUnlock()
+}
+
+func (m keyMutex) RLock() {
+	if m.exclusive {
+		m.Lock()
+
+		return
+	}
+
+	m.service.RLock()
+	m.key.RLock()
+}
+
+func (m keyMutex) RUnlock() {
+	if m.exclusive {
+		m.Unlock()
+
+		return
+	}
+
+	m.key.RUnlock()
+	m.service.RUnlock()
+}
+
+// detach unlocks the service of the key that is locked for reading, so that the operations on the whole service, such
+// as DeleteAll, are not excluded anymore, and returns the function that unlocks the key, see GetReader.
+func (m keyMutex) detach() func() {
+	m.service.RUnlock()
+
+	if m.exclusive {
+		return m.key.Unlock
+	}
+
+	return m.key.RUnlock
+}
+
+func (ss *KeyringStorage[V]) withServiceLock() {
+	ss.serviceLock = true
+}
+
+// WithServiceLock serializes all the operations of a service, the reads included, instead of locking the keys one by
+// one, for the keyrings that are not safe for concurrent access at all. The operations on different services still run
+// concurrently.
+//
+// This is coarser and slower: the reads of a service wait for each other, and a reader returned by GetReader blocks
+// the other operations of the service, but DeleteAll, until it is closed.
+func WithServiceLock() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withServiceLock()
+	})
+}
@@ -0,0 +1,103 @@
+package secretstorage_test
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+var (
+	_ keyring.Keyring      = (*memoryKeyring)(nil)
+	_ secretstorage.Lister = (*memoryKeyring)(nil)
+)
+
+// memoryKeyring is an in-memory keyring that supports listing.
+type memoryKeyring struct {
+	mu   sync.Mutex
+	data map[string]map[string]string
+}
+
+func (k *memoryKeyring) Set(service, user, password string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.data[service]; !ok {
+		k.data[service] = make(map[string]string)
+	}
+
+	k.data[service][user] = password
+
+	return nil
+}
+
+func (k *memoryKeyring) Get(service, user string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	password, ok := k.data[service][user]
+	if !ok {
+		return "", keyring.ErrNotFound
+	}
+
+	return password, nil
+}
+
+func (k *memoryKeyring) Delete(service, user string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.data[service][user]; !ok {
+		return keyring.ErrNotFound
+	}
+
+	delete(k.data[service], user)
+
+	return nil
+}
+
+func (k *memoryKeyring) DeleteAll(service string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	delete(k.data, service)
+
+	return nil
+}
+
+func (k *memoryKeyring) List(service string) ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keys := make([]string, 0, len(k.data[service]))
+
+	for key := range k.data[service] {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// entries returns a copy of all the entries of the service.
+func (k *memoryKeyring) entries(service string) map[string]string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	entries := make(map[string]string, len(k.data[service]))
+
+	for key, value := range k.data[service] {
+		entries[key] = value
+	}
+
+	return entries
+}
+
+func newMemoryKeyring() *memoryKeyring {
+	return &memoryKeyring{
+		data: make(map[string]map[string]string),
+	}
+}
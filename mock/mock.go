@@ -4,3 +4,6 @@ import "github.com/stretchr/testify/mock"
 
 // Anything is an alias of mock.Anything.
 var Anything = mock.Anything
+
+// Arguments is an alias of mock.Arguments.
+type Arguments = mock.Arguments
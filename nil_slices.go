@@ -0,0 +1,39 @@
+package secretstorage
+
+import "reflect"
+
+func (ss *KeyringStorage[V]) withNilSlices() {
+	ss.nilSlices = true
+}
+
+// WithNilSlices tells the nil byte slices from the empty ones. By default, the text codec stores both as an empty
+// secret, and reads them back as empty, non-nil, slices. With this option, the nil byte slices are stored as a sentinel,
+// like the nil pointers, and read back as nil, while the empty slices are still stored and read back as empty.
+//
+// The sentinel is read back as is without this option, so the option must be kept once it is enabled. The other codecs,
+// such as JSONCodec, have their own representation of nil.
+func WithNilSlices() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withNilSlices()
+	})
+}
+
+// isNilSlice tells whether the value is a nil byte slice that is stored as the nil sentinel, see WithNilSlices.
+func (ss *KeyringStorage[V]) isNilSlice(v V) bool {
+	if _, ok := ss.codec.(TextCodec); !ok || !ss.nilSlices {
+		return false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	return rv.IsValid() && isByteSlice(rv) && rv.IsNil()
+}
+
+// marshal marshals the value with the codec, or returns the nil sentinel for the nil byte slices, see WithNilSlices.
+func (ss *KeyringStorage[V]) marshal(v V) ([]byte, error) {
+	if ss.isNilSlice(v) {
+		return []byte(mimeNilSecret), nil
+	}
+
+	return ss.codec.Marshal(v) //nolint: wrapcheck
+}
@@ -0,0 +1,114 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+type rawToken []byte
+
+func TestKeyringStorage_NilSlices(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		options        []secretstorage.KeyringStorageOption
+		value          []byte
+		expectedStored string
+		expectedNil    bool
+	}{
+		{
+			scenario:       "nil is read back as empty by default",
+			value:          nil,
+			expectedStored: "",
+		},
+		{
+			scenario:       "empty is read back as empty by default",
+			value:          []byte{},
+			expectedStored: "",
+		},
+		{
+			scenario:       "nil is read back as nil",
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithNilSlices()},
+			value:          nil,
+			expectedStored: "application/nil-secret",
+			expectedNil:    true,
+		},
+		{
+			scenario:       "empty is read back as empty",
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithNilSlices()},
+			value:          []byte{},
+			expectedStored: "",
+		},
+		{
+			scenario:       "value is read back as is",
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithNilSlices()},
+			value:          []byte("secret"),
+			expectedStored: "secret",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[[]byte](append(tc.options, secretstorage.WithKeyring(k))...)
+
+			require.NoError(t, s.Set(t.Name(), "key", tc.value))
+
+			assert.Equal(t, map[string]string{"key": tc.expectedStored}, k.entries(t.Name()))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+
+			if tc.expectedNil {
+				assert.Nil(t, actual)
+			} else {
+				assert.NotNil(t, actual)
+				assert.Equal(t, string(tc.value), string(actual))
+			}
+		})
+	}
+}
+
+func TestKeyringStorage_NilSlices_DefinedType(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[rawToken](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithNilSlices())
+
+	require.NoError(t, s.Set(t.Name(), "nil", nil))
+	require.NoError(t, s.Set(t.Name(), "empty", rawToken{}))
+
+	actual, err := s.Get(t.Name(), "nil")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	actual, err = s.Get(t.Name(), "empty")
+	require.NoError(t, err)
+	assert.Equal(t, rawToken{}, actual)
+}
+
+func TestKeyringStorage_NilSlices_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[[]byte](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithEncryption(encryptionKey),
+		secretstorage.WithNilSlices(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", nil))
+	assert.NotContains(t, k.entries(t.Name())["key"], "nil-secret")
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+}
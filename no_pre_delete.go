@@ -0,0 +1,18 @@
+package secretstorage
+
+func (ss *KeyringStorage[V]) withNoPreDelete() {
+	ss.noPreDelete = true
+}
+
+// WithNoPreDelete makes Set write the new data over the old one, without deleting the old data first, for the keyrings
+// that keep the history of their entries, and would lose it with the deletion.
+//
+// The header of a multipart data has its number of pages, so the pages of an old larger value that are beyond it are
+// ignored when the data is read. But they are orphaned: they stay in the keyring until they are overwritten by a
+// larger value, or deleted with WithAggressiveDelete. It can not be combined with WithAtomicSwap, which deletes the old
+// pages after the write: the storage is rejected with ErrInvalidOption, see NewKeyringStorage and Reconfigure.
+func WithNoPreDelete() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withNoPreDelete()
+	})
+}
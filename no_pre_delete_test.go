@@ -0,0 +1,83 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_NoPreDelete_LargeThenSmall(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithNoPreDelete(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world, how are you?"))
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello world", actual)
+
+	// The pages of the large value beyond the pages of the small one are orphaned.
+	expected := map[string]string{
+		"key":      "application/multipart-secret; pages=3",
+		"key-0001": "hello",
+		"key-0002": " worl",
+		"key-0003": "d",
+		"key-0004": "w are",
+		"key-0005": " you?",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_NoPreDelete_MultipartThenSingle(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithNoPreDelete(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+	require.NoError(t, s.Set(t.Name(), "key", "hi"))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi", actual)
+	assert.Len(t, k.entries(t.Name()), 4)
+}
+
+func TestKeyringStorage_NoPreDelete_AggressiveDelete(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithNoPreDelete(),
+		secretstorage.WithAggressiveDelete(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world, how are you?"))
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	assert.Empty(t, k.entries(t.Name()))
+}
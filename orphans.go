@@ -0,0 +1,288 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+func (ss *KeyringStorage[V]) withAggressiveDelete() {
+	ss.aggressiveDelete = true
+}
+
+// WithAggressiveDelete makes Delete look for the orphaned pages of the multipart data, beyond its number of pages, and
+// delete them. Such pages are left behind by a write that was interrupted, for example by a crash, or by
+// WithNoPreDelete.
+//
+// Only the pages of the generation of the header, beyond its number of pages, are orphaned. If the keyring implements
+// Lister, all these pages are found. Otherwise, they are probed one by one, until a page is not found. When the main
+// entry is gone or is not multipart, nothing tells the pages from the keys on their own, such as "key-0001" next to
+// "key", so nothing else is deleted. The entries in one of the stored formats, such as multipart, are kept too, see
+// CleanOrphans.
+func WithAggressiveDelete() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withAggressiveDelete()
+	})
+}
+
+// CleanOrphans deletes the orphaned pages of the service, and returns their keys, sorted. Such pages are left behind by
+// the writes that were interrupted, for example by a crash. A page is orphaned if its key is multipart, and it is not
+// one of the pages of the header: it is beyond them, or of another generation, see WithAtomicSwap.
+//
+// The entries next to a key that does not exist or is not multipart, such as "key-0001" next to "key", are keys on
+// their own, they are kept. So are the entries in one of the stored formats, such as multipart, and the ones that the
+// storage has written.
+//
+// The entries of the service are found with List, the keyring must implement Lister. Each key is locked while its pages
+// are checked and deleted. The cleaning goes on when a key fails, the errors are returned together.
+func (ss *KeyringStorage[V]) CleanOrphans(service string) ([]string, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	l, err := ss.lister()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := l.List(service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data in keyring: %w", err)
+	}
+
+	// The pages are grouped by key, so that each key is locked once.
+	pages := make(map[string][]string)
+
+	for _, e := range entries {
+		base, _, ok := ss.pageFormat.parse(e)
+		if !ok {
+			continue
+		}
+
+		key, _ := splitGeneration(base)
+		pages[key] = append(pages[key], e)
+	}
+
+	keys := make([]string, 0, len(pages))
+
+	for key := range pages {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var deleted []string
+
+	for _, key := range keys {
+		d, cErr := ss.cleanOrphans(service, key, pages[key])
+		deleted = append(deleted, d...)
+
+		if cErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to clean the orphans of %q: %w", key, cErr))
+		}
+	}
+
+	sort.Strings(deleted)
+
+	return deleted, err
+}
+
+// cleanOrphans locks the key, and deletes the pages that are not the ones of its header.
+func (ss *KeyringStorage[V]) cleanOrphans(service string, key string, pages []string) ([]string, error) {
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	h, ok, err := ss.orphansHeader(service, key)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+
+	for _, e := range pages {
+		base, page, _ := ss.pageFormat.parse(e)
+
+		if _, g := splitGeneration(base); h.hasPage(g, page) {
+			continue
+		}
+
+		ok, err := ss.deleteOrphan(service, e)
+		if err != nil {
+			return deleted, err
+		}
+
+		if ok {
+			deleted = append(deleted, e)
+		}
+	}
+
+	return deleted, nil
+}
+
+// orphansHeader returns the header of the data of the key. The boolean is false if the key does not exist or is not
+// multipart, it has no orphans. The pages of a value that is marked as deleted are not orphaned, see WithSoftDelete.
+func (ss *KeyringStorage[V]) orphansHeader(service string, key string) (multipartHeader, bool, error) {
+	d, err := ss.keyring.Get(service, key)
+	if errors.Is(err, ErrNotFound) {
+		return multipartHeader{}, false, nil
+	} else if err != nil {
+		return multipartHeader{}, false, fmt.Errorf("failed to read data from keyring: %w", err)
+	}
+
+	if d = untombstone(d); !isMultipart(d) {
+		return multipartHeader{}, false, nil
+	}
+
+	h, err := parseMultipartHeader(d)
+	if err == nil {
+		err = h.checkPages(ss.pageFormat)
+	}
+
+	return h, err == nil, err
+}
+
+// hasPage tells whether the page of the generation is one of the pages of the header.
+func (h multipartHeader) hasPage(generation int, page int) bool {
+	return generation == h.generation && page <= h.pages
+}
+
+// isOrphan tells whether the page of the generation is orphaned: it is beyond the pages of the header, of the same
+// generation.
+func (h multipartHeader) isOrphan(generation int, page int) bool {
+	return generation == h.generation && page > h.pages
+}
+
+// deleteOrphans deletes the pages of the multipart data that are left after the deletion of its data, whose header is
+// given.
+func (ss *KeyringStorage[V]) deleteOrphans(service string, key string, h multipartHeader) error {
+	if l, err := ss.lister(); err == nil {
+		_, err = ss.deleteListedOrphans(l, service, key, h)
+
+		return err
+	}
+
+	_, err := ss.probeOrphans(service, key, h)
+
+	return err
+}
+
+// deleteListedOrphans deletes the orphaned pages of the key that the keyring lists.
+func (ss *KeyringStorage[V]) deleteListedOrphans(l Lister, service string, key string, h multipartHeader) (int, error) {
+	entries, err := l.List(service)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list data in keyring: %w", err)
+	}
+
+	deleted := 0
+
+	for _, e := range entries {
+		base, page, ok := ss.pageFormat.parse(e)
+		if !ok {
+			continue
+		}
+
+		if g, ok := generationOf(base, key); !ok || !h.isOrphan(g, page) {
+			continue
+		}
+
+		ok, err := ss.deleteOrphan(service, e)
+		if err != nil {
+			return deleted, err
+		}
+
+		if ok {
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// probeOrphans deletes the pages of the generation of the header, from the one after its last page, until a page is
+// not found, or is a key on its own.
+func (ss *KeyringStorage[V]) probeOrphans(service string, key string, h multipartHeader) (int, error) {
+	deleted := 0
+
+	for page := h.pages + 1; page <= ss.pageFormat.maxPages(); page++ {
+		ok, err := ss.deleteOrphan(service, h.pageKey(ss.pageFormat, key, page))
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete orphaned data #%d in keyring: %w", page, err)
+		}
+
+		if !ok {
+			break
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// deleteOrphan deletes the orphaned page, unless it is a key on its own. The boolean is false if the page is not found,
+// or is kept.
+func (ss *KeyringStorage[V]) deleteOrphan(service string, entry string) (bool, error) {
+	if ss.written.has(service, entry) {
+		return false, nil
+	}
+
+	d, err := ss.keyring.Get(service, entry)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read orphaned data %q from keyring: %w", entry, err)
+	}
+
+	if _, ok := storedFormats.lookup(d); ok {
+		return false, nil
+	}
+
+	if err := ss.keyring.Delete(service, entry); err != nil && !errors.Is(err, ErrNotFound) {
+		return false, fmt.Errorf("failed to delete orphaned data %q in keyring: %w", entry, err)
+	}
+
+	return true, nil
+}
+
+// generationOf returns the generation of the base of a page, if it is the key, with or without a generation.
+func generationOf(base string, key string) (int, bool) {
+	if base == key {
+		return 0, true
+	}
+
+	g, ok := strings.CutPrefix(base, key+"~")
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(g)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// splitGeneration returns the key and the generation of the base of a page.
+func splitGeneration(base string) (string, int) {
+	i := strings.LastIndex(base, "~")
+	if i < 0 {
+		return base, 0
+	}
+
+	if g, ok := generationOf(base, base[:i]); ok {
+		return base[:i], g
+	}
+
+	return base, 0
+}
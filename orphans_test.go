@@ -0,0 +1,281 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_AggressiveDelete_Listing(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		entries       map[string]string
+		expectedError error
+		expected      map[string]string
+	}{
+		{
+			scenario: "no orphans",
+			entries: map[string]string{
+				"another key": "value",
+			},
+			expectedError: secretstorage.ErrNotFound,
+			expected: map[string]string{
+				"another key": "value",
+			},
+		},
+		{
+			scenario: "pages without header",
+			entries: map[string]string{
+				"key-0001":   "hello",
+				"key-0002":   " worl",
+				"key~3-0001": "hello",
+			},
+			expectedError: secretstorage.ErrNotFound,
+			expected: map[string]string{
+				"key-0001":   "hello",
+				"key-0002":   " worl",
+				"key~3-0001": "hello",
+			},
+		},
+		{
+			scenario: "pages with single entry",
+			entries: map[string]string{
+				"key":      "value",
+				"key-0003": "d",
+			},
+			expected: map[string]string{
+				"key-0003": "d",
+			},
+		},
+		{
+			scenario: "orphans after multipart",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=2",
+				"key-0001": "hello",
+				"key-0002": " worl",
+				"key-0003": "d",
+			},
+			expected: map[string]string{},
+		},
+		{
+			scenario: "orphans of another generation",
+			entries: map[string]string{
+				"key":        "application/multipart-secret; generation=2; pages=2",
+				"key~2-0001": "hello",
+				"key~2-0002": " worl",
+				"key~2-0003": "d",
+				"key~1-0001": "another generation",
+				"key-0002":   "sibling",
+			},
+			expected: map[string]string{
+				"key~1-0001": "another generation",
+				"key-0002":   "sibling",
+			},
+		},
+		{
+			scenario: "sibling in a stored format",
+			entries: map[string]string{
+				"key":           "application/multipart-secret; pages=2",
+				"key-0001":      "hello",
+				"key-0002":      " worl",
+				"key-0003":      "application/labeled-secret; label-owner=alice\nsibling",
+				"another-0001":  "value",
+				"key~beta-0001": "value",
+			},
+			expected: map[string]string{
+				"key-0003":      "application/labeled-secret; label-owner=alice\nsibling",
+				"another-0001":  "value",
+				"key~beta-0001": "value",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+
+			for key, value := range tc.entries {
+				require.NoError(t, k.Set(t.Name(), key, value))
+			}
+
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithAggressiveDelete())
+
+			err := s.Delete(t.Name(), "key")
+
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expected, k.entries(t.Name()))
+		})
+	}
+}
+
+func TestKeyringStorage_AggressiveDelete_Probing(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	for key, value := range map[string]string{
+		"key":        "application/multipart-secret; pages=2",
+		"key-0001":   "hello",
+		"key-0002":   " worl",
+		"key-0003":   "d",
+		"key-0005":   "unreachable",
+		"key~3-0001": "unknown generation",
+	} {
+		require.NoError(t, k.Set(t.Name(), key, value))
+	}
+
+	// The keyring does not implement Lister.
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(struct{ keyring.Keyring }{k}),
+		secretstorage.WithAggressiveDelete(),
+	)
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	expected := map[string]string{
+		"key-0005":   "unreachable",
+		"key~3-0001": "unknown generation",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	err := s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestKeyringStorage_Delete_KeepsOrphans(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key-0001", "hello"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	assert.Equal(t, map[string]string{"key-0001": "hello"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_AggressiveDelete_KeepsWrittenSiblings(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithAggressiveDelete())
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-0001", "hello"))
+	require.NoError(t, k.Set(t.Name(), "key-0002", " worl"))
+	require.NoError(t, s.Set(t.Name(), "key-0003", "sibling"))
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	assert.Equal(t, map[string]string{"key-0003": "sibling"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_AggressiveDelete_KeepsUnrelatedKeys(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	// The key is written by another process, the storage does not know it.
+	require.NoError(t, secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k)).Set(t.Name(), "build-0001", "token"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithAggressiveDelete())
+
+	err := s.Delete(t.Name(), "build")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	deleted, err := s.CleanOrphans(t.Name())
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	actual, err := s.Get(t.Name(), "build-0001")
+	require.NoError(t, err)
+	assert.Equal(t, "token", actual)
+}
+
+func TestKeyringStorage_CleanOrphans(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	for key, value := range map[string]string{
+		"key":             "application/multipart-secret; pages=2",
+		"key-0001":        "hello",
+		"key-0002":        " worl",
+		"key-0003":        "d",
+		"key~5-0001":      "another generation",
+		"gone-0001":       "no key",
+		"single":          "value",
+		"single-0001":     "not multipart",
+		"other-0001":      "application/multipart-secret; pages=2",
+		"other-0001-0001": "hello",
+		"other-0001-0002": " world",
+	} {
+		require.NoError(t, k.Set(t.Name(), key, value))
+	}
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "single-0002", "sibling"))
+
+	deleted, err := s.CleanOrphans(t.Name())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"key-0003", "key~5-0001"}, deleted)
+
+	expected := map[string]string{
+		"key":             "application/multipart-secret; pages=2",
+		"key-0001":        "hello",
+		"key-0002":        " worl",
+		"gone-0001":       "no key",
+		"single":          "value",
+		"single-0001":     "not multipart",
+		"single-0002":     "sibling",
+		"other-0001":      "application/multipart-secret; pages=2",
+		"other-0001-0001": "hello",
+		"other-0001-0002": " world",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "hello worl", actual)
+
+	// Nothing is left to clean.
+	deleted, err = s.CleanOrphans(t.Name())
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+}
+
+func TestKeyringStorage_CleanOrphans_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(struct{ keyring.Keyring }{newMemoryKeyring()}))
+
+	_, err := s.CleanOrphans(t.Name())
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+
+	s = secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithReadOnly())
+
+	_, err = s.CleanOrphans(t.Name())
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
@@ -0,0 +1,83 @@
+package secretstorage
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// defaultPageFormat formats the pages as "key-0001".
+var defaultPageFormat = pageFormat{sep: "-", width: 4}
+
+// pageFormat tells how the keys of the pages are formatted: the key, the separator, and the page number padded with
+// zeros to the width.
+type pageFormat struct {
+	sep   string
+	width int
+	// legacyUnpadded reads the pages without the zero padding too, see WithLegacyPageFormat.
+	legacyUnpadded bool
+}
+
+func (f pageFormat) format(key string, page int) string {
+	return fmt.Sprintf("%s%s%0*d", key, f.sep, f.width, page)
+}
+
+// parse is the reverse of format. It returns false if the key is not a page.
+func (f pageFormat) parse(key string) (string, int, bool) {
+	i := strings.LastIndex(key, f.sep)
+	if i < 0 || len(key)-i-len(f.sep) != f.width {
+		return "", 0, false
+	}
+
+	page, err := strconv.Atoi(key[i+len(f.sep):])
+	if err != nil || page < 1 {
+		return "", 0, false
+	}
+
+	return key[:i], page, true
+}
+
+// maxPages returns the highest page number that fits in the width.
+func (f pageFormat) maxPages() int {
+	n := 1
+
+	for i := 0; i < f.width && n <= math.MaxInt32; i++ {
+		n *= 10
+	}
+
+	return n - 1
+}
+
+// validate checks that the format is usable, and that it accommodates the max pages, if any.
+func (f pageFormat) validate(maxPages int) error {
+	switch {
+	case f.sep == "":
+		return fmt.Errorf("%w: page separator is empty", ErrInvalidOption)
+
+	case f.width < 1:
+		return fmt.Errorf("%w: page width is less than 1: %d", ErrInvalidOption, f.width)
+
+	case maxPages > f.maxPages():
+		return fmt.Errorf("%w: page width %d does not accommodate %d pages", ErrInvalidOption, f.width, maxPages)
+	}
+
+	return nil
+}
+
+func (ss *KeyringStorage[V]) withPageFormat(sep string, width int) {
+	ss.pageFormat.sep = sep
+	ss.pageFormat.width = width
+}
+
+// WithPageFormat sets how the keys of the pages are formatted: the key, the separator, and the page number padded with
+// zeros to the width. The default is "-" and 4, for example "key-0001". The data that needs more pages than the width
+// accommodates is rejected with ErrTooManyPages before anything is written.
+//
+// The data that was written with another page format cannot be read or deleted, except the pages without the zero
+// padding, see WithLegacyPageFormat.
+func WithPageFormat(sep string, width int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withPageFormat(sep, width)
+	})
+}
@@ -0,0 +1,90 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_PageFormat_SixDigits(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithPageFormat("_", 6),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	expected := map[string]string{
+		"key":        "application/multipart-secret; pages=3",
+		"key_000001": "hello",
+		"key_000002": " worl",
+		"key_000003": "d",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", actual)
+
+	all, err := s.GetAll(t.Name())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "hello world"}, all)
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_PageFormat_DefaultFormatIsNotRead(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+	).Set(t.Name(), "key", "hello world"))
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithPageFormat("-", 6),
+	)
+
+	_, err := s.Get(t.Name(), "key")
+	require.EqualError(t, err, "failed to read multipart data #1 from keyring: secret not found in keyring")
+}
+
+func TestKeyringStorage_PageFormat_TooManyPages(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(1),
+		secretstorage.WithPageFormat("-", 1),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "123456789"))
+
+	err := s.Set(t.Name(), "key", "0123456789")
+
+	require.ErrorIs(t, err, secretstorage.ErrTooManyPages)
+	require.EqualError(t, err, "too many pages: the data needs 10 pages, the page format accommodates 9")
+
+	// Nothing is written.
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "123456789", actual)
+
+	p, err := s.PlanSet("0123456789")
+	require.ErrorIs(t, err, secretstorage.ErrTooManyPages)
+	assert.Equal(t, 10, p.Pages)
+}
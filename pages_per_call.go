@@ -0,0 +1,95 @@
+package secretstorage
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+func (ss *KeyringStorage[V]) withPagesPerCall(n int) {
+	ss.pagesPerCall = n
+}
+
+// WithPagesPerCall reads and writes up to n pages of a multipart value at once, to balance the latency of the long
+// values and the load of the keyring. The pages are read and written one by one by default, or if n is 1. The
+// concurrency of the whole storage is still limited by WithMaxConcurrency.
+//
+// When several pages fail, the error of the lowest page is returned, and the pages after it are not started.
+func WithPagesPerCall(n int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withPagesPerCall(n)
+	})
+}
+
+func (ss *KeyringStorage[V]) withParallelReads(n int) {
+	ss.parallelReads = n
+}
+
+// WithParallelReads reads up to n pages of a multipart value at once, regardless of WithPagesPerCall, which still
+// applies to the writes. This suits the keyrings with a high latency, where the reads are frequent and the writes are
+// rare. The pages are joined in order, and the value is checked once they are all read, so the errors are the same as
+// with the sequential reads: the error of the lowest missing or failing page is returned, and the pages after it are
+// not started.
+func WithParallelReads(n int) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withParallelReads(n)
+	})
+}
+
+// readsPerCall returns the number of pages that are read at once.
+func (ss *KeyringStorage[V]) readsPerCall() int {
+	if ss.parallelReads > 0 {
+		return ss.parallelReads
+	}
+
+	return ss.pagesPerCall
+}
+
+// forEachPage calls the function for the pages from 1 to the given number, at most concurrency at once, and returns
+// the error of the lowest failing page. No page is started after a failure.
+func forEachPage(pages int, concurrency int, fn func(page int) error) error {
+	if concurrency <= 1 {
+		for page := 1; page <= pages; page++ {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Bool
+	)
+
+	errs := make([]error, pages+1)
+	sem := make(chan struct{}, concurrency)
+
+	for page := 1; page <= pages && !failed.Load(); page++ {
+		sem <- struct{}{}
+
+		wg.Add(1)
+
+		go func(page int) {
+			defer func() {
+				<-sem
+
+				wg.Done()
+			}()
+
+			if errs[page] = fn(page); errs[page] != nil {
+				failed.Store(true)
+			}
+		}(page)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
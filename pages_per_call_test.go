@@ -0,0 +1,148 @@
+package secretstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+// failingPagesKeyring is a memoryKeyring that fails to write the given pages.
+type failingPagesKeyring struct {
+	*concurrencyKeyring
+
+	failing map[string]bool
+}
+
+func (k *failingPagesKeyring) Set(service, user, password string) error {
+	if k.failing[user] {
+		defer k.track()()
+
+		return assert.AnError
+	}
+
+	return k.concurrencyKeyring.Set(service, user, password)
+}
+
+func TestKeyringStorage_PagesPerCall(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario     string
+		pagesPerCall int
+	}{
+		{
+			scenario: "default",
+		},
+		{
+			scenario:     "sequential",
+			pagesPerCall: 1,
+		},
+		{
+			scenario:     "4 pages per call",
+			pagesPerCall: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newConcurrencyKeyring(5 * time.Millisecond)
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(k),
+				secretstorage.WithMaxLength(10),
+				secretstorage.WithPagesPerCall(tc.pagesPerCall),
+			)
+
+			value := randString(100)
+
+			require.NoError(t, s.Set(t.Name(), "key", value))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, value, actual)
+
+			expected := int64(max(tc.pagesPerCall, 1))
+
+			assert.Equal(t, expected, k.maxConcurrentCalls())
+		})
+	}
+}
+
+func TestKeyringStorage_PagesPerCall_LowestFailingPage(t *testing.T) {
+	t.Parallel()
+
+	k := &failingPagesKeyring{
+		concurrencyKeyring: newConcurrencyKeyring(5 * time.Millisecond),
+		failing:            map[string]bool{formatPage("key", 3): true, formatPage("key", 2): true},
+	}
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithPagesPerCall(4),
+	)
+
+	for i := 0; i < 10; i++ {
+		err := s.Set(t.Name(), "key", randString(100))
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to write multipart data #2 to keyring")
+	}
+
+	// The pages that are written are deleted, and the pages after the failure are not started.
+	assert.Empty(t, k.entries(t.Name()))
+	assert.LessOrEqual(t, k.maxConcurrentCalls(), int64(4))
+}
+
+func TestKeyringStorage_ParallelReads(t *testing.T) {
+	t.Parallel()
+
+	k := newConcurrencyKeyring(5 * time.Millisecond)
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithParallelReads(4),
+	)
+
+	value := randString(100)
+
+	// The pages are still written one by one.
+	require.NoError(t, s.Set(t.Name(), "key", value))
+	assert.Equal(t, int64(1), k.maxConcurrentCalls())
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, value, actual)
+
+	assert.Equal(t, int64(4), k.maxConcurrentCalls())
+}
+
+func TestKeyringStorage_ParallelReads_LowestMissingPage(t *testing.T) {
+	t.Parallel()
+
+	k := newConcurrencyKeyring(time.Millisecond)
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithParallelReads(4),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", randString(100)))
+	require.NoError(t, k.memoryKeyring.Delete(t.Name(), formatPage("key", 7)))
+	require.NoError(t, k.memoryKeyring.Delete(t.Name(), formatPage("key", 5)))
+
+	for i := 0; i < 10; i++ {
+		actual, err := s.Get(t.Name(), "key")
+
+		require.ErrorIs(t, err, secretstorage.ErrNotFound)
+		assert.ErrorContains(t, err, "failed to read multipart data #5 from keyring")
+		assert.Empty(t, actual)
+	}
+}
@@ -0,0 +1,33 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrKeyringPanic indicates that the keyring panicked, see WithPanicRecovery.
+var ErrKeyringPanic = errors.New("keyring panicked")
+
+func (ss *KeyringStorage[V]) withPanicRecovery() {
+	ss.panicRecovery = true
+}
+
+// WithPanicRecovery recovers the panics of the keyring, for example of go-keyring when DBus misbehaves, and returns
+// them as errors that match ErrKeyringPanic, instead of crashing the process. The panics count as failures for the
+// circuit breaker, see WithCircuitBreaker.
+func WithPanicRecovery() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withPanicRecovery()
+	})
+}
+
+// recoverPanic calls the function, and returns its panic as an error.
+func recoverPanic(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrKeyringPanic, r)
+		}
+	}()
+
+	return fn()
+}
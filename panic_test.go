@@ -0,0 +1,45 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_PanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").Panic("dbus: connection closed")
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithPanicRecovery())
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrKeyringPanic)
+	require.EqualError(t, err, "failed to read data from keyring: keyring panicked: dbus: connection closed")
+
+	err = s.Set(t.Name(), "key", "value")
+	require.ErrorIs(t, err, secretstorage.ErrKeyringPanic)
+
+	err = s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrKeyringPanic)
+}
+
+func TestKeyringStorage_NoPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").Panic("dbus: connection closed")
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	assert.Panics(t, func() {
+		_, _ = s.Get(t.Name(), "key") //nolint: errcheck
+	})
+}
@@ -0,0 +1,57 @@
+package secretstorage
+
+import "errors"
+
+// peekAttempts is the number of lock-free reads of Peek before it waits for the write in progress.
+const peekAttempts = 3
+
+// Peek gets the value for the given key, like Get, without waiting for the write in progress on the key, if any. It is
+// meant for the reads that tolerate a stale value, such as the monitoring dashboards.
+//
+// It requires WithAtomicSwap, so that the value that is committed stays readable while the new one is written: Peek
+// may return the value before the write while Set is in progress. Without it, the old value may be deleted before the
+// new one is written, so Peek waits for the write like Get.
+//
+// The header is read again after the value, and the value is read again if the header has changed meanwhile, so that
+// the pages of two values are not mixed. After a few attempts, Peek waits for the write like Get. Two writes that
+// complete during a single read, with the same header, are not detected, see WithStoredLength to reject the mixed
+// values of different lengths.
+//
+// Unlike Get, the case variants and the aliases of the key are not read, and the expired values are not deleted.
+func (ss *KeyringStorage[V]) Peek(service string, key string) (V, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.atomicSwap {
+		for i := 0; i < peekAttempts; i++ {
+			if v, ok, err := ss.peek(service, key); ok {
+				return v, ss.contextualError(ss.notFound(err), service, key)
+			}
+		}
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	v, _, err := ss.get(service, key)
+
+	return v, ss.contextualError(ss.notFound(err), service, key)
+}
+
+// peek reads the value without locking the key. The boolean is false if the header has changed during the read, the
+// value may be mixed with the pages of another value.
+func (ss *KeyringStorage[V]) peek(service string, key string) (V, bool, error) {
+	before, bErr := ss.keyring.Get(service, key)
+	v, _, err := ss.get(service, key)
+	after, aErr := ss.keyring.Get(service, key)
+
+	if bErr != nil || aErr != nil {
+		// The value is not found on both reads, or the keyring fails.
+		return v, errors.Is(bErr, ErrNotFound) == errors.Is(aErr, ErrNotFound), err
+	}
+
+	return v, before == after, err
+}
@@ -0,0 +1,200 @@
+package secretstorage_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+// blockingKeyring blocks the writes of the pages, once it is armed, until it is released.
+type blockingKeyring struct {
+	*memoryKeyring
+
+	key      string
+	armed    chan struct{}
+	blocked  chan struct{}
+	released chan struct{}
+	once     sync.Once
+}
+
+func (k *blockingKeyring) Set(service, user, password string) error {
+	select {
+	case <-k.armed:
+		if strings.HasPrefix(user, k.key+"~") || strings.HasPrefix(user, k.key+"-") {
+			k.once.Do(func() { close(k.blocked) })
+
+			<-k.released
+		}
+
+	default:
+	}
+
+	return k.memoryKeyring.Set(service, user, password)
+}
+
+func newBlockingKeyring(key string) *blockingKeyring {
+	return &blockingKeyring{
+		memoryKeyring: newMemoryKeyring(),
+		key:           key,
+		armed:         make(chan struct{}),
+		blocked:       make(chan struct{}),
+		released:      make(chan struct{}),
+	}
+}
+
+func TestKeyringStorage_Peek_DuringSet(t *testing.T) {
+	t.Parallel()
+
+	k := newBlockingKeyring("key")
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "the old value"))
+
+	close(k.armed)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		assert.NoError(t, s.Set(t.Name(), "key", "the new value"))
+	}()
+
+	<-k.blocked
+
+	actual, err := s.Peek(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "the old value", actual)
+
+	select {
+	case <-done:
+		t.Fatal("the write is not blocked")
+	default:
+	}
+
+	close(k.released)
+	<-done
+
+	actual, err = s.Peek(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "the new value", actual)
+}
+
+func TestKeyringStorage_Peek_WithoutAtomicSwap(t *testing.T) {
+	t.Parallel()
+
+	k := newBlockingKeyring("key")
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", "the old value"))
+
+	close(k.armed)
+
+	go func() {
+		assert.NoError(t, s.Set(t.Name(), "key", "the new value"))
+	}()
+
+	<-k.blocked
+
+	peeked := make(chan string)
+
+	go func() {
+		actual, err := s.Peek(t.Name(), "key")
+		assert.NoError(t, err)
+
+		peeked <- actual
+	}()
+
+	select {
+	case <-peeked:
+		t.Fatal("the read does not wait for the write")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(k.released)
+
+	assert.Equal(t, "the new value", <-peeked)
+}
+
+func TestKeyringStorage_Peek_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	_, err := s.Peek(t.Name(), "key")
+
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+// hookedKeyring calls the hook once, before the first read of the entry.
+type hookedKeyring struct {
+	*memoryKeyring
+
+	entry string
+	hook  func()
+	once  sync.Once
+}
+
+func (k *hookedKeyring) Get(service, user string) (string, error) {
+	if user == k.entry {
+		k.once.Do(k.hook)
+	}
+
+	return k.memoryKeyring.Get(service, user)
+}
+
+func TestKeyringStorage_Peek_DoesNotMixValues(t *testing.T) {
+	t.Parallel()
+
+	k := &hookedKeyring{memoryKeyring: newMemoryKeyring()}
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", strings.Repeat("a", 30)))
+
+	var entries []string
+
+	for e := range k.entries(t.Name()) {
+		if strings.HasSuffix(e, "2") {
+			entries = append(entries, e)
+		}
+	}
+
+	require.Len(t, entries, 1)
+
+	// Two writes complete after the first page is read, the second one writes the pages of the same generation.
+	k.entry = entries[0]
+	k.hook = func() {
+		assert.NoError(t, s.Set(t.Name(), "key", strings.Repeat("b", 30)))
+		assert.NoError(t, s.Set(t.Name(), "key", strings.Repeat("c", 40)))
+	}
+
+	actual, err := s.Peek(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, strings.Repeat("c", 40), actual)
+}
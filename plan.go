@@ -0,0 +1,55 @@
+package secretstorage
+
+import "fmt"
+
+// PlanInfo describes how a value would be stored in the keyring.
+type PlanInfo struct {
+	// Length is the length of the marshaled data.
+	Length int
+	// Multipart tells whether the data would be split into pages.
+	Multipart bool
+	// Pages is the number of pages of the data, 0 if the data is not multipart.
+	Pages int
+}
+
+// PlanSet tells how the value would be stored by Set, with the current configuration of the storage, such as the codec
+// and the max length. The value is marshaled, but the keyring is not called. If the data needs more pages than allowed
+// by WithMaxPages, the plan is returned together with ErrTooManyPages.
+func (ss *KeyringStorage[V]) PlanSet(value V) (PlanInfo, error) {
+	defer ss.rlockConfig()()
+
+	// The service and the key do not change the length of the data.
+	d, err := ss.encode("", "", value)
+	if err != nil {
+		return PlanInfo{}, fmt.Errorf("failed to marshal data: %w", tagError(ErrMarshal, err))
+	}
+
+	p := ss.plan(len(d))
+
+	return p, ss.checkPages(p)
+}
+
+// checkPages returns ErrTooManyPages if the data needs more pages than allowed by WithMaxPages, or than the page format
+// accommodates.
+func (ss *KeyringStorage[V]) checkPages(p PlanInfo) error {
+	if ss.maxPages > 0 && p.Pages > ss.maxPages {
+		return fmt.Errorf("%w: the data needs %d pages, the limit is %d", ErrTooManyPages, p.Pages, ss.maxPages)
+	}
+
+	if limit := ss.pageFormat.maxPages(); p.Pages > limit {
+		return fmt.Errorf("%w: the data needs %d pages, the page format accommodates %d", ErrTooManyPages, p.Pages, limit)
+	}
+
+	return nil
+}
+
+func (ss *KeyringStorage[V]) plan(length int) PlanInfo {
+	p := PlanInfo{Length: length}
+
+	if length > ss.maxLength {
+		p.Multipart = true
+		p.Pages = ss.countPages(length)
+	}
+
+	return p
+}
@@ -0,0 +1,183 @@
+package secretstorage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_PlanSet(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+		value    string
+		expected secretstorage.PlanInfo
+	}{
+		{
+			scenario: "empty",
+			expected: secretstorage.PlanInfo{},
+		},
+		{
+			scenario: "at max length",
+			value:    strings.Repeat("a", 2048),
+			expected: secretstorage.PlanInfo{Length: 2048},
+		},
+		{
+			scenario: "above max length",
+			value:    strings.Repeat("a", 2049),
+			expected: secretstorage.PlanInfo{Length: 2049, Multipart: true, Pages: 2},
+		},
+		{
+			scenario: "full pages",
+			value:    strings.Repeat("a", 4096),
+			expected: secretstorage.PlanInfo{Length: 4096, Multipart: true, Pages: 2},
+		},
+		{
+			scenario: "above full pages",
+			value:    strings.Repeat("a", 4097),
+			expected: secretstorage.PlanInfo{Length: 4097, Multipart: true, Pages: 3},
+		},
+		{
+			scenario: "custom max length",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithMaxLength(10)},
+			value:    strings.Repeat("a", 25),
+			expected: secretstorage.PlanInfo{Length: 25, Multipart: true, Pages: 3},
+		},
+		{
+			scenario: "codec",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCodec(secretstorage.JSONCodec{}), secretstorage.WithForceCodec()},
+			value:    strings.Repeat("a", 2000),
+			expected: secretstorage.PlanInfo{Length: 2041, Multipart: false},
+		},
+		{
+			scenario: "codec above max length",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCodec(secretstorage.JSONCodec{}), secretstorage.WithForceCodec()},
+			value:    strings.Repeat("a", 2008),
+			expected: secretstorage.PlanInfo{Length: 2049, Multipart: true, Pages: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewKeyringStorage[string](append(tc.options, secretstorage.WithKeyring(mock.NopKeyring(t)))...)
+
+			actual, err := s.PlanSet(tc.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_PlanSet_UnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[int](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	actual, err := s.PlanSet(42)
+
+	require.EqualError(t, err, "failed to marshal data: unsupported type: int")
+	require.ErrorIs(t, err, secretstorage.ErrUnsupportedType)
+	require.ErrorIs(t, err, secretstorage.ErrMarshal)
+	assert.Equal(t, secretstorage.PlanInfo{}, actual)
+}
+
+func TestKeyringStorage_PlanSet_TooManyPages(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(mock.NopKeyring(t)),
+		secretstorage.WithMaxPages(2),
+	)
+
+	actual, err := s.PlanSet(strings.Repeat("a", 4097))
+
+	require.EqualError(t, err, "too many pages: the data needs 3 pages, the limit is 2")
+	assert.Equal(t, secretstorage.PlanInfo{Length: 4097, Multipart: true, Pages: 3}, actual)
+}
+
+func TestKeyringStorage_MaxLength(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+	)
+
+	err := s.Set(t.Name(), "key", "hello world")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"key":      "application/multipart-secret; pages=3",
+		"key-0001": "hello",
+		"key-0002": " worl",
+		"key-0003": "d",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello world", actual)
+}
+
+func TestKeyringStorage_MaxLength_Boundary(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		value    string
+		expected map[string]string
+	}{
+		{
+			scenario: "exactly at the max length",
+			value:    "a",
+			expected: map[string]string{"key": "a"},
+		},
+		{
+			scenario: "one byte over the max length",
+			value:    "ab",
+			expected: map[string]string{
+				"key":      "application/multipart-secret; pages=2",
+				"key-0001": "a",
+				"key-0002": "b",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(k),
+				secretstorage.WithMaxLength(1),
+			)
+
+			require.NoError(t, s.Set(t.Name(), "key", tc.value))
+
+			assert.Equal(t, tc.expected, k.entries(t.Name()))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.value, actual)
+		})
+	}
+}
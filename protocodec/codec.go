@@ -0,0 +1,87 @@
+// Package protocodec provides a codec that stores protobuf messages in the keyring.
+package protocodec
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+
+	"go.nhat.io/secretstorage"
+)
+
+// ErrNotMessage indicates that the value is not a protobuf message.
+var ErrNotMessage = errors.New("not a protobuf message")
+
+var _ secretstorage.Codec = Codec{}
+
+// Codec encodes the protobuf messages with proto.Marshal. The binary data is encoded with base64, because some
+// keyrings only store text.
+type Codec struct{}
+
+// Name returns "proto".
+func (Codec) Name() string {
+	return "proto"
+}
+
+// Marshal marshals the message.
+func (Codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotMessage, v)
+	}
+
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// Unmarshal unmarshals the data into the destination, which is either a message, or a pointer to a message. In the
+// latter case, the message is allocated.
+func (Codec) Unmarshal(data []byte, dest any) error {
+	m, err := message(dest)
+	if err != nil {
+		return err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	return proto.Unmarshal(b, m) //nolint: wrapcheck
+}
+
+// message returns the message to unmarshal into.
+func message(dest any) (proto.Message, error) {
+	rv := reflect.ValueOf(dest)
+
+	// The destination is a pointer to a message pointer, for example a *KeyringStorage[*pb.Message].Get.
+	if rv.Kind() == reflect.Pointer && rv.Elem().Kind() == reflect.Pointer {
+		if m, ok := rv.Elem().Interface().(proto.Message); ok {
+			if rv.Elem().IsNil() {
+				rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+
+				m = rv.Elem().Interface().(proto.Message) //nolint: forcetypeassert
+			}
+
+			return m, nil
+		}
+	}
+
+	if m, ok := dest.(proto.Message); ok {
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrNotMessage, dest)
+}
+
+// WithProtoCodec makes the storage store the protobuf messages.
+func WithProtoCodec() secretstorage.KeyringStorageOption {
+	return secretstorage.WithCodec(Codec{})
+}
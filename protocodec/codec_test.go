@@ -0,0 +1,170 @@
+package protocodec_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/protocodec"
+)
+
+func TestWithProtoCodec_Multipart(t *testing.T) {
+	t.Parallel()
+
+	k := newMapKeyring()
+	s := secretstorage.NewKeyringStorage[*structpb.Struct](
+		secretstorage.WithKeyring(k),
+		protocodec.WithProtoCodec(),
+	)
+
+	expected, err := structpb.NewStruct(map[string]any{
+		"username": "john",
+		"password": strings.Repeat("secret", 1000),
+		"scopes":   []any{"read", "write"},
+	})
+	require.NoError(t, err)
+
+	err = s.Set(t.Name(), "key", expected)
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/multipart-secret; pages=4", k.data[t.Name()+":key"])
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(expected, actual))
+}
+
+func TestWithProtoCodec_Binary(t *testing.T) {
+	t.Parallel()
+
+	k := newMapKeyring()
+	s := secretstorage.NewKeyringStorage[*wrapperspb.BytesValue](
+		secretstorage.WithKeyring(k),
+		protocodec.WithProtoCodec(),
+	)
+
+	expected := wrapperspb.Bytes([]byte{0x00, 0xff, '\n', 0x80})
+
+	err := s.Set(t.Name(), "key", expected)
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/encoded-secret; codec=proto\nCgQA/wqA", k.data[t.Name()+":key"])
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(expected, actual))
+}
+
+func TestCodec_Marshal_NotMessage(t *testing.T) {
+	t.Parallel()
+
+	actual, err := protocodec.Codec{}.Marshal("value")
+
+	require.EqualError(t, err, "not a protobuf message: string")
+	assert.Nil(t, actual)
+}
+
+func TestCodec_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	c := protocodec.Codec{}
+
+	data, err := c.Marshal(wrapperspb.String("value"))
+	require.NoError(t, err)
+
+	var ptr *wrapperspb.StringValue
+
+	err = c.Unmarshal(data, &ptr)
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", ptr.GetValue())
+
+	msg := &wrapperspb.StringValue{}
+
+	err = c.Unmarshal(data, msg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", msg.GetValue())
+}
+
+func TestCodec_Unmarshal_Failure(t *testing.T) {
+	t.Parallel()
+
+	c := protocodec.Codec{}
+
+	var s string
+
+	err := c.Unmarshal([]byte("CgV2YWx1ZQ=="), &s)
+	require.EqualError(t, err, "not a protobuf message: *string")
+
+	var msg *wrapperspb.StringValue
+
+	err = c.Unmarshal([]byte("not base64"), &msg)
+	require.EqualError(t, err, "failed to decode data: illegal base64 data at input byte 3")
+}
+
+type mapKeyring struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (k *mapKeyring) Set(service, user, password string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.data[service+":"+user] = password
+
+	return nil
+}
+
+func (k *mapKeyring) Get(service, user string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	v, ok := k.data[service+":"+user]
+	if !ok {
+		return "", keyring.ErrNotFound
+	}
+
+	return v, nil
+}
+
+func (k *mapKeyring) Delete(service, user string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.data[service+":"+user]; !ok {
+		return keyring.ErrNotFound
+	}
+
+	delete(k.data, service+":"+user)
+
+	return nil
+}
+
+func (k *mapKeyring) DeleteAll(service string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for key := range k.data {
+		if strings.HasPrefix(key, service+":") {
+			delete(k.data, key)
+		}
+	}
+
+	return nil
+}
+
+func newMapKeyring() *mapKeyring {
+	return &mapKeyring{data: make(map[string]string)}
+}
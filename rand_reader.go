@@ -0,0 +1,36 @@
+package secretstorage
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+func (ss *KeyringStorage[V]) withRandReader(r io.Reader) {
+	ss.randReader = r
+}
+
+// WithRandReader sets the source of the randomness of the storage, such as the nonces of WithEncryption, so that the
+// tests get a reproducible output with a fixed reader. The source is crypto/rand.Reader by default, or if the reader is
+// nil.
+//
+// It is meant for the tests only: a predictable source makes the encryption insecure, it must never be set in
+// production.
+func WithRandReader(r io.Reader) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withRandReader(r)
+	})
+}
+
+// rand returns the source of randomness of the storage.
+func (ss *KeyringStorage[V]) rand() io.Reader {
+	return randReader(ss.randReader)
+}
+
+// randReader returns the reader, or crypto/rand.Reader if it is nil.
+func randReader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+
+	return r
+}
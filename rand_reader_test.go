@@ -0,0 +1,156 @@
+package secretstorage_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_RandReader_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	seed := bytes.Repeat([]byte{0x42}, 1024)
+
+	newStorage := func(k *memoryKeyring) *secretstorage.KeyringStorage[string] {
+		return secretstorage.NewKeyringStorage[string](
+			secretstorage.WithKeyring(k),
+			secretstorage.WithEncryption(encryptionKey),
+			secretstorage.WithRandReader(bytes.NewReader(seed)),
+		)
+	}
+
+	k1 := newMemoryKeyring()
+	k2 := newMemoryKeyring()
+
+	require.NoError(t, newStorage(k1).Set(t.Name(), "key", "p4ssw0rd"))
+	require.NoError(t, newStorage(k2).Set(t.Name(), "key", "p4ssw0rd"))
+
+	assert.Equal(t, k1.entries(t.Name()), k2.entries(t.Name()))
+
+	actual, err := newStorage(k1).Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestEncryptedStorage_RandReader_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	seed := bytes.Repeat([]byte{0x42}, 1024)
+
+	newStorage := func(k *memoryKeyring) *secretstorage.EncryptedStorage[string] {
+		inner := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+		return secretstorage.NewEncryptedStorage[string](inner, []byte("passphrase"),
+			secretstorage.WithKDF[string](secretstorage.Argon2KDF{}),
+			secretstorage.WithEncryptedRandReader[string](bytes.NewReader(seed)),
+		)
+	}
+
+	k1 := newMemoryKeyring()
+	k2 := newMemoryKeyring()
+
+	require.NoError(t, newStorage(k1).Set(t.Name(), "key", "p4ssw0rd"))
+	require.NoError(t, newStorage(k2).Set(t.Name(), "key", "p4ssw0rd"))
+
+	assert.Equal(t, k1.entries(t.Name()), k2.entries(t.Name()))
+
+	actual, err := newStorage(k1).Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestEncryptedKeyring_RandReader_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	seed := bytes.Repeat([]byte{0x42}, 1024)
+
+	k1 := newMemoryKeyring()
+	k2 := newMemoryKeyring()
+
+	for _, k := range []*memoryKeyring{k1, k2} {
+		ek, err := secretstorage.NewEncryptedKeyring(k, encryptionKey, secretstorage.WithEncryptedKeyringRandReader(bytes.NewReader(seed)))
+		require.NoError(t, err)
+
+		require.NoError(t, ek.Set(t.Name(), "key", "p4ssw0rd"))
+	}
+
+	assert.Equal(t, k1.entries(t.Name()), k2.entries(t.Name()))
+}
+
+func TestFileKeyring_RandReader_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	seed := bytes.Repeat([]byte{0x42}, 1024)
+	files := make([][]byte, 2)
+
+	for i := range files {
+		path := filepath.Join(t.TempDir(), "secrets")
+
+		k, err := secretstorage.NewFileKeyring(path, encryptionKey, secretstorage.WithFileKeyringRandReader(bytes.NewReader(seed)))
+		require.NoError(t, err)
+
+		require.NoError(t, k.Set(t.Name(), "key", "p4ssw0rd"))
+
+		files[i], err = os.ReadFile(path) //nolint: gosec
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, files[0], files[1])
+}
+
+func TestArgon2Hasher_RandReader_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	seed := bytes.Repeat([]byte{0x42}, 1024)
+
+	h1, err := secretstorage.Argon2Hasher{Rand: bytes.NewReader(seed)}.Hash("p4ssw0rd")
+	require.NoError(t, err)
+
+	h2, err := secretstorage.Argon2Hasher{Rand: bytes.NewReader(seed)}.Hash("p4ssw0rd")
+	require.NoError(t, err)
+
+	assert.Equal(t, h1, h2)
+}
+
+func TestKeyringStorage_RandReader_Default(t *testing.T) {
+	t.Parallel()
+
+	k1 := newMemoryKeyring()
+	k2 := newMemoryKeyring()
+
+	s1 := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k1), secretstorage.WithEncryption(encryptionKey))
+	s2 := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k2), secretstorage.WithEncryption(encryptionKey),
+		secretstorage.WithRandReader(nil),
+	)
+
+	require.NoError(t, s1.Set(t.Name(), "key", "p4ssw0rd"))
+	require.NoError(t, s2.Set(t.Name(), "key", "p4ssw0rd"))
+
+	assert.NotEqual(t, k1.entries(t.Name()), k2.entries(t.Name()))
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestKeyringStorage_RandReader_Error(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithEncryption(encryptionKey),
+		secretstorage.WithRandReader(failingReader{}),
+	)
+
+	err := s.Set(t.Name(), "key", "p4ssw0rd")
+	require.EqualError(t, err, "failed to marshal data for writing to keyring: failed to generate nonce: no entropy")
+}
@@ -0,0 +1,292 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// SetReader drains the reader and stores its content for the given key, as is, without marshaling. For a
+// KeyringStorage[[]byte], this is equivalent to Set with the content of the reader. The content that starts like one
+// of the headers of the storage is escaped, and the content is compressed and encrypted if configured, like Set does.
+func (ss *KeyringStorage[V]) SetReader(service string, key string, r io.Reader) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	d, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read data for writing to keyring: %w", err)
+	}
+
+	e, err := ss.encodeBytes(service, key, []byte(escapeLiteral(string(d))), textCodecName)
+	if err != nil {
+		return err
+	}
+
+	return ss.setRaw(service, key, e, nil)
+}
+
+// GetReader returns a reader over the content stored for the given key, without unmarshaling. For a
+// KeyringStorage[[]byte], the content is the value itself.
+//
+// The pages of a multipart data are read lazily, when the reader reaches them, with the keyring and the page format
+// that are configured when GetReader is called, see Reconfigure. The key stays locked for reading until the reader is
+// closed, so the caller must always close it. The service is not locked, the pages that DeleteAll deletes meanwhile
+// fail the reader. The references are followed, see SetRef. The data that is compressed or encrypted is decoded as a
+// whole before the reader is returned, see WithCompression and WithEncryption.
+func (ss *KeyringStorage[V]) GetReader(service string, key string) (io.ReadCloser, error) {
+	unlockConfig := ss.rlockConfig()
+	service = ss.serviceOrDefault(service)
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+
+	unlock := func() {
+		mu.RUnlock()
+		unlockConfig()
+	}
+
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		err = ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+
+		unlock()
+
+		return nil, err
+	}
+
+	// The reference is followed like Get does, the target is not locked, see SetRef.
+	if isReference(d) {
+		if d, service, key, err = resolveReference(ss.keyring, service, key, d); err != nil {
+			unlock()
+
+			return nil, ss.notFound(err)
+		}
+	}
+
+	if isDeleted(d) {
+		unlock()
+
+		return nil, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", ErrNotFound))
+	}
+
+	if err := ss.checkDataExpiry(d); err != nil {
+		unlock()
+
+		return nil, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	// The sealed data is read as a whole, it can not be decrypted page by page, see WithEncryption.
+	if ss.sealed() {
+		defer unlock()
+
+		return ss.readSealed(service, key, d)
+	}
+
+	if isLabeled(d) {
+		d, err = decodeLabeled(ss.keyring, ss.pageFormat, ss.readsPerCall(), service, key, d)
+
+		unlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(strings.NewReader(unescapeLiteral(unescape(d)))), nil
+	}
+
+	if !isMultipart(d) {
+		unlock()
+
+		return io.NopCloser(strings.NewReader(unescapeLiteral(unescape(d)))), nil
+	}
+
+	h, err := parseMultipartHeader(d)
+	if err == nil {
+		err = h.checkPages(ss.pageFormat)
+	}
+
+	if err != nil {
+		unlock()
+
+		return nil, err
+	}
+
+	// The configuration is not locked while the pages are read, so that Reconfigure does not wait for the reader.
+	k, format := ss.keyring, ss.pageFormat
+
+	unlockConfig()
+
+	return &unescapingReader{ReadCloser: &pageReader{
+		pages: h.pages,
+		check: h.checkLength,
+		read: func(page int) (string, error) {
+			return h.readPage(k, format, service, key, page)
+		},
+		close: mu.detach(),
+	}}, nil
+}
+
+// readSealed decodes the data of the key, with its pages, and returns a reader over its content.
+func (ss *KeyringStorage[V]) readSealed(service string, key string, d string) (io.ReadCloser, error) {
+	if decode, ok := storedFormats.lookup(d); ok {
+		var err error
+
+		if d, err = decode(ss.keyring, ss.pageFormat, ss.readsPerCall(), service, key, d); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := ss.decodeBytes(service, key, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data read from keyring: %w", err)
+	}
+
+	return io.NopCloser(strings.NewReader(unescapeLiteral(d))), nil
+}
+
+// unescapingReader skips the header of the escaped data on the first read, and unescapes the data that reads like a
+// sentinel, see escapeLiteral.
+type unescapingReader struct {
+	io.ReadCloser
+
+	checked bool
+	buf     []byte
+	err     error
+}
+
+func (r *unescapingReader) Read(p []byte) (int, error) {
+	if !r.checked {
+		r.check()
+	}
+
+	if len(r.buf) > 0 {
+		n := copy(p, r.buf)
+		r.buf = r.buf[n:]
+
+		return n, nil
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return r.ReadCloser.Read(p) //nolint: wrapcheck
+}
+
+// check reads the data as long as it matches the header, and discards it if it is the whole header. The data that
+// follows is read as long as it could be an escaped sentinel, and is unescaped if it is one.
+func (r *unescapingReader) check() {
+	r.checked = true
+
+	r.fill(len(escapedHeader), func(d string) bool {
+		return strings.HasPrefix(escapedHeader, d)
+	})
+
+	if string(r.buf) == escapedHeader {
+		r.buf = nil
+	}
+
+	r.fill(0, mayBeEscapedSentinel)
+
+	if errors.Is(r.err, io.EOF) {
+		r.buf = []byte(unescapeLiteral(string(r.buf)))
+	}
+}
+
+// fill reads the data into the buffer as long as the buffer matches, up to the length if it is not 0.
+func (r *unescapingReader) fill(length int, matches func(d string) bool) {
+	tmp := make([]byte, len(escapedHeader))
+
+	for r.err == nil && matches(string(r.buf)) {
+		size := len(tmp)
+		if length > 0 {
+			if size = length - len(r.buf); size <= 0 {
+				return
+			}
+		}
+
+		n, err := r.ReadCloser.Read(tmp[:size])
+		r.buf = append(r.buf, tmp[:n]...)
+
+		r.err = err
+	}
+}
+
+// mayBeEscapedSentinel tells whether the data is the beginning of a sentinel, escaped any number of times.
+func mayBeEscapedSentinel(d string) bool {
+	for {
+		e, ok := strings.CutPrefix(d, escapedHeader)
+		if !ok {
+			return strings.HasPrefix(escapedHeader, d) || strings.HasPrefix(mimeNilSecret, d) || strings.HasPrefix(mimeEmptySecret, d)
+		}
+
+		d = e
+	}
+}
+
+var _ io.ReadCloser = (*pageReader)(nil)
+
+// pageReader reads the pages of a multipart data one by one.
+type pageReader struct {
+	pages int
+	page  int
+	n     int
+	buf   strings.Reader
+	err   error
+	read  func(page int) (string, error)
+	check func(length int) error
+
+	close     func()
+	closeOnce sync.Once
+}
+
+func (r *pageReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if r.page >= r.pages {
+			if err := r.check(r.n); err != nil {
+				r.err = err
+
+				return 0, err
+			}
+
+			return 0, io.EOF
+		}
+
+		r.page++
+
+		d, err := r.read(r.page)
+		if err != nil {
+			r.err = fmt.Errorf("failed to read multipart data #%d from keyring: %w", r.page, err)
+
+			return 0, r.err
+		}
+
+		r.n += len(d)
+		r.buf.Reset(d)
+	}
+
+	return r.buf.Read(p) //nolint: wrapcheck
+}
+
+func (r *pageReader) Close() error {
+	r.closeOnce.Do(r.close)
+
+	return nil
+}
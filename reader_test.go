@@ -0,0 +1,270 @@
+package secretstorage_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_SetReader_Failure_CouldNotRead(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	err := s.SetReader(t.Name(), "key", iotest.ErrReader(assert.AnError))
+
+	require.EqualError(t, err, "failed to read data for writing to keyring: assert.AnError general error for testing")
+}
+
+func TestKeyringStorage_SetReader_GetReader_Success(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		length   int
+	}{
+		{
+			scenario: "single",
+			length:   128,
+		},
+		{
+			scenario: "multipart",
+			length:   6139,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			value := []byte(randString(tc.length))
+
+			s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(newMemoryKeyring()))
+
+			err := s.SetReader(t.Name(), "key", bytes.NewReader(value))
+			require.NoError(t, err)
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, value, actual)
+
+			r, err := s.GetReader(t.Name(), "key")
+			require.NoError(t, err)
+
+			read, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+
+			assert.Equal(t, value, read)
+		})
+	}
+}
+
+func TestKeyringStorage_SetReader_GetReader_Sealed(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+		value    string
+	}{
+		{
+			scenario: "encrypted",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithEncryption(encryptionKey)},
+			value:    "p4ssw0rd",
+		},
+		{
+			scenario: "encrypted multipart",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithEncryption(encryptionKey)},
+			value:    randString(6139),
+		},
+		{
+			scenario: "compressed",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCompression()},
+			value:    strings.Repeat("p4ssw0rd", 1000),
+		},
+		{
+			scenario: "compressed and encrypted",
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithCompression(),
+				secretstorage.WithEncryption(encryptionKey),
+			},
+			value: strings.Repeat("p4ssw0rd", 1000),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[[]byte](append(tc.options, secretstorage.WithKeyring(k))...)
+
+			// The content of the reader is sealed like the values.
+			require.NoError(t, s.SetReader(t.Name(), "reader", strings.NewReader(tc.value)))
+
+			for _, d := range k.entries(t.Name()) {
+				assert.NotContains(t, d, "p4ssw0rd")
+			}
+
+			actual, err := s.Get(t.Name(), "reader")
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tc.value), actual)
+
+			// The reader returns the content of the values, not the sealed data.
+			require.NoError(t, s.Set(t.Name(), "value", []byte(tc.value)))
+
+			for _, key := range []string{"reader", "value"} {
+				r, err := s.GetReader(t.Name(), key)
+				require.NoError(t, err)
+
+				read, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.NoError(t, r.Close())
+
+				assert.Equal(t, tc.value, string(read))
+			}
+		})
+	}
+}
+
+func TestKeyringStorage_GetReader_SecretNotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	r, err := s.GetReader(t.Name(), "key")
+
+	require.EqualError(t, err, "failed to read data from keyring: secret not found in keyring")
+	assert.Nil(t, r)
+}
+
+func TestKeyringStorage_GetReader_Failure_MultipartWrongPages(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=1"))
+
+	s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(k))
+
+	r, err := s.GetReader(t.Name(), "key")
+
+	require.EqualError(t, err, "invalid secret pages: 1")
+	assert.Nil(t, r)
+}
+
+func TestKeyringStorage_GetReader_Lazy(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").Once().
+			Return("application/multipart-secret; pages=3", nil)
+
+		k.On("Get", t.Name(), formatPage("key", 1)).Once().
+			Return("13", nil)
+
+		k.On("Get", t.Name(), formatPage("key", 2)).Once().
+			Return("", assert.AnError)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(k))
+
+	r, err := s.GetReader(t.Name(), "key")
+	require.NoError(t, err)
+
+	buf := make([]byte, 2)
+
+	n, err := r.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "13", string(buf[:n]))
+
+	_, err = r.Read(buf)
+	require.EqualError(t, err, "failed to read multipart data #2 from keyring: assert.AnError general error for testing")
+
+	_, err = r.Read(buf)
+	require.EqualError(t, err, "failed to read multipart data #2 from keyring: assert.AnError general error for testing")
+
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+}
+
+func TestKeyringStorage_GetReader_LocksUntilClosed(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "key", []byte(randString(6139))))
+
+	r, err := s.GetReader(t.Name(), "key")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		assert.NoError(t, s.Delete(t.Name(), "key"))
+	}()
+
+	read, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Len(t, read, 6139)
+
+	require.NoError(t, r.Close())
+
+	<-done
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_GetReader_DoesNotBlockReconfigureAndDeleteAll(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[[]byte](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "key", []byte(randString(6139))))
+
+	r, err := s.GetReader(t.Name(), "key")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = r.Close() //nolint: errcheck
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		assert.NoError(t, s.Reconfigure(secretstorage.WithMaxLength(2048)))
+		assert.NoError(t, s.DeleteAll(t.Name()))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the open reader blocks Reconfigure or DeleteAll")
+	}
+
+	assert.Empty(t, k.entries(t.Name()))
+
+	// The pages are gone, the reader fails.
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
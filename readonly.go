@@ -0,0 +1,14 @@
+package secretstorage
+
+import "errors"
+
+// ErrReadOnly indicates that the storage is read-only and does not write to the keyring.
+var ErrReadOnly = errors.New("storage is read-only")
+
+// WithReadOnly makes the storage read-only. All the operations that write to the keyring, such as Set and Delete,
+// return ErrReadOnly without calling the keyring.
+func WithReadOnly() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withReadOnly()
+	})
+}
@@ -0,0 +1,112 @@
+package secretstorage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_ReadOnly_Get(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("value", nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithReadOnly(),
+	)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_ReadOnly_Writes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		write    func(s *secretstorage.KeyringStorage[string]) error
+	}{
+		{
+			scenario: "set",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.Set("service", "key", "value")
+			},
+		},
+		{
+			scenario: "delete",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.Delete("service", "key")
+			},
+		},
+		{
+			scenario: "delete known",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.DeleteKnown("service", "key", 0)
+			},
+		},
+		{
+			scenario: "set reader",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.SetReader("service", "key", strings.NewReader("value"))
+			},
+		},
+		{
+			scenario: "copy service",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.CopyService("service", "another service")
+			},
+		},
+		{
+			scenario: "import key",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.ImportKey("service", "key", nil)
+			},
+		},
+		{
+			scenario: "delete page",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.DeletePage("service", "key", 1)
+			},
+		},
+		{
+			scenario: "delete all",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.DeleteAll("service")
+			},
+		},
+		{
+			scenario: "set ref",
+			write: func(s *secretstorage.KeyringStorage[string]) error {
+				return s.SetRef("service", "key", "another service", "key")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(mock.NopKeyring(t)),
+				secretstorage.WithReadOnly(),
+			)
+
+			err := tc.write(s)
+
+			require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+		})
+	}
+}
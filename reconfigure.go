@@ -0,0 +1,115 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidOption indicates that an option is invalid, or conflicts with another option.
+var ErrInvalidOption = errors.New("invalid option")
+
+// rlockConfig locks the configuration for reading, so that Reconfigure waits for the operation to finish. The public
+// methods lock it once, and must not call each other while it is locked.
+func (ss *KeyringStorage[V]) rlockConfig() func() {
+	ss.configMu.RLock()
+
+	return ss.configMu.RUnlock
+}
+
+// Reconfigure applies the options on top of the current configuration of the storage, without losing its state, such
+// as the locks of the keys. The options are validated first, the configuration is left unchanged if one of them is
+// invalid or conflicts with another.
+//
+// Reconfigure waits for the ongoing operations, including the readers returned by GetReader that are not closed yet,
+// so that every operation sees either the old or the new configuration.
+func (ss *KeyringStorage[V]) Reconfigure(opts ...KeyringStorageOption) error {
+	ss.configMu.Lock()
+	defer ss.configMu.Unlock()
+
+	c := *ss
+	c.keyring = unwrapKeyring(ss.keyring)
+	c.legacyCodecs = append([]Codec(nil), ss.legacyCodecs...)
+	c.decryptionKeys = append([][]byte(nil), ss.decryptionKeys...)
+
+	for _, opt := range opts {
+		opt.applyKeyringStorageOption(&c)
+	}
+
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	if err := c.checkCodec(); err != nil {
+		return err
+	}
+
+	c.guardKeyring()
+
+	ss.keyringStorageConfig = c.keyringStorageConfig
+
+	return nil
+}
+
+// validate checks that the configuration is consistent. The conflict of the codec with the type of the values is checked
+// apart, see WithForceCodec.
+func (ss *KeyringStorage[V]) validate() error {
+	switch {
+	case ss.keyring == nil:
+		return fmt.Errorf("%w: keyring is nil", ErrInvalidOption)
+
+	case ss.codec == nil:
+		return fmt.Errorf("%w: codec is nil", ErrInvalidOption)
+
+	case ss.maxPages < 0:
+		return fmt.Errorf("%w: max pages is negative: %d", ErrInvalidOption, ss.maxPages)
+
+	case ss.maxPages > 0 && ss.maxPages < minPages:
+		return fmt.Errorf("%w: max pages is less than %d: %d", ErrInvalidOption, minPages, ss.maxPages)
+
+	case ss.maxConcurrency < 0:
+		return fmt.Errorf("%w: max concurrency is negative: %d", ErrInvalidOption, ss.maxConcurrency)
+
+	case ss.timeouts.negative():
+		return fmt.Errorf("%w: timeout is negative", ErrInvalidOption)
+
+	case ss.pagesPerCall < 0:
+		return fmt.Errorf("%w: pages per call is negative: %d", ErrInvalidOption, ss.pagesPerCall)
+
+	case ss.parallelReads < 0:
+		return fmt.Errorf("%w: parallel reads is negative: %d", ErrInvalidOption, ss.parallelReads)
+
+	case ss.entryBudget < 0:
+		return fmt.Errorf("%w: entry budget is negative: %d", ErrInvalidOption, ss.entryBudget)
+
+	case ss.noPreDelete && ss.atomicSwap:
+		return fmt.Errorf("%w: no pre-delete can not be used with atomic swap", ErrInvalidOption)
+	}
+
+	if err := ss.pageFormat.validate(ss.maxPages); err != nil {
+		return err
+	}
+
+	if ss.encryptionKey != nil {
+		if _, err := newAEAD(ss.encryptionKey); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidOption, err)
+		}
+	}
+
+	for _, key := range ss.decryptionKeys {
+		if _, err := newAEAD(key); err != nil {
+			return fmt.Errorf("%w: decryption key: %w", ErrInvalidOption, err)
+		}
+	}
+
+	for _, c := range ss.legacyCodecs {
+		if c == nil {
+			return fmt.Errorf("%w: legacy codec is nil", ErrInvalidOption)
+		}
+
+		if c.Name() == ss.codec.Name() {
+			return fmt.Errorf("%w: legacy codec %q has the same name as the codec", ErrInvalidOption, c.Name())
+		}
+	}
+
+	return nil
+}
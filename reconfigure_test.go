@@ -0,0 +1,275 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Reconfigure_MaxLength(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	assert.Equal(t, map[string]string{"key": "hello world"}, k.entries(t.Name()))
+
+	require.NoError(t, s.Reconfigure(secretstorage.WithMaxLength(5)))
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+	expected := map[string]string{
+		"key":      "application/multipart-secret; pages=3",
+		"key-0001": "hello",
+		"key-0002": " worl",
+		"key-0003": "d",
+	}
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello world", actual)
+}
+
+func TestKeyringStorage_Reconfigure_KeepsCurrentConfiguration(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCodec(secretstorage.JSONCodec{}),
+		secretstorage.WithForceCodec(),
+	)
+
+	require.NoError(t, s.Reconfigure(secretstorage.WithReadOnly()))
+
+	err := s.Set(t.Name(), "key", "value")
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/encoded-secret; codec=json\n\"value\""))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_Reconfigure_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		options       []secretstorage.KeyringStorageOption
+		expectedError string
+	}{
+		{
+			scenario:      "nil keyring",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithKeyring(nil)},
+			expectedError: "invalid option: keyring is nil",
+		},
+		{
+			scenario:      "nil codec",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithCodec(nil)},
+			expectedError: "invalid option: codec is nil",
+		},
+		{
+			scenario:      "negative max pages",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithMaxPages(-1)},
+			expectedError: "invalid option: max pages is negative: -1",
+		},
+		{
+			scenario:      "single max page",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithMaxPages(1)},
+			expectedError: "invalid option: max pages is less than 2: 1",
+		},
+		{
+			scenario:      "negative max concurrency",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithMaxConcurrency(-1)},
+			expectedError: "invalid option: max concurrency is negative: -1",
+		},
+		{
+			scenario:      "negative pages per call",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithPagesPerCall(-1)},
+			expectedError: "invalid option: pages per call is negative: -1",
+		},
+		{
+			scenario:      "negative parallel reads",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithParallelReads(-1)},
+			expectedError: "invalid option: parallel reads is negative: -1",
+		},
+		{
+			scenario:      "no pre-delete with atomic swap",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithNoPreDelete(), secretstorage.WithAtomicSwap()},
+			expectedError: "invalid option: no pre-delete can not be used with atomic swap",
+		},
+		{
+			scenario:      "negative timeout",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithGetTimeout(-time.Second)},
+			expectedError: "invalid option: timeout is negative",
+		},
+		{
+			scenario:      "negative entry budget",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithEntryBudget(-1)},
+			expectedError: "invalid option: entry budget is negative: -1",
+		},
+		{
+			scenario:      "empty page separator",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithPageFormat("", 4)},
+			expectedError: "invalid option: page separator is empty",
+		},
+		{
+			scenario:      "zero page width",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithPageFormat("-", 0)},
+			expectedError: "invalid option: page width is less than 1: 0",
+		},
+		{
+			scenario: "page width does not accommodate max pages",
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithPageFormat("-", 1),
+				secretstorage.WithMaxPages(10),
+			},
+			expectedError: "invalid option: page width 1 does not accommodate 10 pages",
+		},
+		{
+			scenario: "legacy codec conflicts with codec",
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithCodec(secretstorage.JSONCodec{}),
+				secretstorage.WithLegacyCodecs(secretstorage.JSONCodec{}),
+			},
+			expectedError: `invalid option: legacy codec "json" has the same name as the codec`,
+		},
+		{
+			scenario:      "json codec on strings",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithCodec(secretstorage.JSONCodec{})},
+			expectedError: `invalid option: codec "json" quotes the string values, use WithForceCodec to keep it`,
+		},
+		{
+			scenario:      "invalid encryption key",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithEncryption([]byte("key"))},
+			expectedError: "invalid option: failed to create cipher: crypto/aes: invalid key size 3",
+		},
+		{
+			scenario:      "invalid decryption key",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithDecryptionKeys([]byte("key"))},
+			expectedError: "invalid option: decryption key: failed to create cipher: crypto/aes: invalid key size 3",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			err := s.Reconfigure(append([]secretstorage.KeyringStorageOption{secretstorage.WithMaxLength(5)}, tc.options...)...)
+
+			require.EqualError(t, err, tc.expectedError)
+			require.ErrorIs(t, err, secretstorage.ErrInvalidOption)
+
+			// The configuration is unchanged.
+			require.NoError(t, s.Set(t.Name(), "key", "hello world"))
+
+			assert.Equal(t, map[string]string{"key": "hello world"}, k.entries(t.Name()))
+		})
+	}
+}
+
+func TestNewKeyringStorage_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		options       []secretstorage.KeyringStorageOption
+		expectedError string
+	}{
+		{
+			scenario:      "nil keyring",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithKeyring(nil)},
+			expectedError: "invalid option: keyring is nil",
+		},
+		{
+			scenario:      "single max page",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithMaxPages(1)},
+			expectedError: "invalid option: max pages is less than 2: 1",
+		},
+		{
+			scenario:      "no pre-delete with atomic swap",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithNoPreDelete(), secretstorage.WithAtomicSwap()},
+			expectedError: "invalid option: no pre-delete can not be used with atomic swap",
+		},
+		{
+			scenario:      "empty page separator",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithPageFormat("", 4)},
+			expectedError: "invalid option: page separator is empty",
+		},
+		{
+			scenario:      "invalid encryption key",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithEncryption([]byte("key"))},
+			expectedError: "invalid option: failed to create cipher: crypto/aes: invalid key size 3",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.PanicsWithError(t, tc.expectedError, func() {
+				secretstorage.NewKeyringStorage[string](append([]secretstorage.KeyringStorageOption{secretstorage.WithKeyring(newMemoryKeyring())}, tc.options...)...)
+			})
+		})
+	}
+
+	// The codec that conflicts with the type of the values fails the writes instead.
+	assert.NotPanics(t, func() {
+		secretstorage.NewKeyringStorage[string](secretstorage.WithCodec(secretstorage.JSONCodec{}))
+	})
+}
+
+func TestKeyringStorage_Reconfigure_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+			value := randString(100)
+
+			assert.NoError(t, s.Set(t.Name(), key, value))
+
+			actual, err := s.Get(t.Name(), key)
+
+			assert.NoError(t, err)
+			assert.Equal(t, value, actual)
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, s.Reconfigure(secretstorage.WithMaxLength(10+i)))
+		}(i)
+	}
+
+	wg.Wait()
+}
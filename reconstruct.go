@@ -0,0 +1,79 @@
+package secretstorage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetOrReconstruct gets the value like Get, but when some pages of a multipart value could not be read, or the
+// reassembled data does not have the stored length, the pages that could be read are passed to the reconstruct
+// function, by page number. The data that it returns is then unmarshaled as if it was read from the keyring.
+//
+// GetOrReconstruct is meant for disaster recovery, for example when the data is erasure-coded, or when the caller is
+// able to fill a gap. The values that are not multipart, or that are complete, are returned as is.
+func (ss *KeyringStorage[V]) GetOrReconstruct(
+	service string,
+	key string,
+	reconstruct func(available map[int]string) (string, error),
+) (V, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var result V
+
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		return result, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	if !isMultipart(d) {
+		v, _, err := ss.get(service, key)
+
+		return v, ss.notFound(err)
+	}
+
+	if err := ss.checkDataExpiry(d); err != nil {
+		return result, ss.notFound(fmt.Errorf("failed to read data from keyring: %w", err))
+	}
+
+	h, err := parseMultipartHeader(d)
+	if err == nil {
+		err = h.checkPages(ss.pageFormat)
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	var sb strings.Builder
+
+	available := make(map[int]string, h.pages)
+
+	for i := 1; i <= h.pages; i++ {
+		if p, err := h.readPage(ss.keyring, ss.pageFormat, service, key, i); err == nil {
+			available[i] = p
+
+			sb.WriteString(p)
+		}
+	}
+
+	d = sb.String()
+
+	if len(available) < h.pages || h.checkLength(len(d)) != nil {
+		if d, err = reconstruct(available); err != nil {
+			return result, fmt.Errorf("failed to reconstruct data: %w", err)
+		}
+	}
+
+	if err := ss.decode(service, key, d, &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal data read from keyring: %w", err)
+	}
+
+	return result, nil
+}
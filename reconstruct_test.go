@@ -0,0 +1,134 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_GetOrReconstruct(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario          string
+		entries           map[string]string
+		reconstructed     string
+		reconstructErr    error
+		expected          string
+		expectedAvailable map[int]string
+		expectedError     string
+	}{
+		{
+			scenario:      "not found",
+			expectedError: "failed to read data from keyring: secret not found in keyring",
+		},
+		{
+			scenario: "single entry",
+			entries:  map[string]string{"key": "hello"},
+			expected: "hello",
+		},
+		{
+			scenario: "complete multipart",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=2",
+				"key-0001": "hello ",
+				"key-0002": "world",
+			},
+			expected: "hello world",
+		},
+		{
+			scenario:      "invalid header",
+			entries:       map[string]string{"key": "application/multipart-secret; pages=1"},
+			expectedError: "invalid secret pages: 1",
+		},
+		{
+			scenario: "missing page",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=3",
+				"key-0001": "hello ",
+				"key-0003": "world",
+			},
+			reconstructed:     "hello big world",
+			expected:          "hello big world",
+			expectedAvailable: map[int]string{1: "hello ", 3: "world"},
+		},
+		{
+			scenario: "truncated page",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=2; length=11",
+				"key-0001": "hello ",
+				"key-0002": "wor",
+			},
+			reconstructed:     "hello world",
+			expected:          "hello world",
+			expectedAvailable: map[int]string{1: "hello ", 2: "wor"},
+		},
+		{
+			scenario: "reconstruction error",
+			entries: map[string]string{
+				"key":      "application/multipart-secret; pages=2",
+				"key-0001": "hello ",
+			},
+			reconstructErr:    assert.AnError,
+			expectedAvailable: map[int]string{1: "hello "},
+			expectedError:     "failed to reconstruct data: assert.AnError general error for testing",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+
+			for key, value := range tc.entries {
+				require.NoError(t, k.Set(t.Name(), key, value))
+			}
+
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			var available map[int]string
+
+			actual, err := s.GetOrReconstruct(t.Name(), "key", func(a map[int]string) (string, error) {
+				available = a
+
+				return tc.reconstructed, tc.reconstructErr
+			})
+
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.expectedAvailable, available)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestKeyringStorage_GetOrReconstruct_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+	require.NoError(t, k.Set(t.Name(), "key-0001", "4"))
+
+	s := secretstorage.NewKeyringStorage[custom](secretstorage.WithKeyring(k))
+
+	actual, err := s.GetOrReconstruct(t.Name(), "key", func(map[int]string) (string, error) {
+		return "42", nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, custom(42), actual)
+
+	_, err = s.Get(t.Name(), "key")
+	require.Error(t, err, "Get stays strict")
+}
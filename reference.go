@@ -0,0 +1,99 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrReferenceLoop indicates that a reference could not be resolved because the chain of references is too deep, which
+// is usually caused by a loop.
+var ErrReferenceLoop = errors.New("reference loop")
+
+const (
+	mimeSecretReference = "application/secret-reference"
+	maxReferenceDepth   = 8
+)
+
+// SetRef stores a reference to another key in place of a value. Get follows the reference transparently and returns
+// the value of the target, which may be another reference, up to a depth of 8. The chains that are deeper, such as the
+// loops, fail with ErrReferenceLoop.
+//
+// The target is not required to exist, and is not locked while the reference is followed, by Get or GetReader.
+func (ss *KeyringStorage[V]) SetRef(service string, key string, targetService string, targetKey string) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+	targetService = ss.serviceOrDefault(targetService)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	d := mime.FormatMediaType(mimeSecretReference, map[string]string{"service": targetService, "key": targetKey})
+	if len(d) > ss.maxLength {
+		return fmt.Errorf("reference is too long: %d, the max length is %d", len(d), ss.maxLength) //nolint: goerr113
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return ss.setRaw(service, key, d, nil)
+}
+
+// decodeReference follows the references until it finds a data that is not a reference, and decodes it if it is
+// in one of the stored formats. A reference to a deleted value is not found, see WithSoftDelete.
+func decodeReference(k keyring.Keyring, f pageFormat, reads int, service string, key string, d string) (string, error) {
+	d, service, key, err := resolveReference(k, service, key, d)
+	if err != nil {
+		return "", err
+	}
+
+	if decode, ok := targetFormats.lookup(d); ok {
+		return decode(k, f, reads, service, key, d)
+	}
+
+	return d, nil
+}
+
+// resolveReference follows the references until it finds a data that is not a reference, and returns it with the
+// service and the key that it is read from.
+func resolveReference(k keyring.Keyring, service string, key string, d string) (string, string, string, error) {
+	for depth := 0; isReference(d); depth++ {
+		if depth >= maxReferenceDepth {
+			return "", "", "", fmt.Errorf("%w: more than %d references are followed", ErrReferenceLoop, maxReferenceDepth)
+		}
+
+		_, params, err := mime.ParseMediaType(d)
+		if err != nil {
+			return "", "", "", &headerError{field: "params", err: err}
+		}
+
+		targetService, ok := params["service"]
+		if !ok {
+			return "", "", "", &headerError{field: "service", err: errors.New("missing parameter")} //nolint: goerr113
+		}
+
+		targetKey, ok := params["key"]
+		if !ok {
+			return "", "", "", &headerError{field: "key", err: errors.New("missing parameter")} //nolint: goerr113
+		}
+
+		if d, err = k.Get(targetService, targetKey); err != nil {
+			return "", "", "", fmt.Errorf("failed to read referenced data %q/%q from keyring: %w", targetService, targetKey, err)
+		}
+
+		service, key = targetService, targetKey
+	}
+
+	return d, service, key, nil
+}
+
+func isReference(d string) bool {
+	return strings.HasPrefix(d, mimeSecretReference)
+}
@@ -0,0 +1,129 @@
+package secretstorage_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_SetRef_Chain(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithMaxLength(100))
+
+	value := randString(300)
+
+	require.NoError(t, s.Set("shared", "credentials", value))
+	require.NoError(t, s.SetRef(t.Name(), "alias", "shared", "credentials"))
+	require.NoError(t, s.SetRef(t.Name(), "alias of alias", t.Name(), "alias"))
+
+	assert.Equal(t, `application/secret-reference; key=credentials; service=shared`, k.entries(t.Name())["alias"])
+
+	for _, key := range []string{"alias", "alias of alias"} {
+		actual, err := s.Get(t.Name(), key)
+		require.NoError(t, err)
+		assert.Equal(t, value, actual)
+	}
+
+	// Rotate in one place.
+	require.NoError(t, s.Set("shared", "credentials", "rotated"))
+
+	actual, err := s.Get(t.Name(), "alias of alias")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", actual)
+
+	// Deleting the reference keeps the target.
+	require.NoError(t, s.Delete(t.Name(), "alias"))
+
+	actual, err = s.Get("shared", "credentials")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", actual)
+
+	_, err = s.Get(t.Name(), "alias of alias")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	require.EqualError(t, err, `failed to read referenced data "TestKeyringStorage_SetRef_Chain"/"alias" from keyring: secret not found in keyring`)
+}
+
+func TestKeyringStorage_SetRef_Loop(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	require.NoError(t, s.SetRef(t.Name(), "a", t.Name(), "b"))
+	require.NoError(t, s.SetRef(t.Name(), "b", t.Name(), "a"))
+
+	_, err := s.Get(t.Name(), "a")
+
+	require.ErrorIs(t, err, secretstorage.ErrReferenceLoop)
+	require.EqualError(t, err, "reference loop: more than 8 references are followed")
+
+	// Overwriting a reference with a value breaks the loop.
+	require.NoError(t, s.Set(t.Name(), "b", "value"))
+
+	actual, err := s.Get(t.Name(), "a")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_SetRef_InvalidReference(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/secret-reference; service=service"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	_, err := s.Get(t.Name(), "key")
+
+	require.EqualError(t, err, "failed to get key from data: missing parameter")
+}
+
+func TestKeyringStorage_SetRef_GetReader(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		value    string
+	}{
+		{
+			scenario: "single entry",
+			value:    "secret",
+		},
+		{
+			scenario: "multipart",
+			value:    randString(300),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithMaxLength(100))
+
+			require.NoError(t, s.Set("shared", t.Name(), tc.value))
+			require.NoError(t, s.SetRef(t.Name(), "alias", "shared", t.Name()))
+
+			r, err := s.GetReader(t.Name(), "alias")
+			require.NoError(t, err)
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, tc.value, string(b))
+
+			require.NoError(t, s.Delete("shared", t.Name()))
+
+			_, err = s.GetReader(t.Name(), "alias")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package secretstorage
+
+import "fmt"
+
+// Reinitializer is an optional interface for keyrings that hold a connection or a handle to their backend, which could
+// become stale, for example when the keyring daemon restarts.
+type Reinitializer interface {
+	Reinit() error
+}
+
+// Reinit re-establishes the connection of the keyring to its backend, without recreating the storage. It is a no-op
+// if the keyring does not implement Reinitializer, like the OS keyring. Once the keyring is reinitialized, the circuit
+// breaker is closed, see WithCircuitBreaker.
+func (ss *KeyringStorage[V]) Reinit() error {
+	defer ss.rlockConfig()()
+
+	r, ok := unwrapKeyring(ss.keyring).(Reinitializer)
+	if !ok {
+		return nil
+	}
+
+	if err := r.Reinit(); err != nil {
+		return fmt.Errorf("failed to reinitialize keyring: %w", err)
+	}
+
+	if ss.circuitBreaker != nil {
+		ss.circuitBreaker.reset()
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package secretstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+// reinitKeyring implements both keyring.Keyring and secretstorage.Reinitializer.
+type reinitKeyring struct {
+	*mock.Keyring
+	*mock.Reinitializer
+}
+
+func TestKeyringStorage_Reinit_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	require.NoError(t, s.Reinit())
+}
+
+func TestKeyringStorage_Reinit_Failure(t *testing.T) {
+	t.Parallel()
+
+	r := mock.NewReinitializer(t)
+
+	r.On("Reinit").Return(assert.AnError).Once()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(&reinitKeyring{Keyring: mock.NopKeyring(t), Reinitializer: r}),
+	)
+
+	err := s.Reinit()
+
+	require.EqualError(t, err, "failed to reinitialize keyring: assert.AnError general error for testing")
+}
+
+func TestKeyringStorage_Reinit_ClosesCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("", assert.AnError).Once()
+
+		k.On("Get", t.Name(), "key").
+			Return("value", nil).Once()
+	})(t)
+
+	r := mock.NewReinitializer(t)
+
+	r.On("Reinit").Return(nil).Once()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(&reinitKeyring{Keyring: k, Reinitializer: r}),
+		secretstorage.WithCircuitBreaker(1, time.Hour),
+	)
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, assert.AnError)
+
+	_, err = s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+
+	require.NoError(t, s.Reinit())
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", actual)
+}
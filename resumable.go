@@ -0,0 +1,24 @@
+package secretstorage
+
+func (ss *KeyringStorage[V]) withResumableWrites() {
+	ss.resumableWrites = true
+}
+
+// WithResumableWrites keeps the pages that were written when the write of a multipart data fails, instead of deleting
+// them, so that the next write of the same data resumes from the failed page. Before writing a page, the storage reads
+// it, and skips the write if the page already has the exact same content.
+//
+// This costs one more call to the keyring per page. The pages of a write that is never retried are left behind, see
+// WithAggressiveDelete.
+func WithResumableWrites() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withResumableWrites()
+	})
+}
+
+// hasPage tells whether the page exists, with the exact same content.
+func (ss *KeyringStorage[V]) hasPage(service string, pageKey string, data string) bool {
+	p, err := ss.keyring.Get(service, pageKey)
+
+	return err == nil && p == data
+}
@@ -0,0 +1,106 @@
+package secretstorage_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+// flakyKeyring fails the first write of an entry, and records the writes.
+type flakyKeyring struct {
+	keyring.Keyring
+
+	failOnce string
+
+	mu     sync.Mutex
+	failed bool
+	writes []string
+}
+
+func (k *flakyKeyring) Set(service, user, password string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if user == k.failOnce && !k.failed {
+		k.failed = true
+
+		return assert.AnError
+	}
+
+	k.writes = append(k.writes, user)
+
+	return k.Keyring.Set(service, user, password)
+}
+
+func TestKeyringStorage_ResumableWrites(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+	k := &flakyKeyring{Keyring: m, failOnce: "key-0003"}
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithResumableWrites(),
+	)
+
+	err := s.Set(t.Name(), "key", "hello world!")
+	require.ErrorIs(t, err, assert.AnError)
+
+	// The written pages are kept.
+	assert.Equal(t, map[string]string{"key-0001": "hello", "key-0002": " worl"}, m.entries(t.Name()))
+
+	require.NoError(t, s.Set(t.Name(), "key", "hello world!"))
+
+	// The matching pages are not written again.
+	assert.Equal(t, []string{"key-0001", "key-0002", "key-0003", "key"}, k.writes)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world!", actual)
+}
+
+func TestKeyringStorage_ResumableWrites_ChangedPage(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+	k := &flakyKeyring{Keyring: m, failOnce: "key-0002"}
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+		secretstorage.WithResumableWrites(),
+	)
+
+	err := s.Set(t.Name(), "key", "hello world")
+	require.ErrorIs(t, err, assert.AnError)
+
+	// Another value, the first page does not match.
+	require.NoError(t, s.Set(t.Name(), "key", "howdy world"))
+
+	assert.Equal(t, []string{"key-0001", "key-0001", "key-0002", "key-0003", "key"}, k.writes)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "howdy world", actual)
+}
+
+func TestKeyringStorage_NotResumableWrites(t *testing.T) {
+	t.Parallel()
+
+	m := newMemoryKeyring()
+	k := &flakyKeyring{Keyring: m, failOnce: "key-0003"}
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(5),
+	)
+
+	err := s.Set(t.Name(), "key", "hello world!")
+	require.ErrorIs(t, err, assert.AnError)
+
+	// The written pages are deleted.
+	assert.Empty(t, m.entries(t.Name()))
+}
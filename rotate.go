@@ -0,0 +1,206 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"strings"
+	"time"
+)
+
+// ErrReservedKey indicates that the key is reserved by the storage, such as the keys of the previous slots, see Rotate.
+var ErrReservedKey = errors.New("reserved key")
+
+const (
+	mimePreviousSecret = "application/previous-secret"
+	previousKeySuffix  = "~previous"
+)
+
+// Rotate replaces the value of the key with the new one, like Set, and keeps the old value in a derived "previous" slot
+// until the grace period elapses, see GetPrevious, so that the clients that still use the old value keep working
+// during the rotation. The labels of the old value are kept. If the key does not exist, or the grace period is not
+// positive, the new value is set without a previous value.
+//
+// The previous value expires according to the clock, see WithClock. Its slot is overwritten by the next rotation, and
+// is deleted with the key by Delete and Purge, or once it has expired and is read. The slot is stored under the key with
+// the "~previous" suffix, such keys are reserved: they are not listed, and the writes to them fail with ErrReservedKey.
+func (ss *KeyringStorage[V]) Rotate(service string, key string, newValue V, grace time.Duration) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	d, err := ss.encode(service, key, newValue)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data for writing to keyring: %w", tagError(ErrMarshal, err))
+	}
+
+	// The lock of the key also guards its previous slot.
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	prevKey := previousKey(key)
+
+	old, params, targetService, targetKey, err := ss.getRawTarget(service, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to read old data from keyring: %w", err)
+	}
+
+	// The old value of a reference is bound to its target, it is encoded again for the key, see WithEncryption.
+	if err == nil && grace > 0 && (targetService != service || targetKey != key) {
+		if old, err = ss.rebind(targetService, targetKey, service, key, old); err != nil {
+			return fmt.Errorf("failed to read old data from keyring: %w", err)
+		}
+	}
+
+	if err == nil && grace > 0 {
+		expires := ss.clock.Now().Add(grace)
+
+		if err := ss.setEntry(service, prevKey, previousEntry(old, expires), nil); err != nil {
+			return fmt.Errorf("failed to write previous data to keyring: %w", err)
+		}
+	} else if err := ss.deletePrevious(service, key); err != nil {
+		return err
+	}
+
+	return ss.setRaw(service, key, d, labelsFromParams(params))
+}
+
+// rebind decodes the data that is bound to a key, and encodes it again for another key.
+func (ss *KeyringStorage[V]) rebind(fromService string, fromKey string, service string, key string, d string) (string, error) {
+	var v V
+
+	if err := ss.decode(fromService, fromKey, d, &v); err != nil {
+		return "", err
+	}
+
+	return ss.encode(service, key, v)
+}
+
+// GetPrevious returns the value that the key had before its last rotation, see Rotate. The boolean is false if there
+// is no previous value, or if its grace period has elapsed.
+func (ss *KeyringStorage[V]) GetPrevious(service string, key string) (V, bool, error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	return ss.getPrevious(service, key)
+}
+
+// getPrevious locks the key and reads its previous value if it has not expired. The expired value is deleted.
+func (ss *KeyringStorage[V]) getPrevious(service string, key string) (V, bool, error) {
+	var result V
+
+	mu := ss.mutex(service, key)
+
+	mu.RLock()
+
+	d, expires, err := ss.readPrevious(service, key)
+
+	mu.RUnlock()
+
+	if errors.Is(err, ErrNotFound) {
+		return result, false, nil
+	} else if err != nil {
+		return result, false, err
+	}
+
+	if !ss.clock.Now().Before(expires) {
+		ss.deleteExpiredPrevious(service, key)
+
+		return result, false, nil
+	}
+
+	if err := ss.decode(service, key, d, &result); err != nil {
+		return result, false, fmt.Errorf("failed to unmarshal data read from keyring: %w", err)
+	}
+
+	return result, true, nil
+}
+
+// readPrevious reads the previous value of the key, and its expiry.
+func (ss *KeyringStorage[V]) readPrevious(service string, key string) (string, time.Time, error) {
+	d, err := ss.getRaw(service, previousKey(key))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return parsePreviousEntry(d)
+}
+
+// deleteExpiredPrevious locks the key and deletes its previous value if it has expired.
+func (ss *KeyringStorage[V]) deleteExpiredPrevious(service string, key string) {
+	if ss.readOnly {
+		return
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, expires, err := ss.readPrevious(service, key); err != nil || ss.clock.Now().Before(expires) {
+		return
+	}
+
+	if err := ss.deletePrevious(service, key); err != nil {
+		slog.Debug("failed to delete expired previous secret", "service", service, "key", key, "error", err)
+	}
+}
+
+// deletePrevious deletes the previous value of the key, if any. The key must be locked by the caller.
+func (ss *KeyringStorage[V]) deletePrevious(service string, key string) error {
+	prevKey := previousKey(key)
+
+	if _, err := ss.delete(service, prevKey); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to delete previous data in keyring: %w", err)
+	}
+
+	ss.releaseEntries(service, prevKey)
+
+	return nil
+}
+
+// previousKey returns the key of the slot that keeps the previous value of the key.
+func previousKey(key string) string {
+	return key + previousKeySuffix
+}
+
+// isPreviousKey tells whether the key is the slot of a previous value, see Rotate.
+func isPreviousKey(key string) bool {
+	return strings.HasSuffix(key, previousKeySuffix)
+}
+
+// previousEntry returns the data with its expiry in a header.
+func previousEntry(d string, expires time.Time) string {
+	return mime.FormatMediaType(mimePreviousSecret, map[string]string{
+		"expires": expires.UTC().Format(time.RFC3339Nano),
+	}) + "\n" + d
+}
+
+// parsePreviousEntry returns the data and its expiry.
+func parsePreviousEntry(d string) (string, time.Time, error) {
+	if !strings.HasPrefix(d, mimePreviousSecret) {
+		return "", time.Time{}, fmt.Errorf("%w: data is not a previous value", ErrCorruptSecret)
+	}
+
+	header, data, _ := strings.Cut(d, "\n")
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", time.Time{}, &headerError{field: "params", err: err}
+	}
+
+	expires, err := time.Parse(time.RFC3339Nano, params["expires"])
+	if err != nil {
+		return "", time.Time{}, &headerError{field: "expires", err: err}
+	}
+
+	return data, expires, nil
+}
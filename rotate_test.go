@@ -0,0 +1,216 @@
+package secretstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Rotate(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithClock(clock))
+
+	require.NoError(t, s.SetWith(t.Name(), "api-key", "old", secretstorage.WithLabels(map[string]string{"env": "prod"})))
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "new", time.Hour))
+
+	actual, err := s.Get(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual)
+
+	labels, err := s.Labels(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod"}, labels)
+
+	// The previous value is valid during the grace period.
+	clock.Add(59 * time.Minute)
+
+	previous, ok, err := s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "old", previous)
+
+	// And expires after it.
+	clock.Add(time.Minute)
+
+	previous, ok, err = s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, previous)
+}
+
+func TestKeyringStorage_Rotate_Again(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithClock(clock))
+
+	require.NoError(t, s.Set(t.Name(), "api-key", "v1"))
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "v2", time.Hour))
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "v3", time.Minute))
+
+	previous, ok, err := s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v2", previous)
+
+	// The grace period of the last rotation applies.
+	clock.Add(time.Minute)
+
+	_, ok, err = s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// A rotation without grace period drops the previous value.
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "v4", 0))
+
+	_, ok, err = s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestKeyringStorage_Rotate_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithClock(newFakeClock()))
+
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "new", time.Hour))
+
+	actual, err := s.Get(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual)
+
+	_, ok, err := s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestKeyringStorage_Rotate_Multipart(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithClock(clock),
+		secretstorage.WithMaxLength(40),
+	)
+
+	old := randString(100)
+
+	require.NoError(t, s.Set(t.Name(), "api-key", old))
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "new", time.Hour))
+
+	previous, ok, err := s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, old, previous)
+}
+
+func TestKeyringStorage_Rotate_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithReadOnly())
+
+	err := s.Rotate(t.Name(), "api-key", "new", time.Hour)
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
+
+func TestKeyringStorage_Rotate_Delete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+		delete   func(s *secretstorage.KeyringStorage[string], service string) error
+	}{
+		{
+			scenario: "delete",
+			delete: func(s *secretstorage.KeyringStorage[string], service string) error {
+				return s.Delete(service, "api-key")
+			},
+		},
+		{
+			scenario: "soft delete",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithSoftDelete()},
+			delete: func(s *secretstorage.KeyringStorage[string], service string) error {
+				return s.Delete(service, "api-key")
+			},
+		},
+		{
+			scenario: "purge",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithSoftDelete()},
+			delete: func(s *secretstorage.KeyringStorage[string], service string) error {
+				if err := s.Delete(service, "api-key"); err != nil {
+					return err
+				}
+
+				return s.Purge(service, "api-key")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](append(tc.options,
+				secretstorage.WithKeyring(k),
+				secretstorage.WithClock(newFakeClock()),
+			)...)
+
+			require.NoError(t, s.Set(t.Name(), "api-key", "old"))
+			require.NoError(t, s.Rotate(t.Name(), "api-key", "new", time.Hour))
+			require.NoError(t, tc.delete(s, t.Name()))
+
+			_, ok, err := s.GetPrevious(t.Name(), "api-key")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.NotContains(t, k.entries(t.Name()), "api-key~previous")
+		})
+	}
+}
+
+func TestKeyringStorage_Rotate_DeletesExpiredPrevious(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithClock(clock))
+
+	require.NoError(t, s.Set(t.Name(), "api-key", "old"))
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "new", time.Hour))
+
+	clock.Add(time.Hour)
+
+	_, ok, err := s.GetPrevious(t.Name(), "api-key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]string{"api-key": "new"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Rotate_ReservedKey(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithClock(newFakeClock()))
+
+	err := s.Set(t.Name(), "api-key~previous", "value")
+	require.ErrorIs(t, err, secretstorage.ErrReservedKey)
+
+	require.NoError(t, s.Set(t.Name(), "api-key", "old"))
+	require.NoError(t, s.Rotate(t.Name(), "api-key", "new", time.Hour))
+
+	// The previous slot is not listed.
+	keys, err := s.List(t.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-key"}, keys)
+}
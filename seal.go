@@ -0,0 +1,297 @@
+package secretstorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	compressionGzip  = "gzip"
+	encryptionAESGCM = "aes-gcm"
+
+	// maxDecompressedLength caps the length of the decompressed data, so that a crafted data does not exhaust the
+	// memory.
+	maxDecompressedLength = 64 << 20
+)
+
+// ErrNotEncrypted indicates that the data is not encrypted while the storage encrypts the values, see
+// WithPlaintextFallback.
+var ErrNotEncrypted = errors.New("data is not encrypted")
+
+func (ss *KeyringStorage[V]) withCompression() {
+	ss.compression = true
+}
+
+func (ss *KeyringStorage[V]) withEncryption(key []byte) {
+	ss.encryptionKey = key
+}
+
+func (ss *KeyringStorage[V]) withDecryptionKeys(keys ...[]byte) {
+	ss.decryptionKeys = append(ss.decryptionKeys, keys...)
+}
+
+func (ss *KeyringStorage[V]) withPlaintextFallback() {
+	ss.plaintext = true
+}
+
+// WithCompression compresses the marshaled values with gzip before writing them, so that the long values need less
+// pages. The values are only stored compressed when it makes them smaller, the tiny or already compressed values are
+// stored as is, and the header records whether the data is compressed.
+//
+// When it is combined with WithEncryption, the data is always compressed first, and then encrypted, regardless of the
+// order of the options: the encrypted data does not compress.
+func WithCompression() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withCompression()
+	})
+}
+
+// WithEncryption encrypts the marshaled values with AES-GCM before writing them, on top of the protection of the
+// keyring. The key must be 16, 24 or 32 bytes long, to select AES-128, AES-192 or AES-256.
+//
+// When it is combined with WithCompression, the data is always compressed first, and then encrypted, regardless of the
+// order of the options.
+//
+// The header of the data records the id of the key, a fingerprint derived from it, so that the data encrypted with
+// the older keys is still read during a rotation, see WithDecryptionKeys and RotateEncryptionKey.
+//
+// The ciphertext is bound to its service and key, it can not be moved to another key. The values that are not
+// encrypted are rejected with ErrNotEncrypted, see WithPlaintextFallback.
+func WithEncryption(key []byte) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withEncryption(key)
+	})
+}
+
+// WithDecryptionKeys registers the keys that were used before the current one, see WithEncryption. The data encrypted
+// with one of them is still decrypted, the data is encrypted with the current key the next time it is written, or
+// when it is rotated with RotateEncryptionKey.
+func WithDecryptionKeys(keys ...[]byte) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withDecryptionKeys(keys...)
+	})
+}
+
+// WithPlaintextFallback reads the values that are not encrypted while the storage encrypts the values, such as the
+// values that are written before WithEncryption is enabled. They are encrypted the next time they are written.
+//
+// Without it, such values are rejected with ErrNotEncrypted, so that a value that is planted in the keyring is not
+// read as if it was written by the storage.
+func WithPlaintextFallback() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withPlaintextFallback()
+	})
+}
+
+// sealed tells whether the data is compressed or encrypted before it is written.
+func (ss *KeyringStorage[V]) sealed() bool {
+	return ss.compression || ss.encryptionKey != nil
+}
+
+// seal compresses the data if it makes it smaller, then encrypts it, as configured, and records the steps in the
+// parameters of the header. The data is returned as is if no step applies. The ciphertext is bound to the service and
+// the key.
+func (ss *KeyringStorage[V]) seal(service string, key string, b []byte, params map[string]string) ([]byte, error) {
+	if ss.compression {
+		gz, err := compress(b)
+		if err != nil {
+			return nil, err
+		}
+
+		// The data is encoded with base64 in the end, unless it is left as is.
+		size := len(b)
+		if ss.encryptionKey != nil {
+			size = base64.StdEncoding.EncodedLen(size)
+		}
+
+		if base64.StdEncoding.EncodedLen(len(gz)) < size {
+			b = gz
+			params["compression"] = compressionGzip
+		}
+	}
+
+	if ss.encryptionKey == nil && params["compression"] == "" {
+		return b, nil
+	}
+
+	if ss.encryptionKey != nil {
+		var err error
+
+		if b, err = encrypt(ss.rand(), ss.encryptionKey, b, associatedData(service, key)); err != nil {
+			return nil, err
+		}
+
+		params["encryption"] = encryptionAESGCM
+		params["key-id"] = keyID(ss.encryptionKey)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// unseal reverses seal, following the steps that are recorded in the parameters of the header.
+func (ss *KeyringStorage[V]) unseal(service string, key string, d string, params map[string]string) (string, error) {
+	compression, compressed := params["compression"]
+	encryption, encrypted := params["encryption"]
+
+	if !encrypted && ss.encryptionKey != nil && !ss.plaintext {
+		return "", fmt.Errorf("failed to decrypt data: %w", ErrNotEncrypted)
+	}
+
+	if !compressed && !encrypted {
+		return d, nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(d)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	if encrypted {
+		if b, err = ss.decrypt(b, encryption, params["key-id"], associatedData(service, key)); err != nil {
+			return "", err
+		}
+	}
+
+	if compressed {
+		if compression != compressionGzip {
+			return "", &headerError{field: "compression", err: fmt.Errorf("unsupported compression %q", compression)} //nolint: goerr113
+		}
+
+		if b, err = decompress(b); err != nil {
+			return "", err
+		}
+	}
+
+	return string(b), nil
+}
+
+func compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	if _, err := w.Write(b); err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress decompresses the data, up to maxDecompressedLength.
+func decompress(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+
+	if b, err = io.ReadAll(io.LimitReader(r, maxDecompressedLength+1)); err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+
+	if len(b) > maxDecompressedLength {
+		return nil, fmt.Errorf("failed to decompress data: %w: the data is longer than %d bytes", ErrCorruptSecret, maxDecompressedLength)
+	}
+
+	return b, nil
+}
+
+// decrypt decrypts the data with the key of the id, or tries the keys in turn if no key has the id.
+func (ss *KeyringStorage[V]) decrypt(b []byte, encryption string, id string, ad []byte) ([]byte, error) {
+	if encryption != encryptionAESGCM {
+		return nil, &headerError{field: "encryption", err: fmt.Errorf("unsupported encryption %q", encryption)} //nolint: goerr113
+	}
+
+	keys := ss.encryptionKeys()
+
+	if len(keys) == 0 {
+		return nil, errors.New("failed to decrypt data: no encryption key") //nolint: goerr113
+	}
+
+	for _, key := range keys {
+		if id != "" && keyID(key) == id {
+			return decrypt(key, b, ad)
+		}
+	}
+
+	// The data is encrypted before the ids are recorded, or with an unknown key.
+	var err error
+
+	for _, key := range keys {
+		var d []byte
+
+		if d, err = decrypt(key, b, ad); err == nil {
+			return d, nil
+		}
+	}
+
+	return nil, err
+}
+
+// encryptionKeys returns the current key, if any, followed by the older keys.
+func (ss *KeyringStorage[V]) encryptionKeys() [][]byte {
+	if ss.encryptionKey == nil {
+		return ss.decryptionKeys
+	}
+
+	return append([][]byte{ss.encryptionKey}, ss.decryptionKeys...)
+}
+
+// keyID returns the id of the key that is recorded with the data. It is derived from the key with SHA-256, and does not
+// reveal it.
+func keyID(key []byte) string {
+	sum := sha256.Sum256(append([]byte("secretstorage key id\n"), key...))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+func encrypt(r io.Reader, key []byte, b []byte, ad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, b, ad), nil
+}
+
+// decrypt decrypts the data that is bound to the associated data. The data that is encrypted before the ciphertexts
+// are bound to their service and key has no associated data, it is still decrypted.
+func decrypt(key []byte, b []byte, ad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return openSealed(aead, b, ad, nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return aead, nil
+}
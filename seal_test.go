@@ -0,0 +1,325 @@
+package secretstorage_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+var encryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+// associatedData is the associated data that the ciphertext is bound to, see WithEncryption.
+func associatedData(service string, key string) []byte {
+	b := binary.BigEndian.AppendUint64(nil, uint64(len(service)))
+	b = append(b, service...)
+	b = binary.BigEndian.AppendUint64(b, uint64(len(key)))
+
+	return append(b, key...)
+}
+
+// storedPayload returns the parameters of the header, and the payload of the stored data, decoded from base64.
+func storedPayload(t *testing.T, d string) (string, []byte) {
+	t.Helper()
+
+	header, data, ok := strings.Cut(d, "\n")
+	require.True(t, ok)
+
+	b, err := base64.StdEncoding.DecodeString(data)
+	require.NoError(t, err)
+
+	return header, b
+}
+
+func TestKeyringStorage_Compression(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithCompression())
+
+	value := strings.Repeat("compressible ", 200)
+
+	require.NoError(t, s.Set(t.Name(), "key", value))
+
+	d := k.entries(t.Name())["key"]
+
+	header, b := storedPayload(t, d)
+
+	assert.Equal(t, "application/encoded-secret; codec=text; compression=gzip", header)
+	assert.Less(t, len(d), len(value))
+
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+
+	plain, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, value, string(plain))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, value, actual)
+}
+
+func TestKeyringStorage_Compression_OnlyWhenSmaller(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+		value    string
+		expected string
+	}{
+		{
+			scenario: "tiny value",
+			value:    "p4ssw0rd",
+			expected: "p4ssw0rd",
+		},
+		{
+			scenario: "incompressible value",
+			value:    randString(500),
+		},
+		{
+			scenario: "tiny value with codec",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCodec(secretstorage.JSONCodec{}), secretstorage.WithForceCodec()},
+			value:    "p4ssw0rd",
+			expected: "application/encoded-secret; codec=json\n\"p4ssw0rd\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			opts := append([]secretstorage.KeyringStorageOption{secretstorage.WithKeyring(k), secretstorage.WithCompression()}, tc.options...)
+			s := secretstorage.NewKeyringStorage[string](opts...)
+
+			require.NoError(t, s.Set(t.Name(), "key", tc.value))
+
+			expected := tc.expected
+			if expected == "" {
+				expected = tc.value
+			}
+
+			assert.Equal(t, expected, k.entries(t.Name())["key"])
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_Compression_OnlyWhenSmaller_Encryption(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithCompression(), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+
+	header, _ := storedPayload(t, k.entries(t.Name())["key"])
+
+	assert.Equal(t, "application/encoded-secret; codec=text; encryption=aes-gcm; key-id=805f2f9ab44345f3", header)
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", actual)
+}
+
+func TestKeyringStorage_CompressionAndEncryption(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []secretstorage.KeyringStorageOption
+	}{
+		{
+			scenario: "compression first",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithCompression(), secretstorage.WithEncryption(encryptionKey)},
+		},
+		{
+			scenario: "encryption first",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithEncryption(encryptionKey), secretstorage.WithCompression()},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](append([]secretstorage.KeyringStorageOption{secretstorage.WithKeyring(k)}, tc.options...)...)
+			compressed := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithCompression())
+
+			value := strings.Repeat("compressible ", 200)
+
+			require.NoError(t, s.Set(t.Name(), "key", value))
+			require.NoError(t, compressed.Set(t.Name(), "compressed", value))
+
+			d := k.entries(t.Name())["key"]
+
+			header, b := storedPayload(t, d)
+			_, compressedPayload := storedPayload(t, k.entries(t.Name())["compressed"])
+
+			assert.Equal(t, "application/encoded-secret; codec=text; compression=gzip; encryption=aes-gcm; key-id=805f2f9ab44345f3", header)
+
+			// The data is compressed before it is encrypted, otherwise it would not be smaller.
+			assert.Less(t, len(d), len(value))
+
+			// The ciphertext is not the compressed plaintext.
+			assert.NotContains(t, string(b), string(compressedPayload))
+
+			// Decrypting, then decompressing, round-trips.
+			block, err := aes.NewCipher(encryptionKey)
+			require.NoError(t, err)
+
+			aead, err := cipher.NewGCM(block)
+			require.NoError(t, err)
+
+			// The ciphertext is bound to the service and the key.
+			gz, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], associatedData(t.Name(), "key"))
+			require.NoError(t, err)
+
+			r, err := gzip.NewReader(bytes.NewReader(gz))
+			require.NoError(t, err)
+
+			plain, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, value, string(plain))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, value, actual)
+		})
+	}
+}
+
+func TestKeyringStorage_Encryption_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, s.Set(t.Name(), "key", "p4ssw0rd"))
+	assert.NotContains(t, k.entries(t.Name())["key"], "p4ssw0rd")
+
+	other := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithEncryption([]byte("fedcba9876543210")))
+
+	_, err := other.Get(t.Name(), "key")
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: failed to decrypt data: cipher: message authentication failed")
+
+	plain := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	_, err = plain.Get(t.Name(), "key")
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: failed to decrypt data: no encryption key")
+}
+
+func TestKeyringStorage_Encryption_ReadsPlainData(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	plain := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, plain.Set(t.Name(), "key", "secret"))
+
+	// The plain data is rejected unless the storage opts in.
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotEncrypted)
+
+	require.NoError(t, s.Reconfigure(secretstorage.WithPlaintextFallback()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+}
+
+func TestKeyringStorage_Encryption_BoundToKey(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, s.Set(t.Name(), "key", "secret"))
+
+	// The ciphertext that is moved to another key is not decrypted.
+	require.NoError(t, k.Set(t.Name(), "other", k.entries(t.Name())["key"]))
+
+	_, err := s.Get(t.Name(), "other")
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: failed to decrypt data: cipher: message authentication failed")
+
+	// The references are followed, the value is bound to the target.
+	require.NoError(t, s.SetRef(t.Name(), "ref", t.Name(), "key"))
+
+	actual, err := s.Get(t.Name(), "ref")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+
+	// The old value of a reference is kept for the key.
+	require.NoError(t, s.Rotate(t.Name(), "ref", "new secret", time.Hour))
+
+	actual, ok, err := s.GetPrevious(t.Name(), "ref")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "secret", actual)
+}
+
+func TestKeyringStorage_Encryption_ReadsUnboundData(t *testing.T) {
+	t.Parallel()
+
+	block, err := aes.NewCipher(encryptionKey)
+	require.NoError(t, err)
+
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	// The data is encrypted before the ciphertexts are bound to their service and key.
+	nonce := make([]byte, aead.NonceSize())
+	b := aead.Seal(nonce, nonce, []byte("secret"), nil)
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithEncryption(encryptionKey))
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/encoded-secret; codec=text; encryption=aes-gcm\n"+base64.StdEncoding.EncodeToString(b)))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+}
+
+func TestKeyringStorage_Compression_TooLong(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	_, err := w.Write(make([]byte, 64<<20+1))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithCompression())
+
+	require.NoError(t, k.Set(t.Name(), "key", "application/encoded-secret; codec=text; compression=gzip\n"+base64.StdEncoding.EncodeToString(buf.Bytes())))
+
+	_, err = s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCorruptSecret)
+	require.EqualError(t, err, "failed to unmarshal data read from keyring: failed to decompress data: corrupt secret: the data is longer than 67108864 bytes")
+}
@@ -0,0 +1,136 @@
+package secretstorage
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrStorageClosed indicates that the storage is closed.
+var ErrStorageClosed = errors.New("storage is closed")
+
+var (
+	_ Storage[any] = (*SerializedStorage[any])(nil)
+	_ Flusher      = (*SerializedStorage[any])(nil)
+)
+
+// SerializedStorage funnels all the operations of another storage through a single goroutine, so that exactly one call
+// to the storage happens at a time, whatever the keys and the services are. It is meant for the backends that are not
+// safe for concurrent access at all.
+//
+// The worker goroutine runs until Close is called.
+type SerializedStorage[V any] struct {
+	storage Storage[V]
+	calls   chan func()
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Get gets the value from the storage.
+func (s *SerializedStorage[V]) Get(service string, key string) (V, error) {
+	var (
+		v   V
+		err error
+	)
+
+	if cErr := s.do(func() { v, err = s.storage.Get(service, key) }); cErr != nil {
+		return v, cErr
+	}
+
+	return v, err //nolint: wrapcheck
+}
+
+// Set sets the value in the storage.
+func (s *SerializedStorage[V]) Set(service string, key string, value V) error {
+	var err error
+
+	if cErr := s.do(func() { err = s.storage.Set(service, key, value) }); cErr != nil {
+		return cErr
+	}
+
+	return err //nolint: wrapcheck
+}
+
+// Delete deletes the value in the storage.
+func (s *SerializedStorage[V]) Delete(service string, key string) error {
+	var err error
+
+	if cErr := s.do(func() { err = s.storage.Delete(service, key) }); cErr != nil {
+		return cErr
+	}
+
+	return err //nolint: wrapcheck
+}
+
+// Flush flushes the decorated storage, once the pending operations are done.
+func (s *SerializedStorage[V]) Flush() error {
+	var err error
+
+	if cErr := s.do(func() { err = flush(s.storage) }); cErr != nil {
+		return cErr
+	}
+
+	return err
+}
+
+// Close stops the worker goroutine, once the pending operations are done. The operations that are called after Close
+// return ErrStorageClosed. Close is safe to call more than once.
+func (s *SerializedStorage[V]) Close() error {
+	s.mu.Lock()
+
+	if !s.closed {
+		s.closed = true
+
+		close(s.calls)
+	}
+
+	s.mu.Unlock()
+
+	<-s.done
+
+	return nil
+}
+
+// do runs the function in the worker goroutine, and waits for it.
+func (s *SerializedStorage[V]) do(fn func()) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrStorageClosed
+	}
+
+	done := make(chan struct{})
+
+	s.calls <- func() {
+		defer close(done)
+
+		fn()
+	}
+
+	<-done
+
+	return nil
+}
+
+func (s *SerializedStorage[V]) work() {
+	defer close(s.done)
+
+	for fn := range s.calls {
+		fn()
+	}
+}
+
+// NewSerializedStorage creates a new SerializedStorage, and starts its worker goroutine.
+func NewSerializedStorage[V any](storage Storage[V]) *SerializedStorage[V] {
+	s := &SerializedStorage[V]{
+		storage: storage,
+		calls:   make(chan func()),
+		done:    make(chan struct{}),
+	}
+
+	go s.work()
+
+	return s
+}
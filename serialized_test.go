@@ -0,0 +1,69 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestSerializedStorage(t *testing.T) {
+	t.Parallel()
+
+	k := newConcurrencyKeyring(100 * time.Microsecond)
+	s := secretstorage.NewSerializedStorage[string](secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k)))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			// Different services and keys.
+			service := fmt.Sprintf("%s-%d", t.Name(), i%3)
+			key := fmt.Sprintf("key-%d", i)
+			value := randString(5000)
+
+			assert.NoError(t, s.Set(service, key, value))
+
+			actual, err := s.Get(service, key)
+			assert.NoError(t, err)
+			assert.Equal(t, value, actual)
+
+			assert.NoError(t, s.Delete(service, key))
+
+			_, err = s.Get(service, key)
+			assert.ErrorIs(t, err, secretstorage.ErrNotFound)
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int64(1), k.maxConcurrentCalls())
+}
+
+func TestSerializedStorage_Close(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewSerializedStorage[string](secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring())))
+
+	require.NoError(t, s.Set(t.Name(), "key", "value"))
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close())
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrStorageClosed)
+
+	err = s.Set(t.Name(), "key", "value")
+	require.ErrorIs(t, err, secretstorage.ErrStorageClosed)
+
+	err = s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrStorageClosed)
+}
@@ -0,0 +1,201 @@
+package secretstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoServiceIndex indicates that the services could not be listed because the service index is not enabled.
+var ErrNoServiceIndex = errors.New("service index is not enabled")
+
+const (
+	serviceIndexService = "go.nhat.io/secretstorage"
+	serviceIndexKey     = "services"
+)
+
+func (ss *KeyringStorage[V]) withServiceIndex() {
+	ss.serviceIndex = true
+}
+
+// WithServiceIndex maintains an index of the services that the storage writes to, so that they can be listed with
+// Services. The index is stored in the keyring, in the "services" key of the "go.nhat.io/secretstorage" service.
+//
+// The index only reflects the services that are written through the storage, with the index enabled. It has an
+// overhead: the first write to a service reads and rewrites the index, and so does DeleteAll. The index is not
+// transactional, a service stays listed after its keys are deleted one by one, and a process that crashes between the
+// write of a value and the update of the index, or another process that writes to the keyring, leaves it incomplete.
+//
+// The updates of the index are read-modify-write operations under a lock dedicated to the index, so the concurrent
+// writes to different services do not lose each other's updates. The lock is held by the storage: two storages, or two
+// processes, that update the same index at the same time may still lose updates. The index is split into pages like
+// any other data when it is too long.
+func WithServiceIndex() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withServiceIndex()
+	})
+}
+
+// Services returns the sorted services of the service index, see WithServiceIndex. If the index is not enabled, the
+// services are listed by the keyring if it implements ServiceLister, such as FileKeyring. Otherwise, an error that
+// matches both ErrListingNotSupported and ErrNoServiceIndex is returned, the OS keyrings do not enumerate their
+// services.
+func (ss *KeyringStorage[V]) Services() ([]string, error) {
+	defer ss.rlockConfig()()
+
+	if !ss.serviceIndex {
+		return ss.listServices()
+	}
+
+	mu := ss.mutex(serviceIndexService, serviceIndexKey)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	services, err := ss.readServiceIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(services))
+
+	for s := range services {
+		result = append(result, s)
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// indexService records the service in the service index, if enabled.
+func (ss *KeyringStorage[V]) indexService(service string) error {
+	if !ss.serviceIndex || service == serviceIndexService {
+		return nil
+	}
+
+	if _, ok := ss.indexed.Load(service); ok {
+		return nil
+	}
+
+	return ss.updateServiceIndex(func(services map[string]struct{}) bool {
+		if _, ok := services[service]; ok {
+			return false
+		}
+
+		services[service] = struct{}{}
+
+		return true
+	}, func() {
+		ss.indexed.Store(service, struct{}{})
+	})
+}
+
+// unindexService removes the service from the service index, if enabled.
+func (ss *KeyringStorage[V]) unindexService(service string) error {
+	if !ss.serviceIndex || service == serviceIndexService {
+		return nil
+	}
+
+	return ss.updateServiceIndex(func(services map[string]struct{}) bool {
+		if _, ok := services[service]; !ok {
+			return false
+		}
+
+		delete(services, service)
+
+		return true
+	}, func() {
+		ss.indexed.Delete(service)
+	})
+}
+
+// updateServiceIndex reads the service index, applies the change, and writes the index back if it changed. The done
+// function is called once the index is up-to-date.
+func (ss *KeyringStorage[V]) updateServiceIndex(change func(services map[string]struct{}) bool, done func()) error {
+	mu := ss.mutex(serviceIndexService, serviceIndexKey)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	services, err := ss.readServiceIndex()
+	if err != nil {
+		return fmt.Errorf("failed to update service index: %w", err)
+	}
+
+	if change(services) {
+		result := make([]string, 0, len(services))
+
+		for s := range services {
+			result = append(result, s)
+		}
+
+		sort.Strings(result)
+
+		d, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to update service index: %w", err)
+		}
+
+		if err := ss.writeRaw(serviceIndexService, serviceIndexKey, string(d), nil); err != nil {
+			return fmt.Errorf("failed to update service index: %w", err)
+		}
+	}
+
+	done()
+
+	return nil
+}
+
+// readServiceIndex reads the services of the index, the index is empty if it does not exist.
+func (ss *KeyringStorage[V]) readServiceIndex() (map[string]struct{}, error) {
+	var services []string
+
+	d, err := ss.getRaw(serviceIndexService, serviceIndexKey)
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+
+	case err != nil:
+		return nil, err
+
+	default:
+		if err := json.Unmarshal([]byte(d), &services); err != nil {
+			return nil, fmt.Errorf("failed to read service index: %w", err)
+		}
+	}
+
+	result := make(map[string]struct{}, len(services))
+
+	for _, s := range services {
+		result[s] = struct{}{}
+	}
+
+	return result, nil
+}
+
+// listServices lists the services of the keyring, without the service of the index.
+func (ss *KeyringStorage[V]) listServices() ([]string, error) {
+	l, err := ss.serviceLister()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, ErrNoServiceIndex)
+	}
+
+	services, err := l.Services()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in keyring: %w", err)
+	}
+
+	result := make([]string, 0, len(services))
+
+	for _, s := range services {
+		if s != serviceIndexService {
+			result = append(result, s)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
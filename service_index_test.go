@@ -0,0 +1,181 @@
+package secretstorage_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Services_NoIndex(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()))
+
+	require.NoError(t, s.Set(t.Name(), "key", "value"))
+
+	actual, err := s.Services()
+
+	require.ErrorIs(t, err, secretstorage.ErrNoServiceIndex)
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.Nil(t, actual)
+}
+
+func TestKeyringStorage_Services_ServiceLister(t *testing.T) {
+	t.Parallel()
+
+	k, err := secretstorage.NewFileKeyring(filepath.Join(t.TempDir(), "secrets"), make([]byte, 32))
+	require.NoError(t, err)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithKeyHasher(nil),
+		secretstorage.WithPanicRecovery(),
+	)
+
+	actual, err := s.Services()
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	require.NoError(t, s.Set("b", "key", "value"))
+	require.NoError(t, s.Set("a", "key", "value"))
+	require.NoError(t, s.Set("a", "another key", "value"))
+
+	actual, err = s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, actual)
+
+	// The services without keys are not listed.
+	require.NoError(t, s.Delete("b", "key"))
+
+	actual, err = s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, actual)
+}
+
+func TestKeyringStorage_Services(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithServiceIndex())
+
+	actual, err := s.Services()
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	require.NoError(t, s.Set("b", "key", "value"))
+	require.NoError(t, s.Set("a", "key", "value"))
+	require.NoError(t, s.Set("a", "another key", "value"))
+	require.NoError(t, s.SetReader("c", "key", strings.NewReader("value")))
+	require.NoError(t, s.SetRef("d", "key", "a", "key"))
+
+	actual, err = s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, actual)
+
+	assert.Equal(t, `["a","b","c","d"]`, k.entries("go.nhat.io/secretstorage")["services"])
+
+	// A service stays listed until it is deleted with DeleteAll.
+	require.NoError(t, s.Delete("b", "key"))
+	require.NoError(t, s.DeleteAll("a"))
+
+	actual, err = s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d"}, actual)
+
+	// The index is shared with the other storages.
+	other := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithServiceIndex())
+
+	require.NoError(t, other.Set("a", "key", "value"))
+
+	actual, err = s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, actual)
+}
+
+func TestKeyringStorage_Services_Multipart(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithServiceIndex(),
+		secretstorage.WithMaxLength(10),
+	)
+
+	expected := []string{"service 1", "service 2", "service 3"}
+
+	for _, service := range expected {
+		require.NoError(t, s.Set(service, "key", "value"))
+	}
+
+	actual, err := s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestKeyringStorage_Services_CorruptIndex(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set("go.nhat.io/secretstorage", "services", "services"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithServiceIndex())
+
+	_, err := s.Services()
+	require.EqualError(t, err, "failed to read service index: invalid character 's' looking for beginning of value")
+
+	err = s.Set(t.Name(), "key", "value")
+	require.EqualError(t, err, "failed to update service index: failed to read service index: invalid character 's' looking for beginning of value")
+}
+
+func TestKeyringStorage_Services_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const services = 100
+
+	// The index is multipart.
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithServiceIndex(),
+		secretstorage.WithMaxLength(64),
+	)
+
+	expected := make([]string, 0, services)
+
+	for i := 0; i < services; i++ {
+		expected = append(expected, fmt.Sprintf("service %03d", i))
+	}
+
+	var wg sync.WaitGroup
+
+	for _, service := range expected {
+		service := service
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, s.Set(service, "key", "value"))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, s.Set(service, "another key", "value"))
+		}()
+	}
+
+	wg.Wait()
+
+	actual, err := s.Services()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,44 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrBackendSizeLimit indicates that the keyring rejected an entry because it is too large, see WithSizeLimitErrors.
+var ErrBackendSizeLimit = errors.New("backend size limit exceeded")
+
+func (ss *KeyringStorage[V]) withSizeLimitErrors() {
+	ss.sizeLimitErrors = true
+}
+
+// WithSizeLimitErrors makes the writes fail with ErrBackendSizeLimit, together with the size of the entry, when the
+// keyring rejects an entry because it is too large, which happens when the max length is too large for the platform,
+// see WithMaxLength. The errors that are not recognized are returned unchanged.
+func WithSizeLimitErrors() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withSizeLimitErrors()
+	})
+}
+
+// sizeLimitError wraps the error of writing an entry of the given size with ErrBackendSizeLimit, if the error is
+// recognized as a size limit error.
+func (ss *KeyringStorage[V]) sizeLimitError(err error, size int) error {
+	if !ss.sizeLimitErrors || !isSizeLimitError(err) {
+		return err
+	}
+
+	return fmt.Errorf("%w: the entry has %d bytes, try a max length lower than %d: %w", ErrBackendSizeLimit, size, ss.maxLength, err)
+}
+
+func isSizeLimitError(err error) bool {
+	if errors.Is(err, keyring.ErrSetDataTooBig) {
+		return true
+	}
+
+	// Windows Credential Manager rejects the large blobs with RPC_X_BAD_STUB_DATA.
+	return strings.Contains(err.Error(), "stub received bad data")
+}
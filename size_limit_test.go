@@ -0,0 +1,96 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+// sizeLimitedKeyring is a memoryKeyring that rejects the entries larger than the limit.
+type sizeLimitedKeyring struct {
+	*memoryKeyring
+
+	limit int
+}
+
+func (k *sizeLimitedKeyring) Set(service, user, password string) error {
+	if len(password) > k.limit {
+		return keyring.ErrSetDataTooBig
+	}
+
+	return k.memoryKeyring.Set(service, user, password)
+}
+
+func TestKeyringStorage_SizeLimitErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		value         string
+		expectedError string
+	}{
+		{
+			scenario:      "single",
+			value:         randString(1500),
+			expectedError: "failed to write data to keyring: backend size limit exceeded: the entry has 1500 bytes, try a max length lower than 2048: data passed to Set was too big",
+		},
+		{
+			scenario:      "multipart",
+			value:         randString(3000),
+			expectedError: "failed to write multipart data #1 to keyring: backend size limit exceeded: the entry has 2048 bytes, try a max length lower than 2048: data passed to Set was too big",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := &sizeLimitedKeyring{memoryKeyring: newMemoryKeyring(), limit: 1000}
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(k),
+				secretstorage.WithSizeLimitErrors(),
+			)
+
+			err := s.Set(t.Name(), "key", tc.value)
+
+			require.EqualError(t, err, tc.expectedError)
+			require.ErrorIs(t, err, secretstorage.ErrBackendSizeLimit)
+			require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
+			require.ErrorIs(t, err, keyring.ErrSetDataTooBig)
+
+			// A lower max length fixes it.
+			require.NoError(t, s.Reconfigure(secretstorage.WithMaxLength(1000)))
+			require.NoError(t, s.Set(t.Name(), "key", tc.value))
+		})
+	}
+}
+
+func TestKeyringStorage_SizeLimitErrors_Disabled(t *testing.T) {
+	t.Parallel()
+
+	k := &sizeLimitedKeyring{memoryKeyring: newMemoryKeyring(), limit: 1000}
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.Set(t.Name(), "key", randString(1500))
+
+	require.EqualError(t, err, "failed to write data to keyring: data passed to Set was too big")
+}
+
+func TestKeyringStorage_SizeLimitErrors_Unrecognized(t *testing.T) {
+	t.Parallel()
+
+	k := &limitedKeyring{memoryKeyring: newMemoryKeyring()}
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithSizeLimitErrors(),
+	)
+
+	err := s.Set(t.Name(), randString(300), "value")
+
+	require.EqualError(t, err, "failed to write data to keyring: name too long")
+}
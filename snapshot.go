@@ -0,0 +1,93 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/multierr"
+)
+
+// Snapshot captures all the entries of the service, exactly as they are stored in the keyring, and returns a function
+// that restores the service to the captured state: entries deleted after the snapshot are added back, entries added
+// after the snapshot are removed, and changed entries are reverted.
+//
+// The keyring must implement Lister, otherwise ErrListingNotSupported is returned. Snapshot and restore are not
+// synchronized with the other operations of the storage, they are meant to be used in tests.
+func (ss *KeyringStorage[V]) Snapshot(service string) (restore func() error, err error) {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	l, err := ss.lister()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ss.readEntries(l, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take snapshot: %w", err)
+	}
+
+	return func() error {
+		defer ss.rlockConfig()()
+
+		return ss.restoreEntries(l, service, entries)
+	}, nil
+}
+
+func (ss *KeyringStorage[V]) readEntries(l Lister, service string) (map[string]string, error) {
+	keys, err := l.List(service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data in keyring: %w", err)
+	}
+
+	entries := make(map[string]string, len(keys))
+
+	for _, key := range keys {
+		d, err := ss.keyring.Get(service, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data from keyring: %w", err)
+		}
+
+		entries[key] = d
+	}
+
+	return entries, nil
+}
+
+func (ss *KeyringStorage[V]) restoreEntries(l Lister, service string, entries map[string]string) error {
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	keys, err := l.List(service)
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot: failed to list data in keyring: %w", err)
+	}
+
+	for _, key := range keys {
+		if _, ok := entries[key]; ok {
+			continue
+		}
+
+		if dErr := ss.keyring.Delete(service, key); dErr != nil && !errors.Is(dErr, ErrNotFound) {
+			err = multierr.Append(err, fmt.Errorf("failed to delete data in keyring: %w", dErr))
+		}
+	}
+
+	for key, value := range entries {
+		if d, gErr := ss.keyring.Get(service, key); gErr == nil && d == value {
+			continue
+		}
+
+		if sErr := ss.keyring.Set(service, key, value); sErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to write data to keyring: %w", sErr))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	return nil
+}
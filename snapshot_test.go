@@ -0,0 +1,84 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_Snapshot_ListingNotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(mock.NopKeyring(t)))
+
+	restore, err := s.Snapshot(t.Name())
+
+	require.ErrorIs(t, err, secretstorage.ErrListingNotSupported)
+	assert.Nil(t, restore)
+}
+
+func TestKeyringStorage_Snapshot_Restore(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "unchanged", "value"))
+	require.NoError(t, s.Set(t.Name(), "changed", "old value"))
+	require.NoError(t, s.Set(t.Name(), "deleted", randString(5000)))
+	require.NoError(t, s.Set("another service", "key", "value"))
+
+	expected := k.entries(t.Name())
+
+	restore, err := s.Snapshot(t.Name())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Set(t.Name(), "changed", "new value"))
+	require.NoError(t, s.Delete(t.Name(), "deleted"))
+	require.NoError(t, s.Set(t.Name(), "added", randString(5000)))
+	require.NoError(t, s.Set("another service", "key", "another value"))
+
+	err = restore()
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "deleted")
+	require.NoError(t, err)
+	assert.Len(t, actual, 5000)
+
+	actual, err = s.Get("another service", "key")
+	require.NoError(t, err)
+	assert.Equal(t, "another value", actual, "other services must not be restored")
+}
+
+func TestKeyringStorage_Snapshot_Restore_Failure(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+
+	require.NoError(t, k.Set(t.Name(), "key", "value"))
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(&readOnlyKeyring{memoryKeyring: k}))
+
+	restore, err := s.Snapshot(t.Name())
+	require.NoError(t, err)
+
+	require.NoError(t, k.Delete(t.Name(), "key"))
+
+	err = restore()
+	require.EqualError(t, err, "failed to restore snapshot: failed to write data to keyring: assert.AnError general error for testing")
+}
+
+// readOnlyKeyring is a memoryKeyring that fails to write.
+type readOnlyKeyring struct {
+	*memoryKeyring
+}
+
+func (k *readOnlyKeyring) Set(string, string, string) error {
+	return assert.AnError
+}
@@ -0,0 +1,220 @@
+package secretstorage
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+const mimeDeletedSecret = "application/deleted-secret"
+
+func (ss *KeyringStorage[V]) withSoftDelete() {
+	ss.softDelete = true
+}
+
+// WithSoftDelete makes Delete mark the values as deleted instead of removing them from the keyring, so that they can
+// be restored with Undelete, or removed for good with Purge. The deleted values are not found by Get, and are replaced
+// by Set. The main entry of a deleted value records when it was deleted, the pages of a multipart value are kept as
+// they are. A single entry that is too long to record it is split into pages first, see WithMaxLength; Delete returns
+// ErrInvalidLabel if the header of a multipart value is still too long, because of its labels.
+//
+// The deleted values still use their keyring entries, and are still listed by the keyring, see Lister. DeleteKnown and
+// DeleteAll still remove the values for good.
+func WithSoftDelete() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withSoftDelete()
+	})
+}
+
+// Undelete restores the value that is deleted with WithSoftDelete. It returns ErrNotFound if the key does not exist
+// or is not deleted.
+func (ss *KeyringStorage[V]) Undelete(service string, key string) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	d, err := ss.deletedEntry(service, key)
+	if err != nil {
+		return ss.notFound(err)
+	}
+
+	if err := ss.set(service, key, d); err != nil {
+		return fmt.Errorf("failed to restore deleted data: %w", err)
+	}
+
+	return nil
+}
+
+// Purge removes the value that is deleted with WithSoftDelete from the keyring, with all its pages. It returns
+// ErrNotFound if the key does not exist or is not deleted, the values that are not deleted are left untouched.
+func (ss *KeyringStorage[V]) Purge(service string, key string) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	mu := ss.mutex(service, key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, err := ss.deletedEntry(service, key); err != nil {
+		return ss.notFound(err)
+	}
+
+	if _, err := ss.delete(service, key); err != nil {
+		return ss.notFound(err)
+	}
+
+	ss.releaseEntries(service, key)
+
+	return ss.deletePrevious(service, key)
+}
+
+// markDeleted replaces the main entry of the value with a tombstone that keeps it.
+func (ss *KeyringStorage[V]) markDeleted(service string, key string) (int, error) {
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete data in keyring: %w", err)
+	}
+
+	if isDeleted(d) {
+		return 0, fmt.Errorf("failed to delete data in keyring: %w", ErrNotFound)
+	}
+
+	now := ss.clock.Now()
+
+	// The single entry that is too long with the tombstone is split into pages first, so that the tombstone keeps
+	// only the header.
+	if !isMultipart(d) && len(tombstone(d, now)) > ss.maxLength {
+		if d, err = ss.splitEntry(service, key, d, now); err != nil {
+			return 0, fmt.Errorf("failed to delete data in keyring: %w", err)
+		}
+	}
+
+	pages := 0
+
+	if isMultipart(d) {
+		h, err := parseMultipartHeader(d)
+		if err == nil {
+			pages = h.pages
+		}
+
+		if t := tombstone(d, now); err == nil && len(h.labels) > 0 && len(t) > ss.maxLength {
+			return pages, ss.tombstoneTooLong(len(t))
+		}
+	}
+
+	if err := ss.set(service, key, tombstone(d, now)); err != nil {
+		return pages, fmt.Errorf("failed to delete data in keyring: %w", err)
+	}
+
+	return pages, nil
+}
+
+// splitEntry writes the data of the single entry, with its labels, into pages, and returns the header that the main
+// entry is replaced with. The entry is returned as is, without labels, if the tombstone of the header does not fit
+// either.
+func (ss *KeyringStorage[V]) splitEntry(service string, key string, d string, now time.Time) (string, error) {
+	entry := d
+
+	var labels map[string]string
+
+	if isLabeled(d) {
+		header, data, _ := strings.Cut(d, "\n")
+
+		_, params, err := mime.ParseMediaType(header)
+		if err != nil {
+			return "", &headerError{field: "labels", err: err}
+		}
+
+		expires, _, err := expiryFromParams(params)
+		if err != nil {
+			return "", err
+		}
+
+		labels, d = withExpiryLabel(labelsFromParams(params), expires), data
+	}
+
+	// The entry is checked before it is split, so that it is left unchanged if it can not be marked as deleted.
+	if h, _ := ss.newMultipartHeader(len(d), 0, labels); len(tombstone(h.String(), now)) > ss.maxLength {
+		if len(labels) > 0 {
+			return "", ss.tombstoneTooLong(len(tombstone(h.String(), now)))
+		}
+
+		return entry, nil
+	}
+
+	if err := ss.setMultipart(service, key, d, 0, labels); err != nil {
+		return "", err
+	}
+
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read data from keyring: %w", err)
+	}
+
+	return d, nil
+}
+
+// tombstoneTooLong returns the error of a header whose labels are too long to mark the value as deleted.
+func (ss *KeyringStorage[V]) tombstoneTooLong(length int) error {
+	return fmt.Errorf("%w: the labels are too long to mark the value as deleted: %d, the max length is %d",
+		ErrInvalidLabel, length, ss.maxLength)
+}
+
+// deletedEntry returns the main entry of the value that is deleted, or ErrNotFound if the value is not deleted.
+func (ss *KeyringStorage[V]) deletedEntry(service string, key string) (string, error) {
+	d, err := ss.keyring.Get(service, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read data from keyring: %w", err)
+	}
+
+	if !isDeleted(d) {
+		return "", fmt.Errorf("%w: data is not deleted", ErrNotFound)
+	}
+
+	return untombstone(d), nil
+}
+
+// tombstone marks the main entry of a value as deleted.
+func tombstone(d string, at time.Time) string {
+	return mime.FormatMediaType(mimeDeletedSecret, map[string]string{
+		"deleted": at.UTC().Format(time.RFC3339),
+	}) + "\n" + d
+}
+
+// untombstone returns the main entry of a deleted value, or the data as is if it is not deleted.
+func untombstone(d string) string {
+	if !isDeleted(d) {
+		return d
+	}
+
+	_, d, _ = strings.Cut(d, "\n")
+
+	return d
+}
+
+func isDeleted(d string) bool {
+	return strings.HasPrefix(d, mimeDeletedSecret)
+}
+
+// decodeDeleted reports the deleted data as not found.
+func decodeDeleted(_ keyring.Keyring, _ pageFormat, _ int, _ string, _ string, _ string) (string, error) {
+	return "", fmt.Errorf("failed to read data from keyring: %w", ErrNotFound)
+}
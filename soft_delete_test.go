@@ -0,0 +1,238 @@
+package secretstorage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_SoftDelete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		value    string
+		entries  int
+	}{
+		{
+			scenario: "single entry",
+			value:    "value",
+			entries:  1,
+		},
+		{
+			scenario: "multipart",
+			value:    randString(300),
+			entries:  6,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(k),
+				secretstorage.WithMaxLength(70),
+				secretstorage.WithSoftDelete(),
+			)
+
+			require.NoError(t, s.Set(t.Name(), "key", tc.value))
+			require.NoError(t, s.Delete(t.Name(), "key"))
+
+			_, err := s.Get(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			_, err = s.GetReader(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			// The whole value is retained.
+			entries := k.entries(t.Name())
+
+			assert.Len(t, entries, tc.entries)
+			assert.True(t, strings.HasPrefix(entries["key"], "application/deleted-secret; deleted="))
+
+			err = s.Delete(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			require.NoError(t, s.Undelete(t.Name(), "key"))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, actual)
+
+			err = s.Undelete(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			// Purge removes the deleted values only.
+			err = s.Purge(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			require.NoError(t, s.Delete(t.Name(), "key"))
+			require.NoError(t, s.Purge(t.Name(), "key"))
+
+			assert.Empty(t, k.entries(t.Name()))
+
+			err = s.Purge(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			err = s.Undelete(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+		})
+	}
+}
+
+func TestKeyringStorage_SoftDelete_Set(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(20),
+		secretstorage.WithSoftDelete(),
+	)
+
+	require.NoError(t, s.Set(t.Name(), "key", randString(100)))
+	require.NoError(t, s.Delete(t.Name(), "key"))
+
+	// Set replaces the deleted value, with its pages.
+	require.NoError(t, s.Set(t.Name(), "key", "value"))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	assert.Len(t, k.entries(t.Name()), 1)
+}
+
+func TestKeyringStorage_SoftDelete_Reference(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(newMemoryKeyring()),
+		secretstorage.WithSoftDelete(),
+	)
+
+	require.NoError(t, s.Set("shared", t.Name(), "value"))
+	require.NoError(t, s.SetRef(t.Name(), "alias", "shared", t.Name()))
+	require.NoError(t, s.Delete("shared", t.Name()))
+
+	// The reference to a deleted value is not found, it does not return the tombstone.
+	actual, err := s.Get(t.Name(), "alias")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+	assert.Empty(t, actual)
+
+	_, err = s.GetReader(t.Name(), "alias")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, s.Undelete("shared", t.Name()))
+
+	actual, err = s.Get(t.Name(), "alias")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+}
+
+func TestKeyringStorage_SoftDelete_MaxLength(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		labels   map[string]string
+		expected map[string]string
+	}{
+		{
+			scenario: "single entry",
+			expected: map[string]string{},
+		},
+		{
+			scenario: "labeled entry",
+			labels:   map[string]string{"owner": "alice"},
+			expected: map[string]string{"owner": "alice"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			k := newMemoryKeyring()
+			s := secretstorage.NewKeyringStorage[string](
+				secretstorage.WithKeyring(k),
+				secretstorage.WithMaxLength(200),
+				secretstorage.WithSoftDelete(),
+			)
+
+			// The value fills the single entry, there is no room left for the tombstone.
+			require.NoError(t, s.SetWith(t.Name(), "key", "", secretstorage.WithLabels(tc.labels)))
+
+			value := randString(200 - len(k.entries(t.Name())["key"]))
+
+			require.NoError(t, s.SetWith(t.Name(), "key", value, secretstorage.WithLabels(tc.labels)))
+			require.Len(t, k.entries(t.Name()), 1)
+			require.Len(t, k.entries(t.Name())["key"], 200)
+
+			require.NoError(t, s.Delete(t.Name(), "key"))
+
+			for key, e := range k.entries(t.Name()) {
+				assert.LessOrEqual(t, len(e), 200, key)
+			}
+
+			_, err := s.Get(t.Name(), "key")
+			require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+			require.NoError(t, s.Undelete(t.Name(), "key"))
+
+			actual, err := s.Get(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, value, actual)
+
+			labels, err := s.Labels(t.Name(), "key")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, labels)
+		})
+	}
+}
+
+func TestKeyringStorage_SoftDelete_LabelsTooLong(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(100),
+		secretstorage.WithSoftDelete(),
+	)
+
+	labels := map[string]string{"owner": randString(40)}
+
+	require.NoError(t, s.SetWith(t.Name(), "key", "secret", secretstorage.WithLabels(labels)))
+
+	expected := k.entries(t.Name())
+
+	err := s.Delete(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrInvalidLabel)
+
+	// The entry is not split.
+	assert.Equal(t, expected, k.entries(t.Name()))
+
+	actual, err := s.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", actual)
+}
+
+func TestKeyringStorage_SoftDelete_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithReadOnly())
+
+	require.ErrorIs(t, s.Undelete(t.Name(), "key"), secretstorage.ErrReadOnly)
+	require.ErrorIs(t, s.Purge(t.Name(), "key"), secretstorage.ErrReadOnly)
+}
@@ -0,0 +1,138 @@
+package secretstorage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	_ Storage[any]  = (*SQLStorage[any])(nil)
+	_ Lister        = (*SQLStorage[any])(nil)
+	_ ServiceLister = (*SQLStorage[any])(nil)
+)
+
+// SQLStorage stores the data in a database table with the (service, key, value) columns, where (service, key) is the
+// primary key. The values are marshaled like in the KeyringStorage, and are stored whole since the database does not
+// limit the size of the entries.
+//
+// The queries use the $N placeholders and the ON CONFLICT clause, they work with PostgreSQL and SQLite.
+type SQLStorage[V any] struct {
+	db    *sql.DB
+	table string
+}
+
+// Get gets the value from the database.
+func (s *SQLStorage[V]) Get(service string, key string) (V, error) {
+	var (
+		result V
+		d      string
+	)
+
+	//nolint: gosec
+	err := s.db.QueryRow(`SELECT value FROM `+s.table+` WHERE service = $1 AND key = $2`, service, key).Scan(&d)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrNotFound
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("failed to read data from database: %w", err)
+	}
+
+	if err := unmarshalData(d, &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal data read from database: %w", err)
+	}
+
+	return result, nil
+}
+
+// Set inserts the value in the database, or updates it if it exists.
+func (s *SQLStorage[V]) Set(service string, key string, value V) error {
+	d, err := marshalData(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data for writing to database: %w", tagError(ErrMarshal, err))
+	}
+
+	//nolint: gosec
+	_, err = s.db.Exec(`INSERT INTO `+s.table+` (service, key, value) VALUES ($1, $2, $3) `+
+		`ON CONFLICT (service, key) DO UPDATE SET value = excluded.value`, service, key, d)
+	if err != nil {
+		return fmt.Errorf("failed to write data to database: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes the value in the database.
+func (s *SQLStorage[V]) Delete(service string, key string) error {
+	//nolint: gosec
+	r, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE service = $1 AND key = $2`, service, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete data in database: %w", err)
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete data in database: %w", err)
+	}
+
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List lists the keys of the service, sorted.
+func (s *SQLStorage[V]) List(service string) ([]string, error) {
+	//nolint: gosec
+	keys, err := s.queryStrings(`SELECT key FROM `+s.table+` WHERE service = $1 ORDER BY key`, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data in database: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Services lists the services that have at least one key, sorted.
+func (s *SQLStorage[V]) Services() ([]string, error) {
+	services, err := s.queryStrings(`SELECT DISTINCT service FROM ` + s.table + ` ORDER BY service`) //nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in database: %w", err)
+	}
+
+	return services, nil
+}
+
+// queryStrings runs a query that selects a single column of strings.
+func (s *SQLStorage[V]) queryStrings(query string, args ...any) ([]string, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+
+	defer rows.Close() //nolint: errcheck
+
+	var result []string
+
+	for rows.Next() {
+		var v string
+
+		if err := rows.Scan(&v); err != nil {
+			return nil, err //nolint: wrapcheck
+		}
+
+		result = append(result, v)
+	}
+
+	return result, rows.Err() //nolint: wrapcheck
+}
+
+// NewSQLStorage creates a new storage over a database table. The table is not created, and its name is not escaped, it
+// must come from a trusted source.
+func NewSQLStorage[V any](db *sql.DB, table string) Storage[V] {
+	return &SQLStorage[V]{
+		db:    db,
+		table: table,
+	}
+}
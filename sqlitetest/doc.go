@@ -0,0 +1,3 @@
+// Package sqlitetest tests secretstorage.SQLStorage against SQLite. It is a module on its own, so that the module of
+// secretstorage does not depend on the driver.
+package sqlitetest
@@ -0,0 +1,179 @@
+package sqlitetest_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"go.nhat.io/secretstorage"
+)
+
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	// Every connection has its own in-memory database.
+	db.SetMaxOpenConns(1)
+
+	t.Cleanup(func() {
+		_ = db.Close() //nolint: errcheck
+	})
+
+	_, err = db.Exec(`CREATE TABLE secrets (
+		service TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (service, key)
+	)`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestSQLStorage_String(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewSQLStorage[string](newSQLiteDB(t), "secrets")
+
+	_, err := s.Get("service", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, s.Set("service", "key", "hello"))
+
+	actual, err := s.Get("service", "key")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", actual)
+
+	// Upsert.
+	require.NoError(t, s.Set("service", "key", "world"))
+
+	actual, err = s.Get("service", "key")
+	require.NoError(t, err)
+	assert.Equal(t, "world", actual)
+
+	// Another service.
+	_, err = s.Get("other", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, s.Delete("service", "key"))
+
+	_, err = s.Get("service", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	err = s.Delete("service", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestSQLStorage_LongData(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLiteDB(t)
+	s := secretstorage.NewSQLStorage[string](db, "secrets")
+	data := strings.Repeat("0123456789", 1000)
+
+	require.NoError(t, s.Set("service", "key", data))
+
+	var rows int
+
+	err := db.QueryRow(`SELECT COUNT(*) FROM secrets`).Scan(&rows)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows, "the data is not split")
+
+	actual, err := s.Get("service", "key")
+	require.NoError(t, err)
+	assert.Equal(t, data, actual)
+}
+
+func TestSQLStorage_Types(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLiteDB(t)
+
+	bs := secretstorage.NewSQLStorage[[]byte](db, "secrets")
+
+	require.NoError(t, bs.Set("service", "bytes", []byte("hello")))
+
+	b, err := bs.Get("service", "bytes")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), b)
+
+	cs := secretstorage.NewSQLStorage[*customStruct](db, "secrets")
+
+	require.NoError(t, cs.Set("service", "custom", &customStruct{Name: "John"}))
+
+	c, err := cs.Get("service", "custom")
+	require.NoError(t, err)
+	assert.Equal(t, &customStruct{Name: "John"}, c)
+
+	require.NoError(t, cs.Set("service", "nil", nil))
+
+	c, err = cs.Get("service", "nil")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+}
+
+func TestSQLStorage_UnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewSQLStorage[int](newSQLiteDB(t), "secrets")
+
+	err := s.Set("service", "key", 42)
+	require.ErrorIs(t, err, secretstorage.ErrMarshal)
+	require.ErrorIs(t, err, secretstorage.ErrUnsupportedType)
+}
+
+func TestSQLStorage_MissingTable(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewSQLStorage[string](newSQLiteDB(t), "unknown")
+
+	_, err := s.Get("service", "key")
+	require.ErrorContains(t, err, "failed to read data from database: ")
+
+	err = s.Set("service", "key", "value")
+	require.ErrorContains(t, err, "failed to write data to database: ")
+
+	err = s.Delete("service", "key")
+	require.ErrorContains(t, err, "failed to delete data in database: ")
+}
+
+func TestSQLStorage_Services(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewSQLStorage[string](newSQLiteDB(t), "secrets")
+
+	l, ok := s.(secretstorage.ServiceLister)
+	require.True(t, ok)
+
+	actual, err := l.Services()
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	require.NoError(t, s.Set("b", "key", "value"))
+	require.NoError(t, s.Set("a", "key", "value"))
+	require.NoError(t, s.Set("a", "another key", "value"))
+
+	actual, err = l.Services()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, actual)
+}
+
+type customStruct struct {
+	Name string
+}
+
+func (c customStruct) MarshalText() (text []byte, err error) {
+	return []byte(c.Name), nil
+}
+
+func (c *customStruct) UnmarshalText(text []byte) error {
+	c.Name = string(text)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package secretstorage
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrInvalidSSHKey indicates that the private key of an SSHKey does not parse, or does not decrypt with its passphrase.
+var ErrInvalidSSHKey = errors.New("invalid ssh key")
+
+var (
+	_ encoding.TextMarshaler   = SSHKey{}
+	_ encoding.TextUnmarshaler = (*SSHKey)(nil)
+)
+
+// SSHKey is an SSH private key, in PEM format, and the passphrase that encrypts it, if any. It is stored as a single
+// value, with TextCodec or JSONCodec, the private key and the passphrase are two fields of a JSON object, for example:
+//
+//	s := secretstorage.NewKeyringStorage[secretstorage.SSHKey]()
+//
+//	k, err := secretstorage.NewSSHKey(pemData, passphrase)
+//	// ...
+//
+//	err = s.Set("service", "deploy", k)
+//
+// The value may be longer than the keyring allows, it is split into pages like any other value, see WithMaxLength.
+type SSHKey struct {
+	PrivatePEM string `json:"private_key"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// MarshalText encodes the key and its passphrase.
+func (k SSHKey) MarshalText() ([]byte, error) {
+	type sshKey SSHKey
+
+	return json.Marshal(sshKey(k)) //nolint: wrapcheck
+}
+
+// UnmarshalText decodes the key and its passphrase. The private key is not parsed, see PrivateKey.
+func (k *SSHKey) UnmarshalText(data []byte) error {
+	type sshKey SSHKey
+
+	var v sshKey
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	*k = SSHKey(v)
+
+	return nil
+}
+
+// PrivateKey parses the private key, and decrypts it with the passphrase if it is present. The key is one of the types
+// that ssh.ParseRawPrivateKey returns, such as *rsa.PrivateKey or *ed25519.PrivateKey.
+func (k SSHKey) PrivateKey() (any, error) {
+	if block, _ := pem.Decode([]byte(k.PrivatePEM)); block == nil {
+		return nil, fmt.Errorf("%w: no PEM data is found", ErrInvalidSSHKey)
+	}
+
+	var (
+		key any
+		err error
+	)
+
+	if k.Passphrase == "" {
+		key, err = ssh.ParseRawPrivateKey([]byte(k.PrivatePEM))
+	} else {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase([]byte(k.PrivatePEM), []byte(k.Passphrase))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSSHKey, err)
+	}
+
+	return key, nil
+}
+
+// Signer parses the private key, like PrivateKey, and returns a signer for the SSH connections.
+func (k SSHKey) Signer() (ssh.Signer, error) {
+	key, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSSHKey, err)
+	}
+
+	return signer, nil
+}
+
+// NewSSHKey creates a new SSHKey, and validates that the private key parses, and decrypts with the passphrase if the key
+// is encrypted. The passphrase is empty if the key is not encrypted.
+func NewSSHKey(privatePEM string, passphrase string) (SSHKey, error) {
+	k := SSHKey{PrivatePEM: privatePEM, Passphrase: passphrase}
+
+	if _, err := k.PrivateKey(); err != nil {
+		return SSHKey{}, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,135 @@
+package secretstorage_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"go.nhat.io/secretstorage"
+)
+
+func newSSHPrivateKey(t *testing.T, passphrase string) (ed25519.PrivateKey, string) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var block *pem.Block
+
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, "test")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "test", []byte(passphrase))
+	}
+
+	require.NoError(t, err)
+
+	return key, string(pem.EncodeToMemory(block))
+}
+
+func TestSSHKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario   string
+		passphrase string
+	}{
+		{
+			scenario: "unprotected key",
+		},
+		{
+			scenario:   "passphrase protected key",
+			passphrase: "correct horse battery staple",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			key, privatePEM := newSSHPrivateKey(t, tc.passphrase)
+
+			k, err := secretstorage.NewSSHKey(privatePEM, tc.passphrase)
+			require.NoError(t, err)
+
+			s := secretstorage.NewKeyringStorage[secretstorage.SSHKey](
+				secretstorage.WithKeyring(newMemoryKeyring()),
+				secretstorage.WithMaxLength(64),
+			)
+
+			require.NoError(t, s.Set(t.Name(), "deploy", k))
+
+			actual, err := s.Get(t.Name(), "deploy")
+			require.NoError(t, err)
+
+			assert.Equal(t, k, actual)
+
+			privateKey, err := actual.PrivateKey()
+			require.NoError(t, err)
+
+			assert.Equal(t, &key, privateKey)
+
+			signer, err := actual.Signer()
+			require.NoError(t, err)
+
+			assert.Equal(t, ssh.KeyAlgoED25519, signer.PublicKey().Type())
+		})
+	}
+}
+
+func TestNewSSHKey_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, protected := newSSHPrivateKey(t, "passphrase")
+	_, unprotected := newSSHPrivateKey(t, "")
+
+	testCases := []struct {
+		scenario      string
+		privatePEM    string
+		passphrase    string
+		expectedError string
+	}{
+		{
+			scenario:      "not pem",
+			privatePEM:    "not a key",
+			expectedError: "invalid ssh key: no PEM data is found",
+		},
+		{
+			scenario:      "missing passphrase",
+			privatePEM:    protected,
+			expectedError: "invalid ssh key: ssh: this private key is passphrase protected",
+		},
+		{
+			scenario:      "wrong passphrase",
+			privatePEM:    protected,
+			passphrase:    "wrong",
+			expectedError: "invalid ssh key: x509: decryption password incorrect",
+		},
+		{
+			scenario:      "passphrase of an unprotected key",
+			privatePEM:    unprotected,
+			passphrase:    "passphrase",
+			expectedError: "invalid ssh key: ssh: key is not password protected",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := secretstorage.NewSSHKey(tc.privatePEM, tc.passphrase)
+
+			require.ErrorIs(t, err, secretstorage.ErrInvalidSSHKey)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
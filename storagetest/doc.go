@@ -0,0 +1,2 @@
+// Package storagetest provides utilities for testing the code that uses the secretstorage package.
+package storagetest
@@ -0,0 +1,222 @@
+package storagetest
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+// ErrInjectedFault is the error of the calls that FaultyKeyring fails, unless Faults.Err is set.
+var ErrInjectedFault = errors.New("injected fault")
+
+var (
+	_ keyring.Keyring             = (*FaultyKeyring)(nil)
+	_ secretstorage.Lister        = (*FaultyKeyring)(nil)
+	_ secretstorage.ServiceLister = (*FaultyKeyring)(nil)
+)
+
+// Operation is a method of the keyring, see Faults.
+type Operation string
+
+// The operations of the keyring.
+const (
+	OperationGet       Operation = "get"
+	OperationSet       Operation = "set"
+	OperationDelete    Operation = "delete"
+	OperationDeleteAll Operation = "delete_all"
+	OperationList      Operation = "list"
+)
+
+// LatencyDistribution draws the latency of a call from the random source.
+type LatencyDistribution func(r *rand.Rand) time.Duration
+
+// FixedLatency delays every call by d.
+func FixedLatency(d time.Duration) LatencyDistribution {
+	return func(*rand.Rand) time.Duration {
+		return d
+	}
+}
+
+// UniformLatency delays the calls by a duration drawn uniformly between min and max, inclusive.
+func UniformLatency(min, max time.Duration) LatencyDistribution { //nolint: predeclared
+	return func(r *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+
+		return min + time.Duration(r.Int63n(int64(max-min)+1))
+	}
+}
+
+// Faults are the faults that FaultyKeyring injects into an operation.
+type Faults struct {
+	// Latency delays the calls, whether they fail or not. The calls are not delayed if it is nil.
+	Latency LatencyDistribution
+	// FailureRate is the probability, from 0 to 1, that a call fails without reaching the keyring.
+	FailureRate float64
+	// Err is the error of the failed calls, ErrInjectedFault if it is nil.
+	Err error
+}
+
+// FaultyKeyring decorates a keyring with artificial latency and random failures, configured per operation, to test how
+// the storage and its options, such as secretstorage.WithCircuitBreaker, behave when the keyring misbehaves. It is used
+// with secretstorage.WithKeyring.
+//
+// The faults are drawn from a random source that is seeded with WithSeed, so the same sequence of calls gets the same
+// faults. The calls that run concurrently draw in the order they are made.
+type FaultyKeyring struct {
+	keyring.Keyring
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	faults map[Operation]Faults
+}
+
+func (k *FaultyKeyring) inject(op Operation) error {
+	f, ok := k.faults[op]
+	if !ok {
+		return nil
+	}
+
+	k.mu.Lock()
+
+	var latency time.Duration
+
+	if f.Latency != nil {
+		latency = f.Latency(k.rand)
+	}
+
+	fail := f.FailureRate > 0 && k.rand.Float64() < f.FailureRate
+
+	k.mu.Unlock()
+
+	time.Sleep(latency)
+
+	if !fail {
+		return nil
+	}
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	return ErrInjectedFault
+}
+
+// Get gets the secret, or fails if a fault is injected.
+func (k *FaultyKeyring) Get(service, user string) (string, error) {
+	if err := k.inject(OperationGet); err != nil {
+		return "", err
+	}
+
+	return k.Keyring.Get(service, user) //nolint: wrapcheck
+}
+
+// Set sets the secret, or fails if a fault is injected.
+func (k *FaultyKeyring) Set(service, user, password string) error {
+	if err := k.inject(OperationSet); err != nil {
+		return err
+	}
+
+	return k.Keyring.Set(service, user, password) //nolint: wrapcheck
+}
+
+// Delete deletes the secret, or fails if a fault is injected.
+func (k *FaultyKeyring) Delete(service, user string) error {
+	if err := k.inject(OperationDelete); err != nil {
+		return err
+	}
+
+	return k.Keyring.Delete(service, user) //nolint: wrapcheck
+}
+
+// DeleteAll deletes all the secrets of the service, or fails if a fault is injected.
+func (k *FaultyKeyring) DeleteAll(service string) error {
+	if err := k.inject(OperationDeleteAll); err != nil {
+		return err
+	}
+
+	return k.Keyring.DeleteAll(service) //nolint: wrapcheck
+}
+
+// List lists the keys of the service if the keyring implements secretstorage.Lister, or fails if a fault is injected.
+func (k *FaultyKeyring) List(service string) ([]string, error) {
+	l, ok := k.Keyring.(secretstorage.Lister)
+	if !ok {
+		return nil, secretstorage.ErrListingNotSupported
+	}
+
+	if err := k.inject(OperationList); err != nil {
+		return nil, err
+	}
+
+	return l.List(service) //nolint: wrapcheck
+}
+
+// Services lists the services if the keyring implements secretstorage.ServiceLister, or fails if a fault is injected
+// into OperationList.
+func (k *FaultyKeyring) Services() ([]string, error) {
+	l, ok := k.Keyring.(secretstorage.ServiceLister)
+	if !ok {
+		return nil, secretstorage.ErrListingNotSupported
+	}
+
+	if err := k.inject(OperationList); err != nil {
+		return nil, err
+	}
+
+	return l.Services() //nolint: wrapcheck
+}
+
+// NewFaultyKeyring creates a new FaultyKeyring that decorates the keyring. Without options, no fault is injected.
+func NewFaultyKeyring(k keyring.Keyring, opts ...FaultyKeyringOption) *FaultyKeyring {
+	fk := &FaultyKeyring{
+		Keyring: k,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())), //nolint: gosec
+		faults:  make(map[Operation]Faults),
+	}
+
+	for _, opt := range opts {
+		opt.applyFaultyKeyringOption(fk)
+	}
+
+	return fk
+}
+
+// FaultyKeyringOption is an option to configure FaultyKeyring.
+type FaultyKeyringOption interface {
+	applyFaultyKeyringOption(k *FaultyKeyring)
+}
+
+type faultyKeyringOptionFunc func(k *FaultyKeyring)
+
+func (f faultyKeyringOptionFunc) applyFaultyKeyringOption(k *FaultyKeyring) {
+	f(k)
+}
+
+// WithSeed seeds the random source of the faults, so that they are reproducible. The source is seeded with the current
+// time by default.
+func WithSeed(seed int64) FaultyKeyringOption {
+	return faultyKeyringOptionFunc(func(k *FaultyKeyring) {
+		k.rand = rand.New(rand.NewSource(seed)) //nolint: gosec
+	})
+}
+
+// WithFaults injects the faults into the operations, or into all of them if none is given. The later options override
+// the earlier ones for the same operation.
+func WithFaults(f Faults, ops ...Operation) FaultyKeyringOption {
+	if len(ops) == 0 {
+		ops = []Operation{OperationGet, OperationSet, OperationDelete, OperationDeleteAll, OperationList}
+	}
+
+	return faultyKeyringOptionFunc(func(k *FaultyKeyring) {
+		for _, op := range ops {
+			k.faults[op] = f
+		}
+	})
+}
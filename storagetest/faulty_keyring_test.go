@@ -0,0 +1,138 @@
+package storagetest_test
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/storagetest"
+)
+
+func newFileKeyring(t *testing.T) *secretstorage.FileKeyring {
+	t.Helper()
+
+	k, err := secretstorage.NewFileKeyring(filepath.Join(t.TempDir(), "secrets"), []byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	return k
+}
+
+func TestFaultyKeyring_NoFaults(t *testing.T) {
+	t.Parallel()
+
+	k := storagetest.NewFaultyKeyring(newFileKeyring(t))
+
+	require.NoError(t, k.Set(t.Name(), "key", "value"))
+
+	actual, err := k.Get(t.Name(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	keys, err := k.List(t.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+
+	require.NoError(t, k.Delete(t.Name(), "key"))
+
+	_, err = k.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestFaultyKeyring_Seeded(t *testing.T) {
+	t.Parallel()
+
+	failures := func(seed int64) []bool {
+		k := storagetest.NewFaultyKeyring(newFileKeyring(t),
+			storagetest.WithSeed(seed),
+			storagetest.WithFaults(storagetest.Faults{FailureRate: 0.5}),
+		)
+
+		result := make([]bool, 50)
+
+		for i := range result {
+			_, err := k.Get(t.Name(), "key")
+
+			result[i] = errors.Is(err, storagetest.ErrInjectedFault)
+		}
+
+		return result
+	}
+
+	expected := failures(42)
+
+	assert.Equal(t, expected, failures(42))
+	assert.Contains(t, expected, true)
+	assert.Contains(t, expected, false)
+}
+
+func TestFaultyKeyring_PerOperation(t *testing.T) {
+	t.Parallel()
+
+	k := storagetest.NewFaultyKeyring(newFileKeyring(t),
+		storagetest.WithFaults(storagetest.Faults{FailureRate: 1, Err: assert.AnError}, storagetest.OperationSet),
+	)
+
+	err := k.Set(t.Name(), "key", "value")
+	require.ErrorIs(t, err, assert.AnError)
+
+	_, err = k.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+}
+
+func TestFaultyKeyring_Latency(t *testing.T) {
+	t.Parallel()
+
+	k := storagetest.NewFaultyKeyring(newFileKeyring(t),
+		storagetest.WithFaults(storagetest.Faults{Latency: storagetest.FixedLatency(20 * time.Millisecond)}, storagetest.OperationGet),
+	)
+
+	start := time.Now()
+
+	_, err := k.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestUniformLatency(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(42)) //nolint: gosec
+	latency := storagetest.UniformLatency(time.Millisecond, 3*time.Millisecond)
+
+	for i := 0; i < 100; i++ {
+		d := latency(r)
+
+		assert.GreaterOrEqual(t, d, time.Millisecond)
+		assert.LessOrEqual(t, d, 3*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Second, storagetest.UniformLatency(time.Second, time.Second)(r))
+}
+
+func TestFaultyKeyring_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	k := storagetest.NewFaultyKeyring(newFileKeyring(t),
+		storagetest.WithFaults(storagetest.Faults{FailureRate: 1}, storagetest.OperationGet),
+	)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithCircuitBreaker(3, time.Hour),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Get(t.Name(), "key")
+		require.ErrorIs(t, err, storagetest.ErrInjectedFault)
+	}
+
+	_, err := s.Get(t.Name(), "key")
+	require.ErrorIs(t, err, secretstorage.ErrCircuitOpen)
+}
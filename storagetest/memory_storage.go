@@ -0,0 +1,69 @@
+package storagetest
+
+import (
+	"sync"
+
+	"go.nhat.io/secretstorage"
+)
+
+var _ secretstorage.Storage[any] = (*MemoryStorage[any])(nil)
+
+// MemoryStorage is a storage that keeps the values in memory. It is the backend to test the decorators of
+// secretstorage.Storage, such as secretstorage.EncryptedStorage, without a keyring. The misses fail with
+// secretstorage.ErrNotFound.
+type MemoryStorage[V any] struct {
+	values map[string]map[string]V
+	mu     sync.RWMutex
+}
+
+// Get gets the value.
+func (s *MemoryStorage[V]) Get(service string, key string) (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.values[service][key]
+	if !ok {
+		var zero V
+
+		return zero, secretstorage.ErrNotFound
+	}
+
+	return v, nil
+}
+
+// Set sets the value.
+func (s *MemoryStorage[V]) Set(service string, key string, value V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values[service] == nil {
+		s.values[service] = make(map[string]V)
+	}
+
+	s.values[service][key] = value
+
+	return nil
+}
+
+// Delete deletes the value.
+func (s *MemoryStorage[V]) Delete(service string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[service][key]; !ok {
+		return secretstorage.ErrNotFound
+	}
+
+	delete(s.values[service], key)
+
+	if len(s.values[service]) == 0 {
+		delete(s.values, service)
+	}
+
+	return nil
+}
+
+// NewMemoryStorage creates a new empty MemoryStorage.
+func NewMemoryStorage[V any]() *MemoryStorage[V] {
+	return &MemoryStorage[V]{values: make(map[string]map[string]V)}
+}
@@ -0,0 +1,53 @@
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+// TestNotFound asserts that the storage reports the misses with secretstorage.ErrNotFound, so that the callers can
+// tell them apart from the failures with errors.Is. It is meant for the decorators, to make sure that they do not
+// swallow or replace the error of the storage that they wrap, see MemoryStorage.
+//
+// Get and Delete must fail with secretstorage.ErrNotFound for a key that was never set, and for a key that was set and
+// then deleted. The value is the one that is set, it must be accepted by the storage. The keys are in a service named
+// after the test, the storage should not be shared with the other tests.
+func TestNotFound[V any](t *testing.T, s secretstorage.Storage[V], value V) {
+	t.Helper()
+
+	service := t.Name()
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := s.Get(service, "missing")
+
+		assert.ErrorIs(t, err, secretstorage.ErrNotFound)
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		err := s.Delete(service, "missing")
+
+		assert.ErrorIs(t, err, secretstorage.ErrNotFound)
+	})
+
+	t.Run("get deleted", func(t *testing.T) {
+		require.NoError(t, s.Set(service, "deleted", value))
+		require.NoError(t, s.Delete(service, "deleted"))
+
+		_, err := s.Get(service, "deleted")
+
+		assert.ErrorIs(t, err, secretstorage.ErrNotFound)
+	})
+
+	t.Run("delete deleted", func(t *testing.T) {
+		require.NoError(t, s.Set(service, "deleted twice", value))
+		require.NoError(t, s.Delete(service, "deleted twice"))
+
+		err := s.Delete(service, "deleted twice")
+
+		assert.ErrorIs(t, err, secretstorage.ErrNotFound)
+	})
+}
@@ -0,0 +1,87 @@
+package storagetest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/storagetest"
+)
+
+func TestNotFound_MemoryStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.TestNotFound[string](t, storagetest.NewMemoryStorage[string](), "value")
+}
+
+func TestNotFound_Decorators(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		storage  func(t *testing.T) secretstorage.Storage[string]
+	}{
+		{
+			scenario: "encrypted storage",
+			storage: func(*testing.T) secretstorage.Storage[string] {
+				return secretstorage.NewEncryptedStorage[string](storagetest.NewMemoryStorage[string](), []byte("passphrase"))
+			},
+		},
+		{
+			scenario: "validating storage",
+			storage: func(*testing.T) secretstorage.Storage[string] {
+				return secretstorage.NewValidatingStorage[string](storagetest.NewMemoryStorage[string](), func(string) error {
+					return nil
+				}, secretstorage.WithValidationOnGet[string]())
+			},
+		},
+		{
+			scenario: "serialized storage",
+			storage: func(*testing.T) secretstorage.Storage[string] {
+				return secretstorage.NewSerializedStorage[string](storagetest.NewMemoryStorage[string]())
+			},
+		},
+		{
+			scenario: "tee storage",
+			storage: func(*testing.T) secretstorage.Storage[string] {
+				return secretstorage.NewTeeStorage[string](
+					storagetest.NewMemoryStorage[string](),
+					storagetest.NewMemoryStorage[string](),
+					secretstorage.WithMismatchHandler[string](func(string, string, string, string) {}),
+				)
+			},
+		},
+		{
+			scenario: "keyring storage",
+			storage: func(t *testing.T) secretstorage.Storage[string] {
+				return secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newFileKeyring(t)))
+			},
+		},
+		{
+			scenario: "keyring storage with faulty keyring",
+			storage: func(t *testing.T) secretstorage.Storage[string] {
+				return secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(storagetest.NewFaultyKeyring(newFileKeyring(t))))
+			},
+		},
+		{
+			scenario: "keyring storage with encrypted keyring",
+			storage: func(t *testing.T) secretstorage.Storage[string] {
+				k, err := secretstorage.NewEncryptedKeyring(newFileKeyring(t), []byte("0123456789abcdef"))
+				require.NoError(t, err)
+
+				return secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			storagetest.TestNotFound[string](t, tc.storage(t), "value")
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/multierr"
+)
+
+// WithAtomicSwap changes the way the values are replaced. By default, the old value is deleted before the new one is
+// written, so a reader in another process could see the value as missing in the meantime. With the atomic swap, the
+// pages of the new value are written next to the old ones, then the header is swapped, and the old pages are deleted
+// at last. The old value stays readable until the new one is fully written.
+//
+// The drawback is that the keyring temporarily holds the pages of both values.
+func WithAtomicSwap() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withAtomicSwap()
+	})
+}
+
+// swapRaw writes the new data without deleting the old one first, and deletes the old pages once the new data is fully
+// written.
+func (ss *KeyringStorage[V]) swapRaw(service string, key string, d string, labels map[string]string) error {
+	var (
+		old      multipartHeader
+		hasPages bool
+	)
+
+	o, err := ss.keyring.Get(service, key)
+	o = untombstone(o)
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+
+	case err != nil:
+		return fmt.Errorf("failed to read old data from keyring: %w", tagError(ErrKeyringWrite, err))
+
+	case isMultipart(o):
+		if old, err = parseMultipartHeader(o); err != nil {
+			return fmt.Errorf("failed to read old data from keyring: %w", tagError(ErrKeyringWrite, err))
+		}
+
+		hasPages = true
+	}
+
+	// Alternate the generation so that the new pages do not overwrite the old ones.
+	generation := 0
+	if hasPages && old.generation == 0 {
+		generation = 1
+	}
+
+	err = ss.write(service, key, d, generation, labels)
+
+	if err != nil || !hasPages {
+		return err
+	}
+
+	if old.checkPages(ss.pageFormat) != nil {
+		// The number of pages of a corrupt header can not be trusted, the old pages are deleted until one is not found.
+		err = ss.deleteFoundPages(service, key, old)
+	} else {
+		for i := 1; i <= old.pages; i++ {
+			if dErr := ss.keyring.Delete(service, old.pageKey(ss.pageFormat, key, i)); dErr != nil && !errors.Is(dErr, ErrNotFound) {
+				err = multierr.Append(err, fmt.Errorf("failed to delete multipart data #%d in keyring: %w", i, dErr))
+			}
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to delete old data in keyring: %w", tagError(ErrKeyringWrite, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package secretstorage_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+func TestKeyringStorage_AtomicSwap_HeaderIsSwappedAfterPages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, args.String(1))
+	}
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("application/multipart-secret; pages=2", nil)
+
+		k.On("Set", t.Name(), mock.Anything, mock.Anything).
+			Run(record).
+			Return(nil)
+
+		k.On("Delete", t.Name(), mock.Anything).
+			Run(record).
+			Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	err := s.Set(t.Name(), "key", randString(6139))
+	require.NoError(t, err)
+
+	expected := []string{
+		"key~1-0001",
+		"key~1-0002",
+		"key~1-0003",
+		"key",
+		"key-0001",
+		"key-0002",
+	}
+
+	assert.Equal(t, expected, calls)
+	k.AssertCalled(t, "Set", t.Name(), "key", "application/multipart-secret; generation=1; pages=3")
+}
+
+func TestKeyringStorage_AtomicSwap_Success(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	testCases := []struct {
+		length   int
+		expected []string
+	}{
+		{
+			length:   6139,
+			expected: []string{"key", "key-0001", "key-0002", "key-0003"},
+		},
+		{
+			length:   3000,
+			expected: []string{"key", "key~1-0001", "key~1-0002"},
+		},
+		{
+			length:   5000,
+			expected: []string{"key", "key-0001", "key-0002", "key-0003"},
+		},
+		{
+			length:   128,
+			expected: []string{"key"},
+		},
+		{
+			length:   3000,
+			expected: []string{"key", "key-0001", "key-0002"},
+		},
+	}
+
+	for _, tc := range testCases {
+		value := randString(tc.length)
+
+		require.NoError(t, s.Set(t.Name(), "key", value))
+
+		entries, err := k.List(t.Name())
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, entries)
+
+		actual, err := s.Get(t.Name(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, value, actual)
+
+		all, err := s.GetAll(t.Name())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"key": value}, all)
+	}
+
+	require.NoError(t, s.Delete(t.Name(), "key"))
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_AtomicSwap_Failure_OldDataIsKept(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("application/multipart-secret; pages=2", nil)
+
+		k.On("Set", t.Name(), "key~1-0001", mock.Anything).
+			Return(nil)
+
+		k.On("Set", t.Name(), "key~1-0002", mock.Anything).
+			Return(assert.AnError)
+
+		k.On("Delete", t.Name(), "key~1-0001").
+			Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	err := s.Set(t.Name(), "key", randString(6139))
+
+	require.EqualError(t, err, "failed to write multipart data #2 to keyring: assert.AnError general error for testing")
+	require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
+}
+
+func TestKeyringStorage_AtomicSwap_Failure_CouldNotReadOldData(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("", assert.AnError)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	err := s.Set(t.Name(), "key", "value")
+
+	require.EqualError(t, err, "failed to read old data from keyring: assert.AnError general error for testing")
+	require.ErrorIs(t, err, secretstorage.ErrKeyringWrite)
+}
+
+func TestKeyringStorage_AtomicSwap_Failure_CouldNotDeleteOldPages(t *testing.T) {
+	t.Parallel()
+
+	k := mock.MockKeyring(func(k *mock.Keyring) {
+		k.On("Get", t.Name(), "key").
+			Return("application/multipart-secret; generation=1; pages=2", nil)
+
+		k.On("Set", t.Name(), "key", "value").
+			Return(nil)
+
+		k.On("Delete", t.Name(), "key~1-0001").
+			Return(assert.AnError)
+
+		k.On("Delete", t.Name(), "key~1-0002").
+			Return(nil)
+	})(t)
+
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithAtomicSwap(),
+	)
+
+	err := s.Set(t.Name(), "key", "value")
+
+	require.EqualError(t, err, "failed to delete old data in keyring: failed to delete multipart data #1 in keyring: assert.AnError general error for testing")
+}
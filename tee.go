@@ -0,0 +1,122 @@
+package secretstorage
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	_ Storage[any] = (*TeeStorage[any])(nil)
+	_ Flusher      = (*TeeStorage[any])(nil)
+)
+
+// TeeStorage mirrors the writes of a primary storage to a secondary storage, for example to evaluate a new backend
+// without risking the production reads. The errors of the secondary storage are reported, but never returned.
+type TeeStorage[V any] struct {
+	primary   Storage[V]
+	secondary Storage[V]
+
+	onSecondaryError func(op, service, key string, err error)
+	onMismatch       func(service, key string, primary, secondary V)
+}
+
+// Get gets the value from the primary storage. If a mismatch handler is configured, the value is also read from the
+// secondary storage and compared.
+func (s *TeeStorage[V]) Get(service string, key string) (V, error) {
+	v, err := s.primary.Get(service, key)
+	if err != nil || s.onMismatch == nil {
+		return v, err //nolint: wrapcheck
+	}
+
+	sv, sErr := s.secondary.Get(service, key)
+	if sErr != nil {
+		s.reportSecondaryError("get", service, key, sErr)
+
+		return v, nil
+	}
+
+	if !reflect.DeepEqual(v, sv) {
+		s.onMismatch(service, key, v, sv)
+	}
+
+	return v, nil
+}
+
+// Set sets the value in the primary storage, and then in the secondary storage.
+func (s *TeeStorage[V]) Set(service string, key string, value V) error {
+	if err := s.primary.Set(service, key, value); err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	if err := s.secondary.Set(service, key, value); err != nil {
+		s.reportSecondaryError("set", service, key, err)
+	}
+
+	return nil
+}
+
+// Delete deletes the value in the primary storage, and then in the secondary storage.
+func (s *TeeStorage[V]) Delete(service string, key string) error {
+	if err := s.primary.Delete(service, key); err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	if err := s.secondary.Delete(service, key); err != nil {
+		s.reportSecondaryError("delete", service, key, err)
+	}
+
+	return nil
+}
+
+// Flush flushes both the primary and the secondary storages, the errors are returned together.
+func (s *TeeStorage[V]) Flush() error {
+	return multierr.Append(flush(s.primary), flush(s.secondary))
+}
+
+func (s *TeeStorage[V]) reportSecondaryError(op, service, key string, err error) {
+	if s.onSecondaryError != nil {
+		s.onSecondaryError(op, service, key, fmt.Errorf("secondary storage: %w", err))
+	}
+}
+
+// NewTeeStorage creates a new TeeStorage.
+func NewTeeStorage[V any](primary, secondary Storage[V], opts ...TeeStorageOption[V]) *TeeStorage[V] {
+	s := &TeeStorage[V]{
+		primary:   primary,
+		secondary: secondary,
+	}
+
+	for _, opt := range opts {
+		opt.applyTeeStorageOption(s)
+	}
+
+	return s
+}
+
+// TeeStorageOption is an option to configure TeeStorage.
+type TeeStorageOption[V any] interface {
+	applyTeeStorageOption(s *TeeStorage[V])
+}
+
+type teeStorageOptionFunc[V any] func(s *TeeStorage[V])
+
+func (f teeStorageOptionFunc[V]) applyTeeStorageOption(s *TeeStorage[V]) {
+	f(s)
+}
+
+// WithSecondaryErrorHandler sets the handler for the errors of the secondary storage.
+func WithSecondaryErrorHandler[V any](fn func(op, service, key string, err error)) TeeStorageOption[V] {
+	return teeStorageOptionFunc[V](func(s *TeeStorage[V]) {
+		s.onSecondaryError = fn
+	})
+}
+
+// WithMismatchHandler enables the comparison of the values read from the primary and the secondary storages, and sets
+// the handler for the mismatches.
+func WithMismatchHandler[V any](fn func(service, key string, primary, secondary V)) TeeStorageOption[V] {
+	return teeStorageOptionFunc[V](func(s *TeeStorage[V]) {
+		s.onMismatch = fn
+	})
+}
@@ -0,0 +1,268 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+type teeReport struct {
+	op      string
+	service string
+	key     string
+	err     string
+}
+
+func TestTeeStorage_Get(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario           string
+		mockPrimary        mock.StorageMocker[string]
+		mockSecondary      mock.StorageMocker[string]
+		compare            bool
+		expectedResult     string
+		expectedError      string
+		expectedReports    []teeReport
+		expectedMismatches [][2]string
+	}{
+		{
+			scenario: "primary error is returned",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("", assert.AnError)
+			}),
+			mockSecondary: mock.MockStorage[string](),
+			compare:       true,
+			expectedError: "assert.AnError general error for testing",
+		},
+		{
+			scenario: "no comparison",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("value", nil)
+			}),
+			mockSecondary:  mock.MockStorage[string](),
+			expectedResult: "value",
+		},
+		{
+			scenario: "secondary error is reported",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("value", nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("", assert.AnError)
+			}),
+			compare:        true,
+			expectedResult: "value",
+			expectedReports: []teeReport{
+				{op: "get", service: "service", key: "key", err: "secondary storage: assert.AnError general error for testing"},
+			},
+		},
+		{
+			scenario: "mismatch is reported",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("value", nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("another value", nil)
+			}),
+			compare:            true,
+			expectedResult:     "value",
+			expectedMismatches: [][2]string{{"value", "another value"}},
+		},
+		{
+			scenario: "match",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("value", nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("value", nil)
+			}),
+			compare:        true,
+			expectedResult: "value",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				reports    []teeReport
+				mismatches [][2]string
+			)
+
+			opts := []secretstorage.TeeStorageOption[string]{
+				secretstorage.WithSecondaryErrorHandler[string](func(op, service, key string, err error) {
+					reports = append(reports, teeReport{op: op, service: service, key: key, err: err.Error()})
+				}),
+			}
+
+			if tc.compare {
+				opts = append(opts, secretstorage.WithMismatchHandler(func(_, _ string, primary, secondary string) {
+					mismatches = append(mismatches, [2]string{primary, secondary})
+				}))
+			}
+
+			s := secretstorage.NewTeeStorage[string](tc.mockPrimary(t), tc.mockSecondary(t), opts...)
+
+			actual, err := s.Get("service", "key")
+
+			assert.Equal(t, tc.expectedResult, actual)
+			assert.Equal(t, tc.expectedReports, reports)
+			assert.Equal(t, tc.expectedMismatches, mismatches)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestTeeStorage_Set(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario        string
+		mockPrimary     mock.StorageMocker[string]
+		mockSecondary   mock.StorageMocker[string]
+		expectedError   string
+		expectedReports []teeReport
+	}{
+		{
+			scenario: "primary error is fatal",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(assert.AnError)
+			}),
+			mockSecondary: mock.MockStorage[string](),
+			expectedError: "assert.AnError general error for testing",
+		},
+		{
+			scenario: "secondary error is reported",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(assert.AnError)
+			}),
+			expectedReports: []teeReport{
+				{op: "set", service: "service", key: "key", err: "secondary storage: assert.AnError general error for testing"},
+			},
+		},
+		{
+			scenario: "success",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(nil)
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var reports []teeReport
+
+			s := secretstorage.NewTeeStorage[string](tc.mockPrimary(t), tc.mockSecondary(t),
+				secretstorage.WithSecondaryErrorHandler[string](func(op, service, key string, err error) {
+					reports = append(reports, teeReport{op: op, service: service, key: key, err: err.Error()})
+				}),
+			)
+
+			err := s.Set("service", "key", "value")
+
+			assert.Equal(t, tc.expectedReports, reports)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestTeeStorage_Delete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario        string
+		mockPrimary     mock.StorageMocker[string]
+		mockSecondary   mock.StorageMocker[string]
+		expectedError   string
+		expectedReports []teeReport
+	}{
+		{
+			scenario: "primary error is fatal",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Delete", "service", "key").Return(assert.AnError)
+			}),
+			mockSecondary: mock.MockStorage[string](),
+			expectedError: "assert.AnError general error for testing",
+		},
+		{
+			scenario: "secondary error is reported",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Delete", "service", "key").Return(nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Delete", "service", "key").Return(assert.AnError)
+			}),
+			expectedReports: []teeReport{
+				{op: "delete", service: "service", key: "key", err: "secondary storage: assert.AnError general error for testing"},
+			},
+		},
+		{
+			scenario: "secondary error without handler",
+			mockPrimary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Delete", "service", "key").Return(nil)
+			}),
+			mockSecondary: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Delete", "service", "key").Return(assert.AnError)
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				reports []teeReport
+				opts    []secretstorage.TeeStorageOption[string]
+			)
+
+			if tc.expectedReports != nil {
+				opts = append(opts, secretstorage.WithSecondaryErrorHandler[string](func(op, service, key string, err error) {
+					reports = append(reports, teeReport{op: op, service: service, key: key, err: err.Error()})
+				}))
+			}
+
+			s := secretstorage.NewTeeStorage[string](tc.mockPrimary(t), tc.mockSecondary(t), opts...)
+
+			err := s.Delete("service", "key")
+
+			assert.Equal(t, tc.expectedReports, reports)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
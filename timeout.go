@@ -0,0 +1,179 @@
+package secretstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+func (ss *KeyringStorage[V]) withOperationTimeout(d time.Duration) {
+	ss.timeouts.operation = d
+}
+
+func (ss *KeyringStorage[V]) withGetTimeout(d time.Duration) {
+	ss.timeouts.get = d
+}
+
+func (ss *KeyringStorage[V]) withSetTimeout(d time.Duration) {
+	ss.timeouts.set = d
+}
+
+func (ss *KeyringStorage[V]) withDeleteTimeout(d time.Duration) {
+	ss.timeouts.delete = d
+}
+
+// WithOperationTimeout limits the duration of every call to the keyring, the calls that take longer fail with
+// context.DeadlineExceeded. The timeout applies to each call, so to each page of a multipart value, and not to the
+// whole operation. It is overridden by WithGetTimeout, WithSetTimeout and WithDeleteTimeout.
+//
+// The keyrings that do not implement ContextKeyring are not canceled, their calls are abandoned when they time out.
+func WithOperationTimeout(d time.Duration) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withOperationTimeout(d)
+	})
+}
+
+// WithGetTimeout limits the duration of the reads of the keyring, including the listings, like WithOperationTimeout.
+func WithGetTimeout(d time.Duration) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withGetTimeout(d)
+	})
+}
+
+// WithSetTimeout limits the duration of the writes of the keyring, like WithOperationTimeout.
+func WithSetTimeout(d time.Duration) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withSetTimeout(d)
+	})
+}
+
+// WithDeleteTimeout limits the duration of the deletions of the keyring, like WithOperationTimeout.
+func WithDeleteTimeout(d time.Duration) KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withDeleteTimeout(d)
+	})
+}
+
+// operationTimeouts are the timeouts of the calls to the keyring, a specific timeout overrides the operation timeout.
+type operationTimeouts struct {
+	operation time.Duration
+	get       time.Duration
+	set       time.Duration
+	delete    time.Duration
+}
+
+func (t operationTimeouts) enabled() bool {
+	return t.operation > 0 || t.get > 0 || t.set > 0 || t.delete > 0
+}
+
+func (t operationTimeouts) negative() bool {
+	return t.operation < 0 || t.get < 0 || t.set < 0 || t.delete < 0
+}
+
+// resolve returns the timeouts with the operation timeout in place of the unset ones.
+func (t operationTimeouts) resolve() operationTimeouts {
+	for _, d := range []*time.Duration{&t.get, &t.set, &t.delete} {
+		if *d == 0 {
+			*d = t.operation
+		}
+	}
+
+	return t
+}
+
+var (
+	_ keyring.Keyring = (*timeoutKeyring)(nil)
+	_ ContextKeyring  = (*timeoutKeyring)(nil)
+	_ Lister          = (*timeoutKeyring)(nil)
+	_ ServiceLister   = (*timeoutKeyring)(nil)
+)
+
+// timeoutKeyring limits the duration of the calls to the keyring. It implements ContextKeyring, so that the timeouts
+// also apply under the context of the storage, see GetContext.
+type timeoutKeyring struct {
+	keyring.Keyring
+
+	timeouts operationTimeouts
+}
+
+func (k *timeoutKeyring) Get(service, user string) (string, error) {
+	return k.GetContext(context.Background(), service, user)
+}
+
+func (k *timeoutKeyring) GetContext(ctx context.Context, service, user string) (string, error) {
+	return withTimeout(ctx, k.Keyring, k.timeouts.get, func(k keyring.Keyring) (string, error) {
+		return k.Get(service, user)
+	})
+}
+
+func (k *timeoutKeyring) Set(service, user, password string) error {
+	return k.SetContext(context.Background(), service, user, password)
+}
+
+func (k *timeoutKeyring) SetContext(ctx context.Context, service, user, password string) error {
+	_, err := withTimeout(ctx, k.Keyring, k.timeouts.set, func(k keyring.Keyring) (struct{}, error) {
+		return struct{}{}, k.Set(service, user, password)
+	})
+
+	return err
+}
+
+func (k *timeoutKeyring) Delete(service, user string) error {
+	return k.DeleteContext(context.Background(), service, user)
+}
+
+func (k *timeoutKeyring) DeleteContext(ctx context.Context, service, user string) error {
+	_, err := withTimeout(ctx, k.Keyring, k.timeouts.delete, func(k keyring.Keyring) (struct{}, error) {
+		return struct{}{}, k.Delete(service, user)
+	})
+
+	return err
+}
+
+func (k *timeoutKeyring) DeleteAll(service string) error {
+	_, err := withTimeout(context.Background(), k.Keyring, k.timeouts.delete, func(k keyring.Keyring) (struct{}, error) {
+		return struct{}{}, k.DeleteAll(service)
+	})
+
+	return err
+}
+
+func (k *timeoutKeyring) List(service string) ([]string, error) {
+	return withTimeout(context.Background(), k.Keyring, k.timeouts.get, func(k keyring.Keyring) ([]string, error) {
+		l, ok := k.(Lister)
+		if !ok {
+			return nil, ErrListingNotSupported
+		}
+
+		return l.List(service)
+	})
+}
+
+func (k *timeoutKeyring) Services() ([]string, error) {
+	return withTimeout(context.Background(), k.Keyring, k.timeouts.get, func(k keyring.Keyring) ([]string, error) {
+		l, ok := k.(ServiceLister)
+		if !ok {
+			return nil, ErrListingNotSupported
+		}
+
+		return l.Services()
+	})
+}
+
+// withTimeout calls the keyring under the context, limited to the timeout if it is set. The keyring is called
+// directly when there is neither a timeout, nor a context, nor options of the backend.
+func withTimeout[T any](ctx context.Context, k keyring.Keyring, d time.Duration, call func(k keyring.Keyring) (T, error)) (T, error) {
+	if _, ok := backendOptions(ctx); !ok && d <= 0 && ctx.Done() == nil {
+		return call(k)
+	}
+
+	if d > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	return call(contextKeyring{Keyring: k, ctx: ctx})
+}
@@ -0,0 +1,162 @@
+package secretstorage_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+// sleepingKeyring is a memoryKeyring that takes the given latency for each operation. Only the reads of the keys with
+// the "get" prefix are slow, so that Set and Delete read the old data quickly.
+type sleepingKeyring struct {
+	*memoryKeyring
+
+	get, set, delete time.Duration
+}
+
+func (k *sleepingKeyring) Get(service, user string) (string, error) {
+	if strings.HasPrefix(user, "get") {
+		time.Sleep(k.get)
+	}
+
+	return k.memoryKeyring.Get(service, user)
+}
+
+func (k *sleepingKeyring) Set(service, user, password string) error {
+	time.Sleep(k.set)
+
+	return k.memoryKeyring.Set(service, user, password)
+}
+
+func (k *sleepingKeyring) Delete(service, user string) error {
+	time.Sleep(k.delete)
+
+	return k.memoryKeyring.Delete(service, user)
+}
+
+func TestKeyringStorage_Timeouts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		slow     = 100 * time.Millisecond
+		short    = 20 * time.Millisecond
+		generous = time.Second
+	)
+
+	testCases := []struct {
+		scenario       string
+		keyring        *sleepingKeyring
+		options        []secretstorage.KeyringStorageOption
+		expectedGet    bool
+		expectedSet    bool
+		expectedDelete bool
+	}{
+		{
+			scenario:    "get timeout",
+			keyring:     &sleepingKeyring{get: slow},
+			options:     []secretstorage.KeyringStorageOption{secretstorage.WithGetTimeout(short)},
+			expectedGet: true,
+		},
+		{
+			scenario:    "set timeout",
+			keyring:     &sleepingKeyring{set: slow},
+			options:     []secretstorage.KeyringStorageOption{secretstorage.WithSetTimeout(short)},
+			expectedSet: true,
+		},
+		{
+			scenario:       "delete timeout",
+			keyring:        &sleepingKeyring{delete: slow},
+			options:        []secretstorage.KeyringStorageOption{secretstorage.WithDeleteTimeout(short)},
+			expectedDelete: true,
+		},
+		{
+			scenario: "timeouts of the other operations",
+			keyring:  &sleepingKeyring{get: slow, set: slow, delete: slow},
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithOperationTimeout(short),
+				secretstorage.WithGetTimeout(generous),
+				secretstorage.WithSetTimeout(generous),
+				secretstorage.WithDeleteTimeout(generous),
+			},
+		},
+		{
+			scenario: "operation timeout",
+			keyring:  &sleepingKeyring{get: slow, set: slow, delete: slow},
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithOperationTimeout(short),
+			},
+			expectedGet:    true,
+			expectedSet:    true,
+			expectedDelete: true,
+		},
+		{
+			scenario: "get timeout overrides operation timeout",
+			keyring:  &sleepingKeyring{get: slow, set: slow},
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithOperationTimeout(short),
+				secretstorage.WithGetTimeout(generous),
+			},
+			expectedSet: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			tc.keyring.memoryKeyring = newMemoryKeyring()
+
+			require.NoError(t, tc.keyring.memoryKeyring.Set(t.Name(), "get-key", "value"))
+			require.NoError(t, tc.keyring.memoryKeyring.Set(t.Name(), "delete-key", "value"))
+
+			s := secretstorage.NewKeyringStorage[string](append([]secretstorage.KeyringStorageOption{secretstorage.WithKeyring(tc.keyring)}, tc.options...)...)
+
+			assertTimeout := func(t *testing.T, expected bool, err error) {
+				t.Helper()
+
+				if expected {
+					require.ErrorIs(t, err, context.DeadlineExceeded)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+
+			_, err := s.Get(t.Name(), "get-key")
+			assertTimeout(t, tc.expectedGet, err)
+
+			err = s.Set(t.Name(), "set-key", "value")
+			assertTimeout(t, tc.expectedSet, err)
+
+			err = s.Delete(t.Name(), "delete-key")
+			assertTimeout(t, tc.expectedDelete, err)
+		})
+	}
+}
+
+func TestKeyringStorage_Timeouts_PerPage(t *testing.T) {
+	t.Parallel()
+
+	k := &sleepingKeyring{memoryKeyring: newMemoryKeyring(), get: 10 * time.Millisecond}
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(k),
+		secretstorage.WithMaxLength(10),
+		secretstorage.WithGetTimeout(50*time.Millisecond),
+	)
+
+	value := randString(100)
+
+	require.NoError(t, s.Set(t.Name(), "get-key", value))
+
+	// The whole read takes longer than the timeout, each page does not.
+	actual, err := s.Get(t.Name(), "get-key")
+	require.NoError(t, err)
+	assert.Equal(t, value, actual)
+}
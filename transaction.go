@@ -0,0 +1,168 @@
+package secretstorage
+
+import (
+	"fmt"
+
+	"go.uber.org/multierr"
+)
+
+// Tx stages the operations of a transaction, see KeyringStorage.Transaction.
+type Tx[V any] interface {
+	// Set stages the value for the key.
+	Set(key string, value V)
+	// Delete stages the deletion of the key.
+	Delete(key string)
+}
+
+var _ Tx[any] = (*tx[any])(nil)
+
+type txOperation[V any] struct {
+	key    string
+	value  V
+	delete bool
+}
+
+type tx[V any] struct {
+	ops []txOperation[V]
+}
+
+func (t *tx[V]) Set(key string, value V) {
+	t.ops = append(t.ops, txOperation[V]{key: key, value: value})
+}
+
+func (t *tx[V]) Delete(key string) {
+	t.ops = append(t.ops, txOperation[V]{key: key, delete: true})
+}
+
+// txApplied is an operation that is applied, or attempted, with the data of its key before it, for the rollback.
+type txApplied struct {
+	key    string
+	old    string
+	hasOld bool
+}
+
+// Transaction stages the operations of the function, and applies them in order once it returns. Nothing is written if
+// the function fails, or if a value fails to marshal. If an operation fails, the operations that are applied are rolled
+// back in reverse order: the old data of their keys is restored, or the keys are deleted if they did not exist.
+//
+// The keyring has no transactions, so the rollback is best effort: the keys are locked one at a time, the other
+// operations may see the changes before the rollback, and the rollback itself may fail, its errors are returned
+// together with the error of the operation. Like WithVerifyWrite, the old data is restored without its labels if it is
+// multipart.
+//
+// Deleting a key that does not exist fails the transaction with ErrNotFound.
+func (ss *KeyringStorage[V]) Transaction(service string, fn func(tx Tx[V]) error) error {
+	defer ss.rlockConfig()()
+
+	service = ss.serviceOrDefault(service)
+
+	if ss.readOnly {
+		return ErrReadOnly
+	}
+
+	t := &tx[V]{}
+
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	// All the values are marshaled before anything is written.
+	data := make([]string, len(t.ops))
+
+	for i, op := range t.ops {
+		if op.delete {
+			continue
+		}
+
+		d, err := ss.encode(service, op.key, op.value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal data for writing to keyring: %w", tagError(ErrMarshal, err))
+		}
+
+		data[i] = d
+	}
+
+	applied := make([]txApplied, 0, len(t.ops))
+
+	for i, op := range t.ops {
+		a, err := ss.applyTxOperation(service, op, data[i])
+
+		// The operation that fails is rolled back too, because it may be partially applied.
+		if a != nil {
+			applied = append(applied, *a)
+		}
+
+		if err != nil {
+			return multierr.Append(ss.notFound(err), ss.rollbackTx(service, applied))
+		}
+	}
+
+	return nil
+}
+
+// applyTxOperation applies the operation, and returns the old data of its key, or nil if it could not be read.
+func (ss *KeyringStorage[V]) applyTxOperation(service string, op txOperation[V], d string) (*txApplied, error) {
+	mu := ss.mutex(service, op.key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	old, hasOld, err := ss.readOld(service, op.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read old data of %q for transaction: %w", op.key, err)
+	}
+
+	a := &txApplied{key: op.key, old: old, hasOld: hasOld}
+
+	if op.delete {
+		if _, err := ss.deleteValue(service, op.key); err != nil {
+			return a, fmt.Errorf("failed to delete %q in transaction: %w", op.key, err)
+		}
+
+		return a, nil
+	}
+
+	if ss.verifyWrite {
+		err = ss.setRawVerified(service, op.key, d, nil)
+	} else {
+		err = ss.setRaw(service, op.key, d, nil)
+	}
+
+	if err != nil {
+		return a, fmt.Errorf("failed to set %q in transaction: %w", op.key, err)
+	}
+
+	return a, nil
+}
+
+// rollbackTx restores the old data of the operations that are applied, in reverse order.
+func (ss *KeyringStorage[V]) rollbackTx(service string, applied []txApplied) error {
+	var err error
+
+	for i := len(applied) - 1; i >= 0; i-- {
+		a := applied[i]
+
+		if rErr := ss.rollbackTxOperation(service, a); rErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to roll back %q: %w", a.key, rErr))
+		}
+	}
+
+	return err
+}
+
+func (ss *KeyringStorage[V]) rollbackTxOperation(service string, a txApplied) error {
+	mu := ss.mutex(service, a.key)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := ss.rollbackWrite(service, a.key, a.old, a.hasOld); err != nil {
+		return err
+	}
+
+	if !a.hasOld {
+		ss.releaseEntries(service, a.key)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+)
+
+func TestKeyringStorage_Transaction(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	require.NoError(t, s.Set(t.Name(), "session", "old session"))
+
+	err := s.Transaction(t.Name(), func(tx secretstorage.Tx[string]) error {
+		tx.Set("username", "john")
+		tx.Set("password", "p4ssw0rd")
+		tx.Delete("session")
+
+		// Nothing is written until the function returns.
+		assert.Equal(t, map[string]string{"session": "old session"}, k.entries(t.Name()))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"username": "john", "password": "p4ssw0rd"}, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Transaction_FunctionFails(t *testing.T) {
+	t.Parallel()
+
+	k := newMemoryKeyring()
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+	err := s.Transaction(t.Name(), func(tx secretstorage.Tx[string]) error {
+		tx.Set("username", "john")
+
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	assert.Empty(t, k.entries(t.Name()))
+}
+
+func TestKeyringStorage_Transaction_Rollback(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		old           map[string]string
+		failOnce      string
+		ops           func(tx secretstorage.Tx[string])
+		expectedError string
+	}{
+		{
+			scenario: "second set fails, first key is deleted",
+			failOnce: "password",
+			ops: func(tx secretstorage.Tx[string]) {
+				tx.Set("username", "john")
+				tx.Set("password", "p4ssw0rd")
+			},
+			expectedError: `failed to set "password" in transaction: failed to write data to keyring: ` + assert.AnError.Error(),
+		},
+		{
+			scenario: "second set fails, first key is restored",
+			old:      map[string]string{"username": "jane"},
+			failOnce: "password",
+			ops: func(tx secretstorage.Tx[string]) {
+				tx.Set("username", "john")
+				tx.Set("password", "p4ssw0rd")
+			},
+			expectedError: `failed to set "password" in transaction: failed to write data to keyring: ` + assert.AnError.Error(),
+		},
+		{
+			scenario: "second set fails, both keys are restored",
+			old:      map[string]string{"username": "jane", "password": "s3cr3t"},
+			failOnce: "password",
+			ops: func(tx secretstorage.Tx[string]) {
+				tx.Set("username", "john")
+				tx.Set("password", "p4ssw0rd")
+			},
+			expectedError: `failed to set "password" in transaction: failed to write data to keyring: ` + assert.AnError.Error(),
+		},
+		{
+			scenario: "multipart set fails, deleted key is restored",
+			old:      map[string]string{"username": "jane", "session": "old session"},
+			failOnce: "token-0002",
+			ops: func(tx secretstorage.Tx[string]) {
+				tx.Set("username", "john")
+				tx.Delete("session")
+				tx.Set("token", randString(5000))
+			},
+			expectedError: `failed to set "token" in transaction: failed to write multipart data #2 to keyring: ` + assert.AnError.Error(),
+		},
+		{
+			scenario: "deleted key does not exist",
+			old:      map[string]string{"username": "jane"},
+			ops: func(tx secretstorage.Tx[string]) {
+				tx.Set("username", "john")
+				tx.Delete("session")
+			},
+			expectedError: `failed to delete "session" in transaction: failed to delete data in keyring: secret not found in keyring`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := newMemoryKeyring()
+
+			for key, value := range tc.old {
+				require.NoError(t, m.Set(t.Name(), key, value))
+			}
+
+			k := &flakyKeyring{Keyring: m, failOnce: tc.failOnce}
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k))
+
+			err := s.Transaction(t.Name(), func(tx secretstorage.Tx[string]) error {
+				tc.ops(tx)
+
+				return nil
+			})
+
+			require.EqualError(t, err, tc.expectedError)
+
+			expected := tc.old
+			if expected == nil {
+				expected = map[string]string{}
+			}
+
+			assert.Equal(t, expected, m.entries(t.Name()))
+		})
+	}
+}
+
+func TestKeyringStorage_Transaction_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(newMemoryKeyring()), secretstorage.WithReadOnly())
+
+	err := s.Transaction(t.Name(), func(tx secretstorage.Tx[string]) error {
+		tx.Set("username", "john")
+
+		return nil
+	})
+
+	require.ErrorIs(t, err, secretstorage.ErrReadOnly)
+}
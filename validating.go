@@ -0,0 +1,91 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrValidation indicates that a value does not pass the validation of a ValidatingStorage.
+var ErrValidation = errors.New("validation failed")
+
+var (
+	_ Storage[any] = (*ValidatingStorage[any])(nil)
+	_ Flusher      = (*ValidatingStorage[any])(nil)
+)
+
+// ValidatingStorage validates the values before writing them to another storage, so that the invalid values never land
+// in it. The values that are read can be validated too, see WithValidationOnGet.
+type ValidatingStorage[V any] struct {
+	storage  Storage[V]
+	validate func(V) error
+
+	validateOnGet bool
+}
+
+// Get gets the value from the storage, and validates it if WithValidationOnGet is set.
+func (s *ValidatingStorage[V]) Get(service string, key string) (V, error) {
+	v, err := s.storage.Get(service, key)
+	if err != nil || !s.validateOnGet {
+		return v, err //nolint: wrapcheck
+	}
+
+	if err := s.validate(v); err != nil {
+		var zero V
+
+		return zero, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	return v, nil
+}
+
+// Set validates the value, and sets it in the storage if it is valid.
+func (s *ValidatingStorage[V]) Set(service string, key string, value V) error {
+	if err := s.validate(value); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	return s.storage.Set(service, key, value) //nolint: wrapcheck
+}
+
+// Delete deletes the value in the storage.
+func (s *ValidatingStorage[V]) Delete(service string, key string) error {
+	return s.storage.Delete(service, key) //nolint: wrapcheck
+}
+
+// Flush flushes the decorated storage.
+func (s *ValidatingStorage[V]) Flush() error {
+	return flush(s.storage)
+}
+
+// NewValidatingStorage creates a new ValidatingStorage.
+func NewValidatingStorage[V any](storage Storage[V], validate func(V) error, opts ...ValidatingStorageOption[V]) *ValidatingStorage[V] {
+	s := &ValidatingStorage[V]{
+		storage:  storage,
+		validate: validate,
+	}
+
+	for _, opt := range opts {
+		opt.applyValidatingStorageOption(s)
+	}
+
+	return s
+}
+
+// ValidatingStorageOption is an option to configure ValidatingStorage.
+type ValidatingStorageOption[V any] interface {
+	applyValidatingStorageOption(s *ValidatingStorage[V])
+}
+
+type validatingStorageOptionFunc[V any] func(s *ValidatingStorage[V])
+
+func (f validatingStorageOptionFunc[V]) applyValidatingStorageOption(s *ValidatingStorage[V]) {
+	f(s)
+}
+
+// WithValidationOnGet validates the values that are read too, to catch the data that was corrupted outside of the
+// storage. The invalid values are not returned.
+func WithValidationOnGet[V any]() ValidatingStorageOption[V] {
+	return validatingStorageOptionFunc[V](func(s *ValidatingStorage[V]) {
+		s.validateOnGet = true
+	})
+}
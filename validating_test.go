@@ -0,0 +1,164 @@
+package secretstorage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/mock"
+)
+
+var errEmptyValue = errors.New("value is empty")
+
+func validateNotEmpty(v string) error {
+	if v == "" {
+		return errEmptyValue
+	}
+
+	return nil
+}
+
+func TestValidatingStorage_Set(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		mockStorage   mock.StorageMocker[string]
+		value         string
+		expectedError string
+	}{
+		{
+			scenario:      "invalid value",
+			mockStorage:   mock.MockStorage[string](),
+			expectedError: "validation failed: value is empty",
+		},
+		{
+			scenario: "storage error",
+			mockStorage: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(assert.AnError)
+			}),
+			value:         "value",
+			expectedError: "assert.AnError general error for testing",
+		},
+		{
+			scenario: "success",
+			mockStorage: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Set", "service", "key", "value").Return(nil)
+			}),
+			value: "value",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := secretstorage.NewValidatingStorage[string](tc.mockStorage(t), validateNotEmpty)
+
+			err := s.Set("service", "key", tc.value)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidatingStorage_Set_ErrorIs(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewValidatingStorage[string](mock.MockStorage[string]()(t), validateNotEmpty)
+
+	err := s.Set("service", "key", "")
+
+	require.ErrorIs(t, err, secretstorage.ErrValidation)
+	require.ErrorIs(t, err, errEmptyValue)
+}
+
+func TestValidatingStorage_Get(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		mockStorage    mock.StorageMocker[string]
+		validateOnGet  bool
+		expectedResult string
+		expectedError  string
+	}{
+		{
+			scenario: "storage error",
+			mockStorage: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("", assert.AnError)
+			}),
+			validateOnGet: true,
+			expectedError: "assert.AnError general error for testing",
+		},
+		{
+			scenario: "invalid value is not validated",
+			mockStorage: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("", nil)
+			}),
+		},
+		{
+			scenario: "invalid value",
+			mockStorage: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("", nil)
+			}),
+			validateOnGet: true,
+			expectedError: "validation failed: value is empty",
+		},
+		{
+			scenario: "valid value",
+			mockStorage: mock.MockStorage(func(s *mock.Storage[string]) {
+				s.On("Get", "service", "key").Return("value", nil)
+			}),
+			validateOnGet:  true,
+			expectedResult: "value",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []secretstorage.ValidatingStorageOption[string]
+
+			if tc.validateOnGet {
+				opts = append(opts, secretstorage.WithValidationOnGet[string]())
+			}
+
+			s := secretstorage.NewValidatingStorage[string](tc.mockStorage(t), validateNotEmpty, opts...)
+
+			actual, err := s.Get("service", "key")
+
+			assert.Equal(t, tc.expectedResult, actual)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidatingStorage_Delete(t *testing.T) {
+	t.Parallel()
+
+	s := secretstorage.NewValidatingStorage[string](mock.MockStorage(func(s *mock.Storage[string]) {
+		s.On("Delete", "service", "key").Return(assert.AnError)
+	})(t), validateNotEmpty)
+
+	err := s.Delete("service", "key")
+
+	require.ErrorIs(t, err, assert.AnError)
+}
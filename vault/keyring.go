@@ -0,0 +1,257 @@
+// Package vault provides a keyring that stores the secrets in the KV version 2 secrets engine of HashiCorp Vault.
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+var (
+	_ keyring.Keyring      = (*Keyring)(nil)
+	_ secretstorage.Lister = (*Keyring)(nil)
+)
+
+// ErrUnexpectedStatus indicates that Vault responded with an unexpected status code.
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+const valueField = "value"
+
+// Keyring is a keyring that stores the secrets in the KV version 2 secrets engine of HashiCorp Vault, over its HTTP
+// API. A secret is stored at "<mount>/data/<prefix>/<service>/<user>", in the "value" field. The slashes in the service
+// and the user are not escaped by Vault, they create nested paths.
+//
+// Vault does not limit the size of the entries like the OS keyrings do, so the storage can be configured with a higher
+// max length to store the values in a single entry, see secretstorage.WithMaxLength.
+type Keyring struct {
+	address string
+	token   string
+	mount   string
+	prefix  string
+	client  *http.Client
+}
+
+// NewKeyring creates a new Keyring that calls the Vault server at the address, for example "https://vault:8200", with
+// the token. By default, the secrets engine is mounted at "secret", and the secrets are stored without prefix.
+func NewKeyring(address string, token string, opts ...Option) *Keyring {
+	k := &Keyring{
+		address: strings.TrimRight(address, "/"),
+		token:   token,
+		mount:   "secret",
+		client:  http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt.applyOption(k)
+	}
+
+	return k
+}
+
+// Get gets the secret.
+func (k *Keyring) Get(service, user string) (string, error) {
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+
+	if err := k.do(http.MethodGet, k.url("data", service, user), nil, &body); err != nil {
+		return "", err
+	}
+
+	v, ok := body.Data.Data[valueField]
+	if !ok {
+		return "", secretstorage.ErrNotFound
+	}
+
+	return v, nil
+}
+
+// Set sets the secret.
+func (k *Keyring) Set(service, user, password string) error {
+	body := map[string]any{
+		"data": map[string]string{valueField: password},
+	}
+
+	return k.do(http.MethodPost, k.url("data", service, user), body, nil)
+}
+
+// Delete deletes the secret with all its versions. It returns secretstorage.ErrNotFound if the secret does not exist.
+func (k *Keyring) Delete(service, user string) error {
+	// Vault does not tell whether the secret existed.
+	if _, err := k.Get(service, user); err != nil {
+		return err
+	}
+
+	return k.do(http.MethodDelete, k.url("metadata", service, user), nil, nil)
+}
+
+// DeleteAll deletes all the secrets of the service.
+func (k *Keyring) DeleteAll(service string) error {
+	users, err := k.List(service)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := k.do(http.MethodDelete, k.url("metadata", service, user), nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List lists the secrets of the service.
+func (k *Keyring) List(service string) ([]string, error) {
+	var body struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+
+	err := k.do("LIST", k.url("metadata", service, "")+"/", nil, &body)
+	if errors.Is(err, secretstorage.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]string, 0, len(body.Data.Keys))
+
+	for _, key := range body.Data.Keys {
+		// The folders are the services under the prefix of another service.
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+
+		users = append(users, key)
+	}
+
+	return users, nil
+}
+
+func (k *Keyring) url(kind, service, user string) string {
+	segments := []string{k.address, "v1", k.mount, kind}
+
+	if k.prefix != "" {
+		segments = append(segments, k.prefix)
+	}
+
+	segments = append(segments, url.PathEscape(service))
+
+	if user != "" {
+		segments = append(segments, url.PathEscape(user))
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// do sends the request, and decodes the response into out, if any. The not found status is mapped to
+// secretstorage.ErrNotFound.
+func (k *Keyring) do(method, u string, in any, out any) error {
+	var body io.Reader
+
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u, body) //nolint: noctx
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", k.token)
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call vault: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return secretstorage.ErrNotFound
+
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return statusError(resp)
+
+	case out == nil || resp.StatusCode == http.StatusNoContent:
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// statusError returns ErrUnexpectedStatus with the errors reported by Vault, if any.
+func statusError(resp *http.Response) error {
+	var body struct {
+		Errors []string `json:"errors"`
+	}
+
+	_ = json.NewDecoder(resp.Body).Decode(&body) //nolint: errcheck
+
+	if len(body.Errors) == 0 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return fmt.Errorf("%w: %d: %s", ErrUnexpectedStatus, resp.StatusCode, strings.Join(body.Errors, ", "))
+}
+
+// Option is an option to configure Keyring.
+type Option interface {
+	applyOption(k *Keyring)
+}
+
+type optionFunc func(k *Keyring)
+
+func (f optionFunc) applyOption(k *Keyring) {
+	f(k)
+}
+
+// WithMount sets the path where the KV version 2 secrets engine is mounted. The default is "secret".
+func WithMount(mount string) Option {
+	return optionFunc(func(k *Keyring) {
+		k.mount = strings.Trim(mount, "/")
+	})
+}
+
+// WithPathPrefix sets the path under which the services are stored, for example "apps/my-app".
+func WithPathPrefix(prefix string) Option {
+	return optionFunc(func(k *Keyring) {
+		k.prefix = strings.Trim(prefix, "/")
+	})
+}
+
+// WithHTTPClient sets the HTTP client that calls Vault. The default is http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return optionFunc(func(k *Keyring) {
+		k.client = c
+	})
+}
@@ -0,0 +1,228 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/secretstorage"
+	"go.nhat.io/secretstorage/vault"
+)
+
+const testToken = "s.token"
+
+// mockVault is a minimal KV version 2 secrets engine mounted at /v1/secret.
+type mockVault struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+}
+
+func (v *mockVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != testToken {
+		writeJSON(w, http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+
+		return
+	}
+
+	kind, path, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/secret/"), "/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	switch {
+	case kind == "data" && r.Method == http.MethodGet:
+		s, ok := v.secrets[path]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"data": s}})
+
+	case kind == "data" && r.Method == http.MethodPost:
+		var body struct {
+			Data map[string]string `json:"data"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		v.secrets[path] = body.Data
+
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"version": 1}})
+
+	case kind == "metadata" && r.Method == http.MethodDelete:
+		delete(v.secrets, path)
+
+		w.WriteHeader(http.StatusNoContent)
+
+	case kind == "metadata" && r.Method == "LIST":
+		keys := make(map[string]struct{})
+
+		for p := range v.secrets {
+			if rest, ok := strings.CutPrefix(p, path); ok {
+				if i := strings.IndexByte(rest, '/'); i >= 0 {
+					rest = rest[:i+1]
+				}
+
+				keys[rest] = struct{}{}
+			}
+		}
+
+		if len(keys) == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+
+			return
+		}
+
+		result := make([]string, 0, len(keys))
+
+		for k := range keys {
+			result = append(result, k)
+		}
+
+		sort.Strings(result)
+
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"keys": result}})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (v *mockVault) paths() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	result := make([]string, 0, len(v.secrets))
+
+	for p := range v.secrets {
+		result = append(result, p)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body) //nolint: errcheck
+}
+
+func newMockVault(t *testing.T) (*mockVault, string) {
+	t.Helper()
+
+	v := &mockVault{secrets: make(map[string]map[string]string)}
+	srv := httptest.NewServer(v)
+
+	t.Cleanup(srv.Close)
+
+	return v, srv.URL
+}
+
+func TestKeyring(t *testing.T) {
+	t.Parallel()
+
+	v, addr := newMockVault(t)
+	k := vault.NewKeyring(addr, testToken, vault.WithPathPrefix("/apps/my-app/"))
+
+	_, err := k.Get("service", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	err = k.Delete("service", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, k.Set("service", "key", "value"))
+	require.NoError(t, k.Set("service", "another key", "another value"))
+	require.NoError(t, k.Set("service/nested", "key", "value"))
+
+	assert.Equal(t, []string{
+		"apps/my-app/service/another key",
+		"apps/my-app/service/key",
+		"apps/my-app/service/nested/key",
+	}, v.paths())
+
+	actual, err := k.Get("service", "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", actual)
+
+	keys, err := k.List("service")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"another key", "key"}, keys)
+
+	keys, err = k.List("unknown")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	require.NoError(t, k.Delete("service", "key"))
+
+	_, err = k.Get("service", "key")
+	require.ErrorIs(t, err, secretstorage.ErrNotFound)
+
+	require.NoError(t, k.DeleteAll("service"))
+
+	assert.Equal(t, []string{"apps/my-app/service/nested/key"}, v.paths())
+}
+
+func TestKeyring_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	_, addr := newMockVault(t)
+	k := vault.NewKeyring(addr, "invalid")
+
+	_, err := k.Get("service", "key")
+
+	require.ErrorIs(t, err, vault.ErrUnexpectedStatus)
+	require.EqualError(t, err, "unexpected status: 403: permission denied")
+
+	err = k.Set("service", "key", "value")
+
+	require.ErrorIs(t, err, vault.ErrUnexpectedStatus)
+}
+
+func TestKeyring_Storage(t *testing.T) {
+	t.Parallel()
+
+	v, addr := newMockVault(t)
+	s := secretstorage.NewKeyringStorage[string](
+		secretstorage.WithKeyring(vault.NewKeyring(addr, testToken)),
+		secretstorage.WithMaxLength(1<<20),
+	)
+
+	value := strings.Repeat("0123456789", 1000)
+
+	require.NoError(t, s.Set("service", "key", value))
+
+	// The value is stored in a single entry.
+	assert.Equal(t, []string{"service/key"}, v.paths())
+
+	actual, err := s.Get("service", "key")
+	require.NoError(t, err)
+	assert.Equal(t, value, actual)
+
+	all, err := s.GetAll("service")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": value}, all)
+
+	require.NoError(t, s.Delete("service", "key"))
+	assert.Empty(t, v.paths())
+}
@@ -0,0 +1,94 @@
+package secretstorage
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.uber.org/multierr"
+)
+
+// ErrWriteVerificationFailed indicates that the data read back after a write is not the data that was written.
+var ErrWriteVerificationFailed = errors.New("write verification failed")
+
+func (ss *KeyringStorage[V]) withVerifyWrite() {
+	ss.verifyWrite = true
+}
+
+// WithVerifyWrite makes Set read the data back after writing it, and compare it with the marshaled value, to catch the
+// corruptions at write time rather than on a later read. If they differ, the old data is restored, or the new data is
+// deleted if there was no old data, and ErrWriteVerificationFailed is returned. The old data that can not be read is
+// not restored.
+//
+// The old data is read before the write for the rollback, so Set calls the keyring about twice as much.
+func WithVerifyWrite() KeyringStorageOption {
+	return keyringStorageOptionFunc(func(ss configurableKeyringStorage) {
+		ss.withVerifyWrite()
+	})
+}
+
+// setRawVerified is like setRaw, and reads the data back to verify it.
+func (ss *KeyringStorage[V]) setRawVerified(service string, key string, d string, labels map[string]string) error {
+	// The old data that can not be read, such as a corrupt multipart data, is not restored, the write is still
+	// verified, so that a broken value can be fixed with Set.
+	old, hasOld, err := ss.readOld(service, key)
+	canRollback := err == nil
+
+	if !canRollback {
+		slog.Debug("failed to read old data for write verification", "service", service, "key", key, "error", err)
+	}
+
+	if err := ss.setRaw(service, key, d, labels); err != nil {
+		return err
+	}
+
+	actual, err := ss.getRaw(service, key)
+	if err == nil && actual == d {
+		return nil
+	}
+
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrWriteVerificationFailed, err)
+	} else {
+		err = fmt.Errorf("%w: the data read back is not the data written", ErrWriteVerificationFailed)
+	}
+
+	if !canRollback {
+		return err
+	}
+
+	return multierr.Append(err, ss.rollbackWrite(service, key, old, hasOld))
+}
+
+// readOld reads the data before a write for rollbackWrite. The boolean is false if there is no data.
+func (ss *KeyringStorage[V]) readOld(service string, key string) (string, bool, error) {
+	// The references are restored as is, they are not followed.
+	old, err := ss.keyring.Get(service, key)
+	if errors.Is(err, ErrNotFound) {
+		return "", false, nil
+	}
+
+	// A missing page is not a missing data, the data can not be read.
+	if err == nil && isMultipart(old) {
+		old, err = decodeMultipart(ss.keyring, ss.pageFormat, ss.readsPerCall(), service, key, old)
+	}
+
+	return old, err == nil, err
+}
+
+// rollbackWrite restores the old data, or deletes the data if there was no old data.
+func (ss *KeyringStorage[V]) rollbackWrite(service string, key string, old string, hasOld bool) error {
+	if hasOld {
+		if err := ss.writeRaw(service, key, old, nil); err != nil {
+			return fmt.Errorf("failed to restore old data: %w", err)
+		}
+
+		return nil
+	}
+
+	if _, err := ss.delete(service, key); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to delete new data: %w", err)
+	}
+
+	return nil
+}
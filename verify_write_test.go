@@ -0,0 +1,155 @@
+package secretstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+
+	"go.nhat.io/secretstorage"
+)
+
+// corruptingKeyring corrupts an entry when it is read.
+type corruptingKeyring struct {
+	keyring.Keyring
+
+	corrupt string
+}
+
+func (k *corruptingKeyring) Get(service, user string) (string, error) {
+	v, err := k.Keyring.Get(service, user)
+	if err == nil && v == k.corrupt {
+		v += "!"
+	}
+
+	return v, err
+}
+
+func TestKeyringStorage_VerifyWrite(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		options       []secretstorage.KeyringStorageOption
+		old           string
+		corrupt       string
+		expectedError string
+		expected      map[string]string
+	}{
+		{
+			scenario: "not verified",
+			corrupt:  "new",
+			expected: map[string]string{"key": "new"},
+		},
+		{
+			scenario: "verified",
+			options:  []secretstorage.KeyringStorageOption{secretstorage.WithVerifyWrite()},
+			old:      "old",
+			expected: map[string]string{"key": "new"},
+		},
+		{
+			scenario:      "corrupted, old data is restored",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithVerifyWrite()},
+			old:           "old",
+			corrupt:       "new",
+			expectedError: "write verification failed: the data read back is not the data written",
+			expected:      map[string]string{"key": "old"},
+		},
+		{
+			scenario:      "corrupted, new data is deleted",
+			options:       []secretstorage.KeyringStorageOption{secretstorage.WithVerifyWrite()},
+			corrupt:       "new",
+			expectedError: "write verification failed: the data read back is not the data written",
+			expected:      map[string]string{},
+		},
+		{
+			scenario: "corrupted multipart, old multipart data is restored",
+			options: []secretstorage.KeyringStorageOption{
+				secretstorage.WithVerifyWrite(),
+				secretstorage.WithMaxLength(2),
+			},
+			old:           "old",
+			corrupt:       "w",
+			expectedError: "write verification failed: the data read back is not the data written",
+			expected: map[string]string{
+				"key":      "application/multipart-secret; pages=2",
+				"key-0001": "ol",
+				"key-0002": "d",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := newMemoryKeyring()
+			k := &corruptingKeyring{Keyring: m, corrupt: tc.corrupt}
+			s := secretstorage.NewKeyringStorage[string](append(tc.options, secretstorage.WithKeyring(k))...)
+
+			if tc.old != "" {
+				require.NoError(t, s.Set(t.Name(), "key", tc.old))
+			}
+
+			err := s.Set(t.Name(), "key", "new")
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, secretstorage.ErrWriteVerificationFailed)
+				require.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expected, m.entries(t.Name()))
+		})
+	}
+}
+
+func TestKeyringStorage_VerifyWrite_UnreadableOldData(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		corrupt       string
+		expectedError string
+	}{
+		{
+			scenario: "verified",
+		},
+		{
+			scenario:      "corrupted, nothing to restore",
+			corrupt:       "new",
+			expectedError: "write verification failed: the data read back is not the data written",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := newMemoryKeyring()
+			k := &corruptingKeyring{Keyring: m, corrupt: tc.corrupt}
+			s := secretstorage.NewKeyringStorage[string](secretstorage.WithKeyring(k), secretstorage.WithVerifyWrite())
+
+			// The old data misses its second page, it can not be read for the rollback.
+			require.NoError(t, m.Set(t.Name(), "key", "application/multipart-secret; pages=2"))
+			require.NoError(t, m.Set(t.Name(), "key-0001", "ol"))
+
+			err := s.Set(t.Name(), "key", "new")
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, secretstorage.ErrWriteVerificationFailed)
+				require.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, map[string]string{"key": "new"}, m.entries(t.Name()))
+		})
+	}
+}